@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -10,13 +11,17 @@ import (
 	"onthemat/internal/app/config"
 	"onthemat/internal/app/delivery/http"
 	"onthemat/internal/app/delivery/middlewares"
+	"onthemat/internal/app/delivery/scheduler"
 	"onthemat/internal/app/infrastructure"
 	"onthemat/internal/app/repository"
 	"onthemat/internal/app/service"
 	"onthemat/internal/app/service/token"
 	"onthemat/internal/app/transport"
 	"onthemat/internal/app/usecase"
+	"onthemat/pkg/apple"
 	"onthemat/pkg/auth/jwt"
+	"onthemat/pkg/auth/password"
+	"onthemat/pkg/auth/secretbox"
 	"onthemat/pkg/auth/store/redis"
 	"onthemat/pkg/aws"
 
@@ -24,7 +29,9 @@ import (
 	"onthemat/pkg/google"
 	"onthemat/pkg/kakao"
 	"onthemat/pkg/naver"
+	"onthemat/pkg/oauth"
 	"onthemat/pkg/openapi"
+	"onthemat/pkg/sms"
 	"onthemat/pkg/validatorx"
 
 	"github.com/goccy/go-json"
@@ -49,12 +56,53 @@ func main() {
 	}
 
 	// pkg
-	jwt := jwt.NewJwt().WithSignKey(c.JWT.SignKey).Init()
+	if c.JWT.PrivateKeyFile == "" && c.JWT.SignKey == "" {
+		panic("JWT_PRIVATE_KEY_FILE 또는 JWT_SignKey 를 설정해야 합니다")
+	}
+	jwtOption := jwt.NewJwt()
+	if c.JWT.PrivateKeyFile == "" {
+		jwtOption.WithSignKey(c.JWT.SignKey)
+	} else if c.JWT.LegacyHMAC {
+		// 키 교체 기간에만 기존 HMAC 토큰을 받는다.
+		if c.JWT.SignKey == "" || c.JWT.LegacyHMACUntil.IsZero() {
+			panic("JWT_LEGACY_HMAC 을 켜려면 JWT_SignKey 와 JWT_LEGACY_HMAC_UNTIL 을 설정해야 합니다")
+		}
+		jwtOption.WithSignKey(c.JWT.SignKey).WithHMACUntil(c.JWT.LegacyHMACUntil)
+	}
+	if c.JWT.PrivateKeyFile != "" {
+		privateKey, err := jwt.LoadPrivateKey(c.JWT.PrivateKeyFile)
+		if err != nil {
+			panic(err)
+		}
+		jwtOption.WithPrivateKey("", privateKey)
+	}
+	for _, path := range c.JWT.VerifyKeyFiles {
+		publicKey, err := jwt.LoadPublicKey(path)
+		if err != nil {
+			panic(err)
+		}
+		jwtOption.WithVerifyKey("", publicKey)
+	}
+	jwt := jwtOption.Init()
 	tokenModule := token.NewToken(jwt)
-	k := kakao.NewKakao(c)
-	g := google.NewGoogle(c)
-	n := naver.NewNaver(c)
-	emailM := email.NewEmail(c)
+	// 소셜 로그인 업체
+	socialProviders := []oauth.SocialProvider{kakao.NewKakao(c), google.NewGoogle(c), naver.NewNaver(c)}
+	if c.Oauth.AppleClientId != "" {
+		appleKey, err := apple.LoadPrivateKey(c.Oauth.ApplePrivateKeyFile)
+		if err != nil {
+			panic(err)
+		}
+		socialProviders = append(socialProviders, apple.NewApple(c, appleKey))
+	}
+	socials := oauth.NewRegistry(socialProviders...)
+	smsSender, err := sms.NewSender(c)
+	if err != nil {
+		panic(err)
+	}
+	emailSender, err := email.NewSender(c)
+	if err != nil {
+		panic(err)
+	}
 
 	// ------- s3 ----------
 	s3 := aws.NewS3(c)
@@ -81,6 +129,8 @@ func main() {
 
 	// repo
 	userRepo := repository.NewUserRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	emailOutboxRepo := repository.NewEmailOutboxRepository(db)
 	imageRepo := repository.NewImageRepository(db)
 	academyRepo := repository.NewAcademyRepository(db)
 	areaRepo := repository.NewAreaRepository(db)
@@ -89,29 +139,62 @@ func main() {
 	recruitmentRepo := repository.NewRecruitmentRepository(db)
 
 	// service
-	authSvc := service.NewAuthService(k, g, n, emailM)
+	totpBox, err := secretbox.NewBox(c.Secret.TotpKey)
+	if err != nil {
+		panic("TOTP_ENCRYPTION_KEY 를 설정해야 합니다 (openssl rand -base64 32)")
+	}
+	authSvc := service.NewAuthService(password.NewHasher(password.DefaultParams, c.Secret.Password))
+	mailSvc := service.NewMailService(emailSender)
 	authStore := redis.NewStore(redisCli)
+	sessionChecker := token.NewSessionChecker(authStore, time.Duration(c.JWT.SessionCacheTTL)*time.Second)
 	academySvc := service.NewAcademyService(businessManM)
 
 	// usecase
-	authUseCase := usecase.NewAuthUseCase(tokenModule, userRepo, authSvc, authStore, c)
-	userUsecase := usecase.NewUserUseCase(userRepo)
+	authUseCase := usecase.NewAuthUseCase(tokenModule, userRepo, userIdentityRepo, emailOutboxRepo, authSvc, socials, authStore, sessionChecker, totpBox, c)
+	userUsecase := usecase.NewUserUseCase(userRepo, authStore, smsSender)
 	academyUsecase := usecase.NewAcademyUsecase(academyRepo, academySvc, userRepo, yogaRepo, areaRepo)
 	uploadUsecase := usecase.NewUploadUsecase(imageRepo, s3)
 	yogaUsecase := usecase.NewYogaUsecase(yogaRepo, academyRepo, teacherRepo)
 	teacherUsecase := usecase.NewTeacherUsecase(teacherRepo, userRepo)
 	recruitmentUsecase := usecase.NewRecruitmentUsecase(recruitmentRepo)
+	calendarUsecase := usecase.NewCalendarUsecase(userRepo, teacherRepo, academyRepo, recruitmentRepo)
+	emailOutboxUsecase := usecase.NewEmailOutboxUsecase(emailOutboxRepo, mailSvc)
 	// middleware
-	middleWare := middlewares.NewMiddelwWare(authSvc, tokenModule, teacherRepo, academyRepo)
+	middleWare := middlewares.NewMiddelwWare(authSvc, tokenModule, sessionChecker, userRepo, teacherRepo, academyRepo, authStore, c)
+
+	// scheduler
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	if c.Scheduler.Enabled {
+		jobScheduler := scheduler.NewScheduler(authStore)
+		for _, job := range []scheduler.Job{
+			scheduler.RecruitmentExpireJob(recruitmentUsecase, time.Duration(c.Scheduler.RecruitmentExpireInterval)*time.Minute),
+			scheduler.UserAnonymizeJob(authUseCase, time.Duration(c.Scheduler.UserAnonymizeInterval)*time.Minute),
+			scheduler.EmailOutboxJob(emailOutboxUsecase, time.Duration(c.Scheduler.EmailOutboxInterval)*time.Second),
+		} {
+			// 주기가 0 이하인 작업은 끈다.
+			if job.Interval > 0 {
+				jobScheduler.Start(schedulerCtx, job)
+			}
+		}
+	}
 
 	defer func() {
+		stopScheduler()
 		infrastructure.ClosePostgres(db)
 	}()
 	// app
 
+	// 아무나 헤더로 IP 를 바꿔 요청 제한, 로그인 잠금을 피하지 못하도록 믿을 수 있는 프록시에서 온 헤더만 사용한다.
+	if c.Onthemat.ProxyHeader != "" && len(c.Onthemat.TrustedProxies) == 0 {
+		panic("ONETHEMAT_PROXY_HEADER 를 사용하려면 ONETHEMAT_TRUSTED_PROXIES 를 설정해야 합니다")
+	}
 	app := fiber.New(fiber.Config{
-		JSONEncoder: json.Marshal,
-		JSONDecoder: json.Unmarshal,
+		JSONEncoder:             json.Marshal,
+		JSONDecoder:             json.Unmarshal,
+		ProxyHeader:             c.Onthemat.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          c.Onthemat.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
 	fiber.SetParserDecoder(fiber.ParserConfig{
@@ -133,16 +216,15 @@ func main() {
 	// Default config
 	app.Use(cors.New())
 
-	// app.Use(limiter.New(limiter.ConfigDefault))
-
 	// handler
-	router := app.Group("/api/v1")
-	http.NewAuthHandler(authUseCase, validator, router)
+	router := app.Group("/api/v1", middleWare.RateLimit(middlewares.RateLimitDefault))
+	http.NewAuthHandler(middleWare, authUseCase, validator, router)
 	http.NewUploadHandler(middleWare, uploadUsecase, validator, router)
-	http.NewUserHandler(middleWare, userUsecase, router)
+	http.NewUserHandler(middleWare, userUsecase, authUseCase, validator, router)
 	http.NewAcademyHandler(middleWare, academyUsecase, validator, router)
 	http.NewYogaHandler(yogaUsecase, middleWare, validator, router)
 	http.NewTeacherHandler(middleWare, teacherUsecase, validator, router)
 	http.NewRecruitmentHandler(middleWare, recruitmentUsecase, validator, router)
+	http.NewCalendarHandler(middleWare, calendarUsecase, router)
 	app.Listen(":8000")
 }
@@ -33,27 +33,54 @@ const (
 	ErrBusinessCodeInvalid                  = 3008
 	ErrColumnInvalid                        = 3009
 	ErrYogaIdsInvliad                       = 3010
+	ErrRecurrenceInvalid                    = 3011
+	ErrPasswordResetTokenInvalid            = 3012
+	ErrPasswordMismatch                     = 3013
+	ErrRestoreTokenInvalid                  = 3014
+	ErrSocialLinkTokenInvalid               = 3015
+	ErrOAuthStateInvalid                    = 3016
+	ErrPhoneVerifyCodeInvalid               = 3017
+	ErrPhoneVerifyCodeExpired               = 3018
+	ErrTotpCodeInvalid                      = 3019
+	ErrTwoFactorChallengeInvalid            = 3020
+	ErrTwoFactorSetupExpired                = 3021
 
 	// 4000 ~ Conflict
-	ErrConflict                 = 4000
-	ErrUserEmailAlreadyExist    = 4001
-	ErrUserEmailAlreadyVerfied  = 4002
-	ErrUserTypeAlreadyRegisted  = 4003
-	ErrUserEmailAlreadyRegisted = 4004
-	ErrYogaGroupAlreadyExist    = 4005
-	ErrYogaAlreadyRegisted      = 4006
-	ErrYogaDoseNotExist         = 4007
-	ErrYogaGroupDoesNotExist    = 4008
-	ErrSigunguDoseNotExist      = 4009
-	ErrResourceUnOwned          = 4010
+	ErrConflict                    = 4000
+	ErrUserEmailAlreadyExist       = 4001
+	ErrUserEmailAlreadyVerfied     = 4002
+	ErrUserTypeAlreadyRegisted     = 4003
+	ErrUserEmailAlreadyRegisted    = 4004
+	ErrYogaGroupAlreadyExist       = 4005
+	ErrYogaAlreadyRegisted         = 4006
+	ErrYogaDoseNotExist            = 4007
+	ErrYogaGroupDoesNotExist       = 4008
+	ErrSigunguDoseNotExist         = 4009
+	ErrResourceUnOwned             = 4010
+	ErrAlreadyApplied              = 4011
+	ErrRecruitmentAlreadyClosed    = 4012
+	ErrApplicationStatusInvalid    = 4013
+	ErrInsteadAlreadyFilled        = 4014
+	ErrScheduleConflict            = 4015
+	ErrSocialLinkRequired          = 4016
+	ErrSocialAlreadyLinked         = 4017
+	ErrSocialProviderAlreadyLinked = 4018
+	ErrLastLoginMethod             = 4019
+	ErrTwoFactorAlreadyEnabled     = 4020
+	ErrTwoFactorNotEnabled         = 4021
 
 	// 5000 ~ NotFound
-	ErrUserNotFound        = 5001
-	ErrUserEmailNotFound   = 5002
-	ErrAcademyNotFound     = 5003
-	ErrAreaNotFound        = 5004
-	ErrTeacherNotFound     = 5005
-	ErrRecruitmentNotFound = 5006
+	ErrUserNotFound           = 5001
+	ErrUserEmailNotFound      = 5002
+	ErrAcademyNotFound        = 5003
+	ErrAreaNotFound           = 5004
+	ErrTeacherNotFound        = 5005
+	ErrRecruitmentNotFound    = 5006
+	ErrInsteadNotFound        = 5007
+	ErrApplicantNotFound      = 5008
+	ErrScheduleNotFound       = 5009
+	ErrSessionNotFound        = 5010
+	ErrSocialIdentityNotFound = 5011
 
 	// 6000 ~ 401 Authentication UnAuthorization
 	ErrUserEmailUnauthorization = 6001
@@ -64,6 +91,19 @@ const (
 	ErrOnlyTeacher    = 6005
 	ErrOnlySuperAdmin = 6006
 	ErrOnlyOwnUser    = 6007
+
+	ErrRefreshTokenReused = 6008
+	ErrSessionRevoked     = 6009
+	ErrUserWithdrawn      = 6010
+	// superAdmin 은 2단계 인증을 켜야 관리 기능을 사용할 수 있고 끌 수 없다.
+	ErrTwoFactorRequired = 6011
+
+	// 8000 ~ 429 TooManyRequests
+	ErrTooManyRequests             = 8000
+	ErrPhoneVerifyCooldown         = 8001
+	ErrPhoneVerifyAttemptsExceeded = 8002
+	ErrLoginLocked                 = 8003
+	ErrTwoFactorAttemptsExceeded   = 8004
 )
 
 func ErrorText(code int) string {
@@ -107,6 +147,28 @@ func ErrorText(code int) string {
 		return "사용할 수 없는 컬럼입니다."
 	case ErrYogaIdsInvliad:
 		return "유효하지 않은 요가 아이디가 포함되어 있습니다."
+	case ErrRecurrenceInvalid:
+		return "유효하지 않은 반복 규칙입니다."
+	case ErrPasswordResetTokenInvalid:
+		return "만료되었거나 이미 사용된 비밀번호 재설정 링크입니다."
+	case ErrPasswordMismatch:
+		return "현재 비밀번호가 일치하지 않습니다."
+	case ErrRestoreTokenInvalid:
+		return "만료되었거나 이미 사용된 계정 복구 토큰입니다."
+	case ErrSocialLinkTokenInvalid:
+		return "만료되었거나 이미 사용된 소셜 계정 연결 토큰입니다."
+	case ErrOAuthStateInvalid:
+		return "만료되었거나 이미 사용된 소셜 로그인 요청입니다. 다시 시도해주세요."
+	case ErrPhoneVerifyCodeInvalid:
+		return "인증번호가 일치하지 않습니다."
+	case ErrPhoneVerifyCodeExpired:
+		return "인증번호가 만료되었습니다. 인증번호를 다시 요청해주세요."
+	case ErrTotpCodeInvalid:
+		return "인증 코드가 올바르지 않습니다."
+	case ErrTwoFactorChallengeInvalid:
+		return "유효하지 않거나 만료된 2단계 인증 요청입니다. 다시 로그인해주세요."
+	case ErrTwoFactorSetupExpired:
+		return "2단계 인증 등록 시간이 지났습니다. 처음부터 다시 진행해주세요."
 
 	// 4000 ~ Conflict
 	case ErrConflict:
@@ -131,6 +193,28 @@ func ErrorText(code int) string {
 		return "존재하지 않는 시군구입니다."
 	case ErrResourceUnOwned:
 		return "소유권이 없는 리소스가 포함되어 있습니다."
+	case ErrAlreadyApplied:
+		return "이미 지원한 대강입니다."
+	case ErrRecruitmentAlreadyClosed:
+		return "이미 마감된 채용공고입니다."
+	case ErrApplicationStatusInvalid:
+		return "변경할 수 없는 지원 상태입니다."
+	case ErrInsteadAlreadyFilled:
+		return "이미 합격자가 정해진 대강입니다."
+	case ErrScheduleConflict:
+		return "이미 합격한 대강과 시간이 겹칩니다."
+	case ErrSocialLinkRequired:
+		return "같은 이메일로 가입된 계정이 있습니다. 기존 계정으로 로그인한 뒤 소셜 계정 연결을 확인해주세요."
+	case ErrSocialAlreadyLinked:
+		return "이미 다른 계정에 연결된 소셜 계정입니다."
+	case ErrSocialProviderAlreadyLinked:
+		return "이미 같은 업체의 소셜 계정이 연결되어 있습니다."
+	case ErrLastLoginMethod:
+		return "마지막 로그인 수단은 연결 해제할 수 없습니다. 비밀번호를 설정하거나 다른 소셜 계정을 먼저 연결해주세요."
+	case ErrTwoFactorAlreadyEnabled:
+		return "이미 2단계 인증을 사용하고 있습니다."
+	case ErrTwoFactorNotEnabled:
+		return "2단계 인증을 사용하고 있지 않습니다."
 
 	// 5000 ~
 	case ErrUserNotFound:
@@ -143,6 +227,16 @@ func ErrorText(code int) string {
 		return "존재하지 않는 선생님입니다."
 	case ErrRecruitmentNotFound:
 		return "존재하지 않는 게시물입니다."
+	case ErrInsteadNotFound:
+		return "존재하지 않는 대강입니다."
+	case ErrApplicantNotFound:
+		return "지원 내역이 존재하지 않습니다."
+	case ErrScheduleNotFound:
+		return "존재하지 않는 일정입니다."
+	case ErrSessionNotFound:
+		return "존재하지 않거나 이미 로그아웃된 세션입니다."
+	case ErrSocialIdentityNotFound:
+		return "연결되지 않은 소셜 계정입니다."
 
 	// 6000 ~
 	case ErrUserEmailUnauthorization:
@@ -163,6 +257,26 @@ func ErrorText(code int) string {
 		return "슈퍼 관리자로 등록된 회원만 접근할 수 있습니다."
 	case ErrOnlyOwnUser:
 		return "소유권이 없습니다."
+	case ErrRefreshTokenReused:
+		return "이미 사용된 리프레쉬 토큰입니다. 다시 로그인해주세요."
+	case ErrSessionRevoked:
+		return "로그아웃된 세션입니다. 다시 로그인해주세요."
+	case ErrUserWithdrawn:
+		return "탈퇴한 회원입니다. 유예 기간 안에는 계정을 복구할 수 있습니다."
+	case ErrTwoFactorRequired:
+		return "관리자 계정은 2단계 인증을 사용해야 합니다."
+
+	// 8000 ~ TooManyRequests
+	case ErrTooManyRequests:
+		return "요청이 너무 많습니다. 잠시 후에 다시 시도해주세요."
+	case ErrPhoneVerifyCooldown:
+		return "인증번호는 잠시 후에 다시 요청할 수 있습니다."
+	case ErrPhoneVerifyAttemptsExceeded:
+		return "인증번호 요청 횟수를 초과했습니다. 내일 다시 시도해주세요."
+	case ErrLoginLocked:
+		return "로그인 실패 횟수를 초과했습니다. 잠시 후에 다시 시도해주세요."
+	case ErrTwoFactorAttemptsExceeded:
+		return "2단계 인증 시도 횟수를 초과했습니다. 다시 로그인해주세요."
 
 	default:
 		return "일시적인 에러가 발생했습니다."
@@ -277,6 +391,16 @@ func NewUnprocessableEntityError(ErrorCode int, details interface{}) HttpErr {
 	}
 }
 
+// New Too Many Requests Error
+func NewTooManyRequestsError(ErrorCode int, details interface{}) HttpErr {
+	return HttpError{
+		ErrHttpCode: http.StatusTooManyRequests,
+		ErrCode:     ErrorCode,
+		ErrMessage:  ErrorText(ErrorCode),
+		ErrDetails:  details,
+	}
+}
+
 // New Internal Server Error
 func NewInternalServerError() HttpErr {
 	return HttpError{
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
@@ -18,12 +19,15 @@ type Config struct {
 	JWT        JWT        `mapstructure:"Jwt"`
 	Oauth      Oauth      `mapstructure:"Oauth"`
 	Email      Email      `mapstructure:"Email"`
+	Sms        Sms        `mapstructure:"Sms"`
 	APIKey     APIKey     `mapstructure:"ApiKey"`
 	AWS        AWS        `mapstructure:"Aws"`
 	AWSS3      AWSS3      `mapstructure:"AwsS3"`
 	PostgreSQL PostgreSQL `mapstructure:"PostgreSQL"`
 	Elastic    Elastic    `mapstructure:"Elastic"`
 	Onthemat   Onthemat   `mapstructure:"Onthemat"`
+	Scheduler  Scheduler  `mapstructure:"Scheduler"`
+	RateLimit  RateLimit  `mapstructure:"RateLimit"`
 }
 
 type MariaDB struct {
@@ -55,9 +59,18 @@ type Redis struct {
 }
 
 type JWT struct {
-	SignKey             string `env:"JWT_SignKey" envDefault:"1sfkfWjfOkQ8hFhka8"`
-	AccessTokenExpired  int    `env:"JWT_A_EXPIRED" envDefault:"1000000"` // min
-	RefreshTokenExpired int    `env:"JWT_R_EXPIRED" envDefault:"20160"`   // min
+	SignKey             string `env:"JWT_SignKey"`
+	AccessTokenExpired  int    `env:"JWT_A_EXPIRED" envDefault:"1000000"`   // min
+	RefreshTokenExpired int    `env:"JWT_R_EXPIRED" envDefault:"20160"`     // min
+	SessionCacheTTL     int    `env:"JWT_SESSION_CACHE_TTL" envDefault:"5"` // sec, 로그아웃된 세션 확인 결과 캐시
+	// 설정하면 HMAC 대신 RSA(RS256)/Ed25519(EdDSA) 개인키(PEM)로 서명하고 /.well-known/jwks.json 에 공개키를 게시한다.
+	PrivateKeyFile string `env:"JWT_PRIVATE_KEY_FILE"`
+	// 키 교체 중 이전 키로 발급된 토큰을 검증하기 위한 공개키(PEM) 파일 목록
+	VerifyKeyFiles []string `env:"JWT_VERIFY_KEY_FILES" envSeparator:","`
+	// 개인키로 서명할 때도 kid 없는 기존 HMAC 토큰을 SignKey 로 검증할지 여부 (키 교체 기간에만 사용)
+	LegacyHMAC bool `env:"JWT_LEGACY_HMAC" envDefault:"false"`
+	// LegacyHMAC 을 켜면 필수, 이 시각(RFC 3339) 이후로는 HMAC 토큰을 받지 않는다.
+	LegacyHMACUntil time.Time `env:"JWT_LEGACY_HMAC_UNTIL"`
 }
 
 type Oauth struct {
@@ -69,15 +82,42 @@ type Oauth struct {
 	GoogleRedirect     string `env:"GOOGLE_LOGIN_REDIRECT_URL"`
 	GoogleClientId     string `env:"GOOGLE_LOGIN_CLIENT_ID"`
 	GoogleClientSecret string `env:"GOOGLE_LOGIN_CLIENT_SECRET"`
+	// 애플 로그인. ClientId 가 비어있으면 사용하지 않는다.
+	AppleRedirect string `env:"APPLE_LOGIN_REDIRECT_URL"`
+	AppleClientId string `env:"APPLE_LOGIN_CLIENT_ID"` // Services ID
+	AppleTeamId   string `env:"APPLE_LOGIN_TEAM_ID"`
+	AppleKeyId    string `env:"APPLE_LOGIN_KEY_ID"`
+	// client_secret 서명에 사용하는 .p8 개인키 파일
+	ApplePrivateKeyFile string `env:"APPLE_LOGIN_PRIVATE_KEY_FILE"`
 }
 
 type Email struct {
-	Host     string `env:"EMAIL_HOST"`
+	// smtp, file(개발용. 보내지 않고 FileDir 에 .eml 로 저장)
+	Transport string `env:"EMAIL_TRANSPORT" envDefault:"smtp"`
+	Host      string `env:"EMAIL_HOST"`
+	Port      int    `env:"EMAIL_PORT" envDefault:"587"`
+	// starttls, tls(연결부터 TLS, 보통 465), none
+	Security string `env:"EMAIL_SECURITY" envDefault:"starttls"`
 	Password string `env:"EMAIL_PASSWORD"`
 	UserName string `env:"EMAIL_USERNAME"`
+	// 보내는 사람. 비어있으면 UserName
+	From    string `env:"EMAIL_FROM"`
+	FileDir string `env:"EMAIL_FILE_DIR" envDefault:"tmp/mail"`
 }
+type Sms struct {
+	// fake(개발용. 보내지 않고 로그로 남긴다), sens(네이버 클라우드 SENS)
+	Provider string `env:"SMS_PROVIDER" envDefault:"fake"`
+	// 발신 번호. 업체에 미리 등록한 번호만 사용할 수 있다.
+	From          string `env:"SMS_FROM"`
+	SensServiceId string `env:"SMS_SENS_SERVICE_ID"`
+	SensAccessKey string `env:"SMS_SENS_ACCESS_KEY"`
+	SensSecretKey string `env:"SMS_SENS_SECRET_KEY"`
+}
+
 type Secret struct {
 	Password string `env:"PASSWORD_SECRET"`
+	// DB 에 저장하는 2단계 인증(TOTP) 비밀키를 암호화하는 키. base64 로 인코딩된 32byte (openssl rand -base64 32)
+	TotpKey string `env:"TOTP_ENCRYPTION_KEY"`
 }
 
 type APIKey struct {
@@ -97,6 +137,47 @@ type AWSS3 struct {
 type Onthemat struct {
 	PWD  string `env:"ONETHEMAT_PWD"`
 	HOST string `env:"ONETHEMAT_HOST"`
+	// 비밀번호 재설정 페이지 주소. 메일의 링크는 이 주소에 ?token= 을 붙인다. 비어있으면 HOST/password/reset
+	PasswordResetUrl string `env:"ONETHEMAT_PASSWORD_RESET_URL"`
+	// 리버스 프록시 뒤에서 클라이언트 IP 를 담아주는 헤더 (X-Real-IP 등). 비어있으면 연결된 IP 를 사용한다.
+	// 헤더의 첫 번째 IP 를 사용하므로 프록시가 클라이언트가 보낸 값을 덮어쓰는 헤더여야 한다.
+	ProxyHeader string `env:"ONETHEMAT_PROXY_HEADER"`
+	// ProxyHeader 를 믿을 프록시의 IP 또는 CIDR. 여기서 온 요청만 헤더의 IP 를 사용한다.
+	TrustedProxies []string `env:"ONETHEMAT_TRUSTED_PROXIES" envSeparator:","`
+	// 탈퇴 후 복구할 수 있는 기간. 지나면 개인정보를 익명화한다.
+	WithdrawalGraceDays int `env:"ONETHEMAT_WITHDRAWAL_GRACE_DAYS" envDefault:"30"`
+}
+
+type Scheduler struct {
+	Enabled                   bool `env:"SCHEDULER_ENABLED" envDefault:"true"`
+	RecruitmentExpireInterval int  `env:"SCHEDULER_RECRUITMENT_EXPIRE_INTERVAL" envDefault:"10"` // min
+	UserAnonymizeInterval     int  `env:"SCHEDULER_USER_ANONYMIZE_INTERVAL" envDefault:"60"`     // min
+	EmailOutboxInterval       int  `env:"SCHEDULER_EMAIL_OUTBOX_INTERVAL" envDefault:"10"`       // sec
+}
+
+// 그룹별로 window 동안 IP 당 limit 번까지 요청을 허용한다. limit 이 0 이면 제한하지 않는다.
+type RateLimit struct {
+	Enabled       bool `env:"RATE_LIMIT_ENABLED" envDefault:"true"`
+	DefaultLimit  int  `env:"RATE_LIMIT_DEFAULT_LIMIT" envDefault:"300"`
+	DefaultWindow int  `env:"RATE_LIMIT_DEFAULT_WINDOW" envDefault:"60"` // sec
+	// 로그인
+	LoginLimit  int `env:"RATE_LIMIT_LOGIN_LIMIT" envDefault:"10"`
+	LoginWindow int `env:"RATE_LIMIT_LOGIN_WINDOW" envDefault:"60"` // sec
+	// 메일을 보내는 요청 (회원가입, 비밀번호 재설정)
+	EmailLimit  int `env:"RATE_LIMIT_EMAIL_LIMIT" envDefault:"5"`
+	EmailWindow int `env:"RATE_LIMIT_EMAIL_WINDOW" envDefault:"600"` // sec
+	// 가입 여부를 알 수 있는 요청 (이메일 중복체크, 계정 복구)
+	LookupLimit  int `env:"RATE_LIMIT_LOOKUP_LIMIT" envDefault:"20"`
+	LookupWindow int `env:"RATE_LIMIT_LOOKUP_WINDOW" envDefault:"60"` // sec
+	// 문자를 보내는 요청 (휴대폰 번호 인증)
+	SmsLimit  int `env:"RATE_LIMIT_SMS_LIMIT" envDefault:"5"`
+	SmsWindow int `env:"RATE_LIMIT_SMS_WINDOW" envDefault:"600"` // sec
+
+	// 로그인 실패 잠금. LoginFailWindow 동안 이메일, IP 별로 실패가 쌓이면 LoginLockDuration 동안 로그인을 막는다. 0 이면 잠그지 않는다.
+	LoginFailEmailLimit int `env:"RATE_LIMIT_LOGIN_FAIL_EMAIL_LIMIT" envDefault:"5"`
+	LoginFailIpLimit    int `env:"RATE_LIMIT_LOGIN_FAIL_IP_LIMIT" envDefault:"20"`
+	LoginFailWindow     int `env:"RATE_LIMIT_LOGIN_FAIL_WINDOW" envDefault:"15"`   // min
+	LoginLockDuration   int `env:"RATE_LIMIT_LOGIN_LOCK_DURATION" envDefault:"15"` // min
 }
 
 const (
@@ -126,12 +207,15 @@ func (c *Config) Load(filePath string) error {
 	data["Jwt"] = &JWT{}
 	data["Oauth"] = &Oauth{}
 	data["Email"] = &Email{}
+	data["Sms"] = &Sms{}
 	data["ApiKey"] = &APIKey{}
 	data["Aws"] = &AWS{}
 	data["AwsS3"] = &AWSS3{}
 	data["Secret"] = &Secret{}
 	data["Onthemat"] = &Onthemat{}
 	data["Elastic"] = &Elastic{}
+	data["Scheduler"] = &Scheduler{}
+	data["RateLimit"] = &RateLimit{}
 
 	for _, v := range data {
 		if err := env.Parse(v, op); err != nil {
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"time"
 
 	ex "onthemat/internal/app/common"
+	"onthemat/internal/app/delivery/middlewares"
 	"onthemat/internal/app/transport/request"
 	"onthemat/internal/app/transport/response"
 	"onthemat/internal/app/utils"
@@ -17,9 +20,12 @@ import (
 type authHandler struct {
 	AuthUseCase usecase.AuthUseCase
 	Validator   validatorx.Validator
+	// 소셜 로그인 state 쿠키를 보낼 경로. 라우터 그룹의 전체 경로(/api/v1/auth)를 사용한다.
+	cookiePath string
 }
 
 func NewAuthHandler(
+	middleware middlewares.MiddleWare,
 	authUseCase usecase.AuthUseCase,
 	validator validatorx.Validator,
 	router fiber.Router,
@@ -29,26 +35,104 @@ func NewAuthHandler(
 		Validator:   validator,
 	}
 	g := router.Group("/auth")
+	handler.cookiePath = "/auth"
+	if group, ok := g.(*fiber.Group); ok {
+		handler.cookiePath = group.Prefix
+	}
 	// 소셜로그인 리디렉션
 	g.Get("/:socialName/url", handler.SocialUrl)
 	// 소셜로로그인 콜백
 	g.Get("/:socialName/callback", handler.SocialCallback)
+	// 애플은 form POST 로 콜백한다.
+	g.Post("/:socialName/callback", handler.SocialCallback)
 	// 회원가입
-	g.Post("/signup", handler.SignUp)
+	g.Post("/signup", middleware.RateLimit(middlewares.RateLimitEmail), handler.SignUp)
 	// 로그인
-	g.Post("/login", handler.Login)
+	g.Post("/login", middleware.RateLimit(middlewares.RateLimitLogin), handler.Login)
+	// 2단계 인증 로그인
+	g.Post("/login/two-factor", middleware.RateLimit(middlewares.RateLimitLogin), handler.VerifyTwoFactor)
 	// 로그아웃
 	g.Get("/logout", handler.Logout)
 	// 소셜 회원가입
 	g.Patch("/social/signup", handler.SocialSignUp)
-	// 임시 비밀번호 발급
-	g.Get("/temp-password", handler.SendTempPassword)
+	// 비밀번호 재설정 메일 발송
+	g.Post("/password/reset-request", middleware.RateLimit(middlewares.RateLimitEmail), handler.RequestPasswordReset)
+	// 비밀번호 재설정
+	g.Post("/password/reset", handler.ResetPassword)
+	// 비밀번호 변경
+	g.Patch("/password", middleware.Auth, handler.ChangePassword)
+	// 2단계 인증 등록, 해제
+	g.Post("/two-factor/setup", middleware.Auth, handler.SetupTwoFactor)
+	g.Post("/two-factor/confirm", middleware.Auth, handler.ConfirmTwoFactor)
+	g.Delete("/two-factor", middleware.Auth, handler.DisableTwoFactor)
+	// 탈퇴 계정 복구
+	g.Post("/restore", middleware.RateLimit(middlewares.RateLimitLookup), handler.Restore)
 	// 이메일 중복체크
-	g.Get("/check-email", handler.CheckDuplicatedEmail)
+	g.Get("/check-email", middleware.RateLimit(middlewares.RateLimitLookup), handler.CheckDuplicatedEmail)
 	// 이메일 인증
 	g.Get("/verify-email", handler.VerifiyEmail)
 	// Access Token 리프레쉬
 	g.Get("/token/refresh", handler.Refresh)
+	// 로그인된 기기 목록
+	g.Get("/sessions", middleware.Auth, handler.SessionList)
+	// 모든 기기에서 로그아웃
+	g.Delete("/sessions", middleware.Auth, handler.RevokeAllSessions)
+	// 특정 기기 로그아웃
+	g.Delete("/sessions/:uuid", middleware.Auth, handler.RevokeSession)
+	// 연결된 소셜 계정 목록
+	g.Get("/social/identities", middleware.Auth, handler.SocialIdentityList)
+	// 같은 이메일의 소셜 계정 연결 확인
+	g.Post("/social/link/confirm", middleware.Auth, handler.ConfirmSocialLink)
+	// 소셜 계정 연결
+	g.Post("/social/:socialName/link", middleware.Auth, handler.LinkSocial)
+	// 소셜 계정 연결 해제
+	g.Delete("/social/:socialName", middleware.Auth, handler.UnlinkSocial)
+
+	// 토큰 검증용 공개키
+	router.Get("/.well-known/jwks.json", handler.JWKS)
+}
+
+// 로그인 요청을 보낸 기기 정보
+func newSessionDevice(c *fiber.Ctx) *usecase.SessionDevice {
+	return &usecase.SessionDevice{
+		UserAgent: string(c.Request().Header.UserAgent()),
+		Ip:        c.IP(),
+	}
+}
+
+// 소셜 로그인을 시작한 브라우저에만 심어두는 state 쿠키 (login CSRF 방지)
+const oauthStateCookie = "oauthState"
+
+// 애플은 다른 사이트에서 form POST 로 콜백하므로 SameSite=Lax 쿠키가 전달되지 않는다.
+func (h *authHandler) setOAuthStateCookie(c *fiber.Ctx, socialName string, state string) {
+	cookie := &fiber.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     h.cookiePath,
+		Expires:  time.Now().Add(10 * time.Minute),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+	if socialName == "apple" {
+		cookie.SameSite = fiber.CookieSameSiteNoneMode
+		cookie.Secure = true
+	}
+	c.Cookie(cookie)
+}
+
+// 소셜 로그인에서 2단계 인증이 필요하면 challenge 를 스크립트로 읽을 수 없는 쿠키로 넘기고, /login/two-factor 에서 꺼내 쓴다.
+const twoFactorChallengeCookie = "twoFactorChallenge"
+
+// 쿠키의 state 와 콜백으로 받은 state 가 같아야 한다. 확인한 쿠키는 바로 지운다.
+func (h *authHandler) checkOAuthStateCookie(c *fiber.Ctx, state string) bool {
+	cookie := c.Cookies(oauthStateCookie)
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthStateCookie,
+		Path:     h.cookiePath,
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+	})
+	return cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) == 1
 }
 
 // 소셜로그인 리디렉션
@@ -57,8 +141,8 @@ func NewAuthHandler(
 @apiName socialLoginRedirection
 @apiVersion 1.0.0
 @apiGroup auth
-@apiDescription 소셜로그인 URL
-@apiParam {String="naver,kakao,google"} socialName 소셜 로그인 타입
+@apiDescription 소셜로그인 URL. state 를 HttpOnly 쿠키(oauthState)로 함께 내려주며, 같은 브라우저에서 콜백해야 한다.
+@apiParam {String="naver,kakao,google,apple"} socialName 소셜 로그인 타입
 @apiSuccessExample Success-Response:
 HTTP/1.1 302 OK
 */
@@ -76,10 +160,11 @@ func (h *authHandler) SocialUrl(c *fiber.Ctx) error {
 			JSON(ex.NewInvalidInputError(err))
 	}
 
-	url, err := h.AuthUseCase.SocialLoginRedirectUrl(ctx, reqParam.SocialName)
+	url, state, err := h.AuthUseCase.SocialLoginRedirectUrl(ctx, reqParam.SocialName)
 	if err != nil {
 		return utils.NewError(c, err)
 	}
+	h.setOAuthStateCookie(c, reqParam.SocialName, state)
 	return c.Status(200).
 		JSON(ex.ResponseWithData{
 			Code:    200,
@@ -94,8 +179,10 @@ func (h *authHandler) SocialUrl(c *fiber.Ctx) error {
 @apiName Socialcallback
 @apiVersion 1.0.0
 @apiGroup auth
-@apiDescription 소셜 Callback
-@apiParam {String="naver,kakao,google"} socialName 소셜 로그인 타입
+@apiDescription 소셜 Callback. 애플은 같은 주소로 form POST(code, state) 한다.
+@apiParam {String="naver,kakao,google,apple"} socialName 소셜 로그인 타입
+@apiQuery {String} code 소셜 로그인 인가 코드
+@apiQuery {String} state 로그인 페이지 주소를 발급할 때 함께 발급된 값 (10분 동안 한 번만 사용 가능, oauthState 쿠키와 같아야 함)
 @apiSuccess {Number} code 200
 @apiSuccess {String} message ""
 @apiSuccess {Object} result
@@ -104,6 +191,10 @@ func (h *authHandler) SocialUrl(c *fiber.Ctx) error {
 @apiSuccess {String} result.refreshToken 리프레쉬 토큰
 @apiSuccess {String} result.refreshTokenExpiredAt 리프레쉬 토큰 만료일시
 @apiError QueryStringMissing <code>400</code> code: 3001
+@apiError OAuthStateInvalid <code>400</code> code: 3016
+@apiError UserWithdrawn <code>403</code> code: 6010 (details: restoreToken, restorableUntil)
+@apiError SocialLinkRequired <code>409</code> code: 4016 같은 이메일로 가입된 계정이 있음 (details: linkToken, provider, email)
+@apiError UserEmailAlreadyExist <code>409</code> code: 4001
 @apiError InternalServerError <code>500</code> code: 500
 */
 func (h *authHandler) SocialCallback(c *fiber.Ctx) error {
@@ -120,16 +211,36 @@ func (h *authHandler) SocialCallback(c *fiber.Ctx) error {
 			JSON(ex.NewInvalidInputError(err))
 	}
 
-	code := c.Query("code")
-	if code == "" {
+	code := c.Query("code", c.FormValue("code"))
+	state := c.Query("state", c.FormValue("state"))
+	if code == "" || state == "" {
 		return c.Status(http.StatusBadRequest).
 			JSON(ex.NewHttpError(ex.ErrQueryStringMissing, nil))
 	}
+	if !h.checkOAuthStateCookie(c, state) {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrOAuthStateInvalid, nil))
+	}
 
-	data, err := h.AuthUseCase.SocialLogin(ctx, reqParam.SocialName, code)
+	data, challenge, err := h.AuthUseCase.SocialLogin(ctx, reqParam.SocialName, code, state, newSessionDevice(c))
 	if err != nil {
 		return utils.NewError(c, err)
 	}
+
+	// 2단계 인증을 사용하면 코드 입력 화면으로 보낸다. challenge 는 쿠키로 /auth/login/two-factor 에 전달된다.
+	if challenge != nil {
+		c.Cookie(&fiber.Cookie{
+			Name:     twoFactorChallengeCookie,
+			Value:    challenge.ChallengeToken,
+			Path:     h.cookiePath,
+			Expires:  challenge.ExpiredAt,
+			HTTPOnly: true,
+			Secure:   true,
+			SameSite: fiber.CookieSameSiteLaxMode,
+		})
+		return c.Redirect("http://localhost:3000/login/two-factor")
+	}
+
 	c.Cookie(&fiber.Cookie{
 		Name:    "accessToken",
 		Value:   data.AccessToken,
@@ -162,6 +273,7 @@ func (h *authHandler) SocialCallback(c *fiber.Ctx) error {
 @apiError ValidationError <code>400</code> code: 2xxx
 @apiError PasswordInvalid <code>400</code> code: 2001
 @apiError EmailInvalid <code>400</code> code: 2002
+@apiError TooManyRequests <code>429</code> code: 8000 (details: retryAfter, Retry-After 헤더)
 @apiError InternalServerError <code>500</code> code: 500
 */
 func (h *authHandler) SignUp(c *fiber.Ctx) error {
@@ -251,10 +363,16 @@ func (h *authHandler) SocialSignUp(c *fiber.Ctx) error {
 @apiSuccess {String} result.accessTokenexpiredAt 엑세스 토큰 만료일시
 @apiSuccess {String} result.refreshToken 리프레쉬 토큰
 @apiSuccess {String} result.refreshTokenExpiredAt 리프레쉬 토큰 만료일시
+@apiSuccess {Boolean} [result.twoFactorRequired] 2단계 인증을 사용하는 유저면 true. 토큰 대신 아래 값을 받는다.
+@apiSuccess {String} [result.challengeToken] /auth/login/two-factor 로 보낼 토큰
+@apiSuccess {String} [result.expiredAt] challengeToken 만료일시 (5분)
 @apiError PasswordInvalid <code>400</code> code: 2001
 @apiError EmailInvalid <code>400</code> code: 2002
 @apiError UserEmailUnauthorization <code>401</code> code: 6001
+@apiError UserWithdrawn <code>403</code> code: 6010 (details: restoreToken, restorableUntil)
 @apiError UserNotFound <code>404</code> code: 5001
+@apiError TooManyRequests <code>429</code> code: 8000 (details: retryAfter, Retry-After 헤더)
+@apiError LoginLocked <code>429</code> code: 8003 (details: retryAfter)
 @apiError InternalServerError <code>500</code> code: 500
 */
 func (h *authHandler) Login(c *fiber.Ctx) error {
@@ -272,11 +390,90 @@ func (h *authHandler) Login(c *fiber.Ctx) error {
 			JSON(ex.NewInvalidInputError(err))
 	}
 
-	data, err := h.AuthUseCase.Login(ctx, body)
+	data, challenge, err := h.AuthUseCase.Login(ctx, body, newSessionDevice(c))
 	if err != nil {
 		return utils.NewError(c, err)
 	}
 
+	if challenge != nil {
+		return c.Status(200).
+			JSON(ex.ResponseWithData{
+				Code:    200,
+				Message: "",
+				Result:  response.NewTwoFactorChallengeResponse(challenge),
+			})
+	}
+
+	return c.Status(200).
+		JSON(ex.ResponseWithData{
+			Code:    200,
+			Message: "",
+			Result:  data,
+		})
+}
+
+// 2단계 인증 로그인
+/**
+@api {post} /auth/login/two-factor 2단계 인증 로그인
+@apiName loginTwoFactor
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 로그인에서 받은 challengeToken 과 인증 앱의 코드(혹은 복구 코드)로 토큰을 발급받는다.
+소셜 로그인은 challengeToken 대신 콜백에서 심은 twoFactorChallenge 쿠키(HttpOnly)를 사용한다.
+challengeToken 하나로 5번까지 시도할 수 있고, 복구 코드는 한 번 사용하면 사라진다.
+@apiBody {String} [challengeToken] 로그인 응답의 challengeToken (없으면 twoFactorChallenge 쿠키)
+@apiBody {String} code 인증 앱의 6자리 코드 혹은 복구 코드(xxxxx-xxxxx)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object} result
+@apiSuccess {String} result.accessToken 엑세스 토큰
+@apiSuccess {String} result.accessTokenexpiredAt 엑세스 토큰 만료일시
+@apiSuccess {String} result.refreshToken 리프레쉬 토큰
+@apiSuccess {String} result.refreshTokenExpiredAt 리프레쉬 토큰 만료일시
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ValidationError <code>400</code> code: 2000
+@apiError TotpCodeInvalid <code>400</code> code: 3019
+@apiError TwoFactorChallengeInvalid <code>400</code> code: 3020
+@apiError TwoFactorNotEnabled <code>409</code> code: 4021
+@apiError TooManyRequests <code>429</code> code: 8000 (details: retryAfter, Retry-After 헤더)
+@apiError TwoFactorAttemptsExceeded <code>429</code> code: 8004
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) VerifyTwoFactor(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	body := new(request.AuthTwoFactorLoginBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if body.ChallengeToken == "" {
+		body.ChallengeToken = c.Cookies(twoFactorChallengeCookie)
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	data, err := h.AuthUseCase.VerifyTwoFactor(ctx, body, newSessionDevice(c))
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	// 사용한 challenge 쿠키는 지운다.
+	if c.Cookies(twoFactorChallengeCookie) != "" {
+		c.Cookie(&fiber.Cookie{
+			Name:     twoFactorChallengeCookie,
+			Path:     h.cookiePath,
+			Expires:  time.Unix(0, 0),
+			HTTPOnly: true,
+			Secure:   true,
+		})
+	}
+
 	return c.Status(200).
 		JSON(ex.ResponseWithData{
 			Code:    200,
@@ -298,6 +495,7 @@ func (h *authHandler) Login(c *fiber.Ctx) error {
 @apiError QueryStringMissing <code>400</code> code: 3001
 @apiError EmailInvalid <code>400</code> code: 2002
 @apiError UserEmailAlreadyExist <code>409</code> code: 4001
+@apiError TooManyRequests <code>429</code> code: 8000 (details: retryAfter, Retry-After 헤더)
 @apiError InternalServerError <code>500</code> code: 500
 */
 func (h *authHandler) CheckDuplicatedEmail(c *fiber.Ctx) error {
@@ -326,37 +524,38 @@ func (h *authHandler) CheckDuplicatedEmail(c *fiber.Ctx) error {
 		})
 }
 
-// 임시 비밀번호 발급
+// 비밀번호 재설정 메일 발송
 /**
-@api {get} /auth/temp-password 임시 비밀번호 이메일 발송
-@apiName tempPassword
+@api {post} /auth/password/reset-request 비밀번호 재설정 메일 발송
+@apiName passwordResetRequest
 @apiVersion 1.0.0
 @apiGroup auth
-@apiDescription 유저의 이메일 계정으로 임시비밀번호 발송하는 API
-@apiQuery {String} email 유저의 email
+@apiDescription 유저의 이메일로 비밀번호 재설정 링크를 발송하는 API. 가입하지 않았거나 탈퇴한 이메일이어도 같은 응답을 반환합니다.
+링크의 토큰은 30분 동안 한 번만 사용할 수 있다.
+@apiBody {String} email 유저의 email
 @apiSuccess (202) {Number} code 202
 @apiSuccess (202) {String} message ""
-@apiError QueryStringMissing <code>400</code> code: 3001
+@apiError JsonMissing <code>400</code> code: 3000
 @apiError EmailInvalid <code>400</code> code: 2002
-@apiError UserNotFound <code>404</code> code: 5001
+@apiError TooManyRequests <code>429</code> code: 8000 (details: retryAfter, Retry-After 헤더)
 @apiError InternalServerError <code>500</code> code: 500
 */
-func (h *authHandler) SendTempPassword(c *fiber.Ctx) error {
+func (h *authHandler) RequestPasswordReset(c *fiber.Ctx) error {
 	ctx := c.Context()
-	queries := new(request.AuthTempPasswordQuery)
 
-	if err := c.QueryParser(queries); err != nil {
+	body := new(request.AuthPasswordResetRequestBody)
+	if err := c.BodyParser(body); err != nil {
 		return c.Status(http.StatusBadRequest).
-			JSON(ex.NewHttpError(ex.ErrQueryStringMissing, err.Error()))
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
 	}
 
-	if err := h.Validator.ValidateStruct(queries); err != nil {
+	if err := h.Validator.ValidateStruct(body); err != nil {
 		return c.
 			Status(http.StatusBadRequest).
 			JSON(ex.NewInvalidInputError(err))
 	}
 
-	if err := h.AuthUseCase.SendEmailResetPassword(ctx, queries.Email); err != nil {
+	if err := h.AuthUseCase.RequestPasswordReset(ctx, body.Email); err != nil {
 		return utils.NewError(c, err)
 	}
 
@@ -367,6 +566,140 @@ func (h *authHandler) SendTempPassword(c *fiber.Ctx) error {
 		})
 }
 
+// 비밀번호 재설정
+/**
+@api {post} /auth/password/reset 비밀번호 재설정
+@apiName passwordReset
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 메일로 받은 토큰으로 비밀번호를 재설정한다. 모든 기기에서 로그아웃된다.
+@apiBody {String} token 재설정 링크의 token
+@apiBody {String} password 새 비밀번호
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError PasswordInvalid <code>400</code> code: 2001
+@apiError PasswordResetTokenInvalid <code>400</code> code: 3012
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) ResetPassword(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	body := new(request.AuthPasswordResetBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	if err := h.AuthUseCase.ResetPassword(ctx, body); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).
+		JSON(ex.Response{
+			Code:    200,
+			Message: "",
+		})
+}
+
+// 비밀번호 변경
+/**
+@api {patch} /auth/password 비밀번호 변경
+@apiName passwordChange
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 현재 비밀번호를 확인하고 새 비밀번호로 바꾼다. 요청한 기기를 제외한 모든 기기에서 로그아웃된다.
+@apiHeader Authorization accessToken (Bearer)
+@apiBody {String} currentPassword 현재 비밀번호
+@apiBody {String} newPassword 새 비밀번호
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError PasswordInvalid <code>400</code> code: 2001
+@apiError PasswordMismatch <code>400</code> code: 3013
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError SessionRevoked <code>401</code> code: 6009
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) ChangePassword(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+	sessionId, _ := ctx.UserValue("session_id").(string)
+
+	body := new(request.AuthPasswordChangeBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	if err := h.AuthUseCase.ChangePassword(ctx, userId, sessionId, body); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).
+		JSON(ex.Response{
+			Code:    200,
+			Message: "",
+		})
+}
+
+// 탈퇴 계정 복구
+/**
+@api {post} /auth/restore 탈퇴 계정 복구
+@apiName restore
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 탈퇴 유예 기간 안에 로그인했을 때 받은 복구 토큰으로 계정을 복구한다. 복구 후 다시 로그인해야 한다.
+@apiBody {String} token 로그인 에러(6010) details 의 restoreToken
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ValidationError <code>400</code> code: 2000
+@apiError RestoreTokenInvalid <code>400</code> code: 3014
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError TooManyRequests <code>429</code> code: 8000 (details: retryAfter, Retry-After 헤더)
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) Restore(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	body := new(request.AuthRestoreBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	if err := h.AuthUseCase.Restore(ctx, body); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).
+		JSON(ex.Response{
+			Code:    200,
+			Message: "",
+		})
+}
+
 // 이메일 인증
 /**
 @api {get} /auth/verify-email 이메일 인증
@@ -417,16 +750,21 @@ func (h *authHandler) VerifiyEmail(c *fiber.Ctx) error {
 @apiName acessTokenRefresh
 @apiVersion 1.0.0
 @apiGroup auth
-@apiDescription 엑세스 토큰을 재발급하는 API
+@apiDescription 엑세스 토큰을 재발급하는 API.
+리프레쉬 토큰도 함께 새로 발급되며 기존 리프레쉬 토큰은 더 이상 사용할 수 없다.
+이미 사용된 리프레쉬 토큰으로 요청하면 같은 로그인에서 발급된 토큰이 모두 폐기된다.
 @apiHeader {String} Authorization 리프레쉬토큰(Bearer)
 @apiSuccess {Number} code 200
 @apiSuccess {String} message ""
 @apiSuccess {Object} result
 @apiSuccess {String} result.accessToken 엑세스 토큰
 @apiSuccess {String} result.accessTokenexpiredAt 엑세스 토큰 만료일시
+@apiSuccess {String} result.refreshToken 새 리프레쉬 토큰
+@apiSuccess {String} result.refreshTokenExpiredAt 리프레쉬 토큰 만료일시
 @apiError AuthorizationHeaderFormatUnavailable <code>400</code> code: 3005
 @apiError TokenInvalid <code>400</code> code: 3007
 @apiError TokenExpired <code>401</code> code: 6002
+@apiError RefreshTokenReused <code>401</code> code: 6008
 @apiError UserNotFound <code>404</code> code: 5001
 @apiError InternalServerError <code>500</code> code: 500
 */
@@ -435,7 +773,7 @@ func (h *authHandler) Refresh(c *fiber.Ctx) error {
 
 	authorizationHeader := c.Request().Header.Peek("Authorization")
 
-	data, err := h.AuthUseCase.Refresh(ctx, authorizationHeader)
+	data, err := h.AuthUseCase.Refresh(ctx, authorizationHeader, newSessionDevice(c))
 	if err != nil {
 		return utils.NewError(c, err)
 	}
@@ -486,3 +824,454 @@ func (h *authHandler) Logout(c *fiber.Ctx) error {
 		Message: "",
 	})
 }
+
+// 로그인된 기기 목록
+/**
+@api {get} /auth/sessions 로그인된 기기 목록
+@apiName sessionList
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 로그인된 기기(세션) 목록을 최근 사용 순으로 조회한다.
+@apiHeader Authorization accessToken (Bearer)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object[]} result
+@apiSuccess {String} result.uuid 세션 아이디
+@apiSuccess {String} result.userAgent 로그인한 기기의 User-Agent
+@apiSuccess {String} result.ip 마지막으로 사용한 IP
+@apiSuccess {String="normal,kakao,google,naver"} result.loginType 로그인 타입
+@apiSuccess {Boolean} result.isCurrent 현재 요청한 세션인지 여부
+@apiSuccess {String} result.createdAt 로그인 일시 (기존 세션은 null)
+@apiSuccess {String} result.lastUsedAt 마지막 사용 일시 (기존 세션은 null)
+@apiError AuthorizationHeaderFormatUnavailable <code>400</code> code: 3005
+@apiError TokenInvalid <code>400</code> code: 3007
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) SessionList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+	sessionId, _ := ctx.UserValue("session_id").(string)
+
+	data, err := h.AuthUseCase.SessionList(ctx, userId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).
+		JSON(ex.ResponseWithData{
+			Code:    200,
+			Message: "",
+			Result:  response.NewSessionListResponse(data, sessionId),
+		})
+}
+
+// 특정 기기 로그아웃
+/**
+@api {delete} /auth/sessions/:uuid 특정 기기 로그아웃
+@apiName revokeSession
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 선택한 세션의 리프레쉬 토큰을 폐기한다.
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {String} uuid 세션 아이디
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError AuthorizationHeaderFormatUnavailable <code>400</code> code: 3005
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError SessionNotFound <code>404</code> code: 5010
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) RevokeSession(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	reqParam := new(request.AuthSessionParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+	if err := h.Validator.ValidateStruct(reqParam); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	if err := h.AuthUseCase.RevokeSession(ctx, userId, reqParam.Uuid); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).JSON(ex.Response{
+		Code:    200,
+		Message: "",
+	})
+}
+
+// 모든 기기에서 로그아웃
+/**
+@api {delete} /auth/sessions 모든 기기에서 로그아웃
+@apiName revokeAllSessions
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 현재 기기를 포함한 모든 세션의 리프레쉬 토큰을 폐기한다.
+@apiHeader Authorization accessToken (Bearer)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError AuthorizationHeaderFormatUnavailable <code>400</code> code: 3005
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) RevokeAllSessions(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	if err := h.AuthUseCase.RevokeAllSessions(ctx, userId); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).JSON(ex.Response{
+		Code:    200,
+		Message: "",
+	})
+}
+
+// 연결된 소셜 계정 목록
+/**
+@api {get} /auth/social/identities 연결된 소셜 계정 목록
+@apiName socialIdentityList
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 내 계정에 연결된 소셜 계정 목록을 연결한 순서로 조회한다.
+@apiHeader Authorization accessToken (Bearer)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object[]} result
+@apiSuccess {String="kakao,google,naver"} result.provider 소셜로그인 타입
+@apiSuccess {String} [result.email] 소셜 계정 이메일
+@apiSuccess {String} result.linkedAt 연결 일시
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) SocialIdentityList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	data, err := h.AuthUseCase.SocialIdentityList(ctx, userId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).
+		JSON(ex.ResponseWithData{
+			Code:    200,
+			Message: "",
+			Result:  response.NewSocialIdentityListResponse(data),
+		})
+}
+
+// 소셜 계정 연결
+/**
+@api {post} /auth/social/:socialName/link 소셜 계정 연결
+@apiName linkSocial
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 로그인한 상태에서 소셜 로그인으로 받은 인가 코드로 소셜 계정을 연결한다. 업체별로 하나씩 연결할 수 있다.
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {String="naver,kakao,google,apple"} socialName 소셜 로그인 타입
+@apiBody {String} code 소셜 로그인 인가 코드
+@apiBody {String} state 소셜 로그인 콜백으로 받은 state (oauthState 쿠키와 같아야 함)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError OAuthStateInvalid <code>400</code> code: 3016
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError SocialAlreadyLinked <code>409</code> code: 4017
+@apiError SocialProviderAlreadyLinked <code>409</code> code: 4018
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) LinkSocial(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	reqParam := new(request.AuthSocialParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+	if err := h.Validator.ValidateStruct(reqParam); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	body := new(request.AuthSocialLinkBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+	if !h.checkOAuthStateCookie(c, body.State) {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrOAuthStateInvalid, nil))
+	}
+
+	if err := h.AuthUseCase.LinkSocial(ctx, userId, reqParam.SocialName, body.Code, body.State); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).JSON(ex.Response{
+		Code:    200,
+		Message: "",
+	})
+}
+
+// 같은 이메일의 소셜 계정 연결 확인
+/**
+@api {post} /auth/social/link/confirm 소셜 계정 연결 확인
+@apiName confirmSocialLink
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 소셜 로그인 시 같은 이메일로 가입된 계정이 있으면(4016) 자동으로 연결하지 않는다.
+기존 계정으로 로그인한 뒤 에러 details 의 linkToken 으로 연결을 확인해야 연결된다.
+@apiHeader Authorization accessToken (Bearer)
+@apiBody {String} linkToken 소셜 로그인 에러(4016) details 의 linkToken
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError SocialLinkTokenInvalid <code>400</code> code: 3015
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError OnlyOwnUser <code>403</code> code: 6007 다른 계정의 연결 토큰
+@apiError SocialAlreadyLinked <code>409</code> code: 4017
+@apiError SocialProviderAlreadyLinked <code>409</code> code: 4018
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) ConfirmSocialLink(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	body := new(request.AuthSocialLinkConfirmBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	if err := h.AuthUseCase.ConfirmSocialLink(ctx, userId, body); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).JSON(ex.Response{
+		Code:    200,
+		Message: "",
+	})
+}
+
+// 소셜 계정 연결 해제
+/**
+@api {delete} /auth/social/:socialName 소셜 계정 연결 해제
+@apiName unlinkSocial
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 연결된 소셜 계정을 해제한다. 비밀번호가 없는 계정의 마지막 소셜 계정은 해제할 수 없다.
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {String="naver,kakao,google,apple"} socialName 소셜 로그인 타입
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError SocialIdentityNotFound <code>404</code> code: 5011
+@apiError LastLoginMethod <code>409</code> code: 4019
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) UnlinkSocial(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	reqParam := new(request.AuthSocialParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+	if err := h.Validator.ValidateStruct(reqParam); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	if err := h.AuthUseCase.UnlinkSocial(ctx, userId, reqParam.SocialName); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).JSON(ex.Response{
+		Code:    200,
+		Message: "",
+	})
+}
+
+// 토큰 검증용 공개키
+/**
+@api {get} /.well-known/jwks.json 토큰 검증용 공개키(JWKS)
+@apiName jwks
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 엑세스/리프레쉬 토큰 서명 검증에 사용하는 공개키 목록(RFC 7517).
+토큰 헤더의 kid 와 같은 키로 검증한다. 키 교체 중에는 이전 키도 함께 포함된다.
+HMAC 으로 서명 중이면 keys 는 빈 배열이다.
+@apiSuccess {Object[]} keys
+@apiSuccess {String} keys.kty 키 타입 (RSA, OKP)
+@apiSuccess {String} keys.kid 키 아이디
+@apiSuccess {String} keys.use sig
+@apiSuccess {String} keys.alg RS256, EdDSA
+@apiSuccess {String} [keys.n] RSA modulus
+@apiSuccess {String} [keys.e] RSA exponent
+@apiSuccess {String} [keys.crv] Ed25519
+@apiSuccess {String} [keys.x] Ed25519 공개키
+*/
+func (h *authHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(http.StatusOK).JSON(h.AuthUseCase.JWKS())
+}
+
+// 2단계 인증 등록 시작
+/**
+@api {post} /auth/two-factor/setup 2단계 인증 등록 시작
+@apiName setupTwoFactor
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 인증 앱에 등록할 비밀키를 발급한다. 10분 안에 /auth/two-factor/confirm 으로 확인해야 켜진다.
+@apiHeader Authorization accessToken (Bearer)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object} result
+@apiSuccess {String} result.secret 비밀키(base32). QR 코드를 읽을 수 없을 때 직접 입력한다.
+@apiSuccess {String} result.provisioningUri QR 코드로 만들 otpauth:// URI
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError TwoFactorAlreadyEnabled <code>409</code> code: 4020
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) SetupTwoFactor(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	data, err := h.AuthUseCase.SetupTwoFactor(ctx, userId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).
+		JSON(ex.ResponseWithData{
+			Code:    200,
+			Message: "",
+			Result:  data,
+		})
+}
+
+// 2단계 인증 등록 확인
+/**
+@api {post} /auth/two-factor/confirm 2단계 인증 등록 확인
+@apiName confirmTwoFactor
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 인증 앱의 코드로 확인하면 2단계 인증이 켜지고 복구 코드 10개를 발급한다.
+복구 코드는 이 응답에서만 확인할 수 있다.
+현재 기기를 제외한 모든 기기에서 로그아웃되며, 관리자 기능은 2단계 인증으로 다시 로그인해야 사용할 수 있다.
+@apiHeader Authorization accessToken (Bearer)
+@apiBody {String} code 인증 앱의 6자리 코드
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object} result
+@apiSuccess {String[]} result.recoveryCodes 복구 코드 (xxxxx-xxxxx)
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ValidationError <code>400</code> code: 2000
+@apiError TotpCodeInvalid <code>400</code> code: 3019
+@apiError TwoFactorSetupExpired <code>400</code> code: 3021
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError TwoFactorAlreadyEnabled <code>409</code> code: 4020
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) ConfirmTwoFactor(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+	sessionId, _ := ctx.UserValue("session_id").(string)
+
+	body := new(request.AuthTwoFactorConfirmBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	recoveryCodes, err := h.AuthUseCase.ConfirmTwoFactor(ctx, userId, sessionId, body)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).
+		JSON(ex.ResponseWithData{
+			Code:    200,
+			Message: "",
+			Result:  &response.TwoFactorRecoveryCodesResponse{RecoveryCodes: recoveryCodes},
+		})
+}
+
+// 2단계 인증 해제
+/**
+@api {delete} /auth/two-factor 2단계 인증 해제
+@apiName disableTwoFactor
+@apiVersion 1.0.0
+@apiGroup auth
+@apiDescription 인증 앱의 코드 혹은 복구 코드를 확인하고 2단계 인증을 끈다. 관리자(superAdmin)는 끌 수 없다.
+@apiHeader Authorization accessToken (Bearer)
+@apiBody {String} code 인증 앱의 6자리 코드 혹은 복구 코드(xxxxx-xxxxx)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ValidationError <code>400</code> code: 2000
+@apiError TotpCodeInvalid <code>400</code> code: 3019
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError TwoFactorRequired <code>403</code> code: 6011
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError TwoFactorNotEnabled <code>409</code> code: 4021
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *authHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	body := new(request.AuthTwoFactorCodeBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	if err := h.AuthUseCase.DisableTwoFactor(ctx, userId, body); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(200).JSON(ex.Response{
+		Code:    200,
+		Message: "",
+	})
+}
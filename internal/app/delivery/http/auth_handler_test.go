@@ -5,16 +5,22 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"onthemat/internal/app/common"
 	"onthemat/internal/app/mocks"
+	"onthemat/internal/app/transport/request"
 	"onthemat/internal/app/usecase"
 	"onthemat/internal/app/utils"
 	pkgMock "onthemat/pkg/mocks"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -34,7 +40,11 @@ func (ts *AuthHDTestSuite) SetupSuite() {
 
 	ts.fiber = fiber.New()
 	ts.mockValidator = new(pkgMock.Validator)
-	NewAuthHandler(ts.mockAuthUseCase, ts.mockValidator, ts.fiber)
+	mockMiddleWare := new(mocks.MiddleWare)
+	mockMiddleWare.On("RateLimit", mock.AnythingOfType("string")).Return(func(c *fiber.Ctx) error {
+		return c.Next()
+	})
+	NewAuthHandler(mockMiddleWare, ts.mockAuthUseCase, ts.mockValidator, ts.fiber)
 }
 
 // Body가 없거나 QueryString 혹은 Param이 고정인 경우 여기서 공통으로 사용.
@@ -106,7 +116,7 @@ func (ts *AuthHDTestSuite) TestKakaoCallback() {
 			mock.AnythingOfType("string"),
 		).Return(&usecase.LoginResult{
 			AccessToken: "accessToken",
-		}, nil).Once()
+		}, nil, nil).Once()
 
 		// http.Response
 		resp, _ := ts.fiber.Test(ts.req)
@@ -121,7 +131,7 @@ func (ts *AuthHDTestSuite) TestKakaoCallback() {
 			mock.Anything,
 			mock.AnythingOfType("model.SocialType"),
 			mock.AnythingOfType("string"),
-		).Return(nil, errors.New("something")).Once()
+		).Return(nil, nil, errors.New("something")).Once()
 
 		// http.Response
 		resp, _ := ts.fiber.Test(ts.req)
@@ -133,6 +143,91 @@ func (ts *AuthHDTestSuite) TestKakaoCallback() {
 	})
 }
 
+func (ts *AuthHDTestSuite) TestSocialCallbackState() {
+	newReq := func(cookie string) *http.Request {
+		req := httptest.NewRequest(fiber.MethodGet, "/auth/kakao/callback?code=code&state=state", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "oauthState", Value: cookie})
+		}
+		return req
+	}
+
+	ts.Run("state 쿠키 없음", func() {
+		ts.mockValidator.On("ValidateStruct", mock.Anything).Return(nil).Once()
+
+		resp, _ := ts.fiber.Test(newReq(""))
+		result := utils.MakeErrorForTests(resp.Body)
+
+		ts.Equal(http.StatusBadRequest, resp.StatusCode)
+		ts.Equal(common.ErrOAuthStateInvalid, result.ErrCode)
+		ts.mockAuthUseCase.AssertNotCalled(ts.T(), "SocialLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	ts.Run("다른 브라우저에서 발급된 state", func() {
+		ts.mockValidator.On("ValidateStruct", mock.Anything).Return(nil).Once()
+
+		resp, _ := ts.fiber.Test(newReq("other"))
+		result := utils.MakeErrorForTests(resp.Body)
+
+		ts.Equal(http.StatusBadRequest, resp.StatusCode)
+		ts.Equal(common.ErrOAuthStateInvalid, result.ErrCode)
+		ts.mockAuthUseCase.AssertNotCalled(ts.T(), "SocialLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	ts.Run("state 일치", func() {
+		ts.mockValidator.On("ValidateStruct", mock.Anything).Return(nil).Once()
+		ts.mockAuthUseCase.On("SocialLogin", mock.Anything, "kakao", "code", "state", mock.Anything).
+			Return(&usecase.LoginResult{AccessToken: "accessToken"}, nil, nil).Once()
+
+		resp, _ := ts.fiber.Test(newReq("state"))
+
+		ts.Equal(http.StatusFound, resp.StatusCode)
+		ts.Contains(resp.Header.Values("Set-Cookie")[0], "oauthState=;")
+	})
+}
+
+func (ts *AuthHDTestSuite) TestSocialCallbackTwoFactor() {
+	challengeToken := strings.Repeat("ab", 32)
+
+	ts.Run("challenge 는 스크립트로 읽을 수 없는 쿠키로", func() {
+		ts.mockValidator.On("ValidateStruct", mock.Anything).Return(nil).Once()
+		ts.mockAuthUseCase.On("SocialLogin", mock.Anything, "kakao", "code", "state", mock.Anything).
+			Return(nil, &usecase.TwoFactorChallenge{ChallengeToken: challengeToken, ExpiredAt: time.Now().Add(5 * time.Minute)}, nil).Once()
+
+		req := httptest.NewRequest(fiber.MethodGet, "/auth/kakao/callback?code=code&state=state", nil)
+		req.AddCookie(&http.Cookie{Name: "oauthState", Value: "state"})
+		resp, _ := ts.fiber.Test(req)
+
+		ts.Equal(http.StatusFound, resp.StatusCode)
+		var challenge *http.Cookie
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == "twoFactorChallenge" {
+				challenge = cookie
+			}
+		}
+		ts.Require().NotNil(challenge)
+		ts.Equal(challengeToken, challenge.Value)
+		ts.Equal("/auth", challenge.Path)
+		ts.True(challenge.HttpOnly)
+		ts.True(challenge.Secure)
+	})
+
+	ts.Run("body 에 challengeToken 이 없으면 쿠키 사용", func() {
+		ts.mockValidator.On("ValidateStruct", mock.Anything).Return(nil).Once()
+		ts.mockAuthUseCase.On("VerifyTwoFactor", mock.Anything, mock.MatchedBy(func(body *request.AuthTwoFactorLoginBody) bool {
+			return body.ChallengeToken == challengeToken && body.Code == "123456"
+		}), mock.Anything).Return(&usecase.LoginResult{AccessToken: "accessToken"}, nil).Once()
+
+		req := httptest.NewRequest(fiber.MethodPost, "/auth/login/two-factor", bytes.NewBufferString(`{"code":"123456"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "twoFactorChallenge", Value: challengeToken})
+		resp, _ := ts.fiber.Test(req)
+
+		ts.Equal(http.StatusOK, resp.StatusCode)
+		ts.Contains(resp.Header.Get("Set-Cookie"), "twoFactorChallenge=;")
+	})
+}
+
 func (ts *AuthHDTestSuite) TestSignUp() {
 	ts.Run("Success", func() {
 		inputData := `{
@@ -164,3 +259,40 @@ func (ts *AuthHDTestSuite) TestSignUp() {
 func TestAuthHDTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthHDTestSuite))
 }
+
+// 실제 라우터처럼 /api/v1 아래에 붙이고, /url 응답의 쿠키를 브라우저처럼 경로에 맞춰 /callback 에 보낸다.
+func TestSocialStateCookieFlow(t *testing.T) {
+	mockAuthUseCase := new(mocks.AuthUseCase)
+	mockValidator := new(pkgMock.Validator)
+	mockMiddleWare := new(mocks.MiddleWare)
+	mockMiddleWare.On("RateLimit", mock.AnythingOfType("string")).Return(func(c *fiber.Ctx) error {
+		return c.Next()
+	})
+	app := fiber.New()
+	NewAuthHandler(mockMiddleWare, mockAuthUseCase, mockValidator, app.Group("/api/v1"))
+
+	mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+	mockAuthUseCase.On("SocialLoginRedirectUrl", mock.Anything, "kakao").
+		Return("https://kauth.kakao.com/oauth/authorize?state=issued", "issued", nil).Once()
+	mockAuthUseCase.On("SocialLogin", mock.Anything, "kakao", "code", "issued", mock.Anything).
+		Return(&usecase.LoginResult{AccessToken: "accessToken"}, nil, nil).Once()
+
+	jar, _ := cookiejar.New(nil)
+	urlResp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "http://onthemat.com/api/v1/auth/kakao/url", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, urlResp.StatusCode)
+	jar.SetCookies(&url.URL{Scheme: "http", Host: "onthemat.com", Path: "/api/v1/auth/kakao/url"}, urlResp.Cookies())
+
+	callbackUrl, _ := url.Parse("http://onthemat.com/api/v1/auth/kakao/callback?code=code&state=issued")
+	req := httptest.NewRequest(fiber.MethodGet, callbackUrl.String(), nil)
+	cookies := jar.Cookies(callbackUrl)
+	assert.Len(t, cookies, 1)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	mockAuthUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"net/http"
+
+	ex "onthemat/internal/app/common"
+	"onthemat/internal/app/delivery/middlewares"
+	"onthemat/internal/app/transport/response"
+	"onthemat/internal/app/usecase"
+	"onthemat/internal/app/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type calendarHandler struct {
+	calendarUsecase usecase.CalendarUsecase
+}
+
+func NewCalendarHandler(
+	middleware middlewares.MiddleWare,
+	calendarUsecase usecase.CalendarUsecase,
+	router fiber.Router,
+) {
+	handler := &calendarHandler{
+		calendarUsecase: calendarUsecase,
+	}
+
+	// 캘린더 구독 토큰 발급
+	router.Post("/user/me/calendar-token", middleware.Auth, handler.IssueToken)
+	// 선생님 대강 일정 캘린더
+	router.Get("/teacher/me/calendar.ics", handler.TeacherCalendar)
+	// 학원 대강 모집 일정 캘린더
+	router.Get("/academy/me/calendar.ics", handler.AcademyCalendar)
+}
+
+// 캘린더 구독 토큰 발급
+/**
+@api {post} /user/me/calendar-token 캘린더 구독 토큰 발급
+@apiName postCalendarToken
+@apiVersion 1.0.0
+@apiGroup user
+@apiDescription 캘린더 앱(구글, 애플 등)에서 구독할 수 있는 .ics 피드 토큰을 발급한다.
+재발급 시 기존 토큰은 더 이상 사용할 수 없다.
+@apiHeader Authorization accessToken (Bearer)
+@apiSuccess (201) {Number} code 201
+@apiSuccess (201) {String} message ""
+@apiSuccess (201) {Object} result
+@apiSuccess (201) {String} result.token 구독 토큰
+@apiSuccess (201) {String} result.teacherPath 선생님 캘린더 경로
+@apiSuccess (201) {String} result.academyPath 학원 캘린더 경로
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *calendarHandler) IssueToken(c *fiber.Ctx) error {
+	ctx := c.Context()
+	userId := ctx.UserValue("user_id").(int)
+
+	token, err := h.calendarUsecase.IssueToken(ctx, userId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(ex.ResponseWithData{
+		Code:    http.StatusCreated,
+		Message: "",
+		Result:  response.NewCalendarTokenResponse(token),
+	})
+}
+
+// 선생님 대강 일정 캘린더
+/**
+@api {get} /teacher/me/calendar.ics 선생님 대강 일정 캘린더
+@apiName getTeacherCalendar
+@apiVersion 1.0.0
+@apiGroup teacher
+@apiDescription 합격한 대강 일정을 iCalendar(.ics) 형식으로 반환한다.
+@apiQuery {String} token 캘린더 구독 토큰
+@apiSuccess {String} body text/calendar
+@apiError QueryStringMissing <code>400</code> code: 3001
+@apiError TokenInvalid <code>401</code> code: 3007
+@apiError OnlyTeacher <code>403</code> code: 6005
+@apiError TeacherNotFound <code>404</code> code: 5005
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *calendarHandler) TeacherCalendar(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrQueryStringMissing, nil))
+	}
+
+	result, err := h.calendarUsecase.TeacherCalendar(c.Context(), token)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return sendCalendar(c, result)
+}
+
+// 학원 대강 모집 일정 캘린더
+/**
+@api {get} /academy/me/calendar.ics 학원 대강 모집 일정 캘린더
+@apiName getAcademyCalendar
+@apiVersion 1.0.0
+@apiGroup academy
+@apiDescription 학원이 작성한 대강 모집 일정을 iCalendar(.ics) 형식으로 반환한다.
+합격자가 있는 일정은 CONFIRMED, 모집중인 일정은 TENTATIVE 로 표시된다.
+@apiQuery {String} token 캘린더 구독 토큰
+@apiSuccess {String} body text/calendar
+@apiError QueryStringMissing <code>400</code> code: 3001
+@apiError TokenInvalid <code>401</code> code: 3007
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError AcademyNotFound <code>404</code> code: 5003
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *calendarHandler) AcademyCalendar(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrQueryStringMissing, nil))
+	}
+
+	result, err := h.calendarUsecase.AcademyCalendar(c.Context(), token)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return sendCalendar(c, result)
+}
+
+func sendCalendar(c *fiber.Ctx, body []byte) error {
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="calendar.ics"`)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Status(http.StatusOK).Send(body)
+}
@@ -5,6 +5,7 @@ import (
 
 	ex "onthemat/internal/app/common"
 	"onthemat/internal/app/delivery/middlewares"
+	"onthemat/internal/app/model"
 	"onthemat/internal/app/transport/request"
 	"onthemat/internal/app/transport/response"
 	"onthemat/internal/app/usecase"
@@ -41,6 +42,28 @@ func NewRecruitmentHandler(
 	g.Delete("/:id", middleware.Auth, middleware.OnlyAcademy, handler.SoftDelete)
 	g.Get("/list", handler.List)
 	g.Get("/:id", handler.Get)
+
+	// 대강 지원
+	g.Post("/:id/instead/:insteadId/apply", middleware.Auth, middleware.OnlyTeacher, handler.Apply)
+	// 지원 전 합격한 대강과의 스케쥴 충돌 확인
+	g.Get("/:id/instead/:insteadId/conflicts", middleware.Auth, middleware.OnlyTeacher, handler.ScheduleConflicts)
+	// 대강 지원 취소
+	g.Delete("/:id/instead/:insteadId/apply", middleware.Auth, middleware.OnlyTeacher, handler.CancelApply)
+	// 대강 지원자 리스트
+	g.Get("/:id/instead/:insteadId/applicants", middleware.Auth, middleware.OnlyAcademy, handler.ApplicantList)
+	// 지원자 서류 통과
+	g.Post("/:id/instead/:insteadId/applicants/:teacherId/shortlist", middleware.Auth, middleware.OnlyAcademy, handler.Shortlist)
+	// 지원자 합격
+	g.Post("/:id/instead/:insteadId/applicants/:teacherId/accept", middleware.Auth, middleware.OnlyAcademy, handler.Accept)
+	// 지원자 불합격
+	g.Post("/:id/instead/:insteadId/applicants/:teacherId/reject", middleware.Auth, middleware.OnlyAcademy, handler.Reject)
+	// 합격 취소
+	g.Post("/:id/instead/:insteadId/applicants/:teacherId/cancel", middleware.Auth, middleware.OnlyAcademy, handler.CancelAccept)
+
+	// 대강 일정 개별 수정
+	g.Patch("/:id/instead/:insteadId/schedules/:scheduleId", middleware.Auth, middleware.OnlyAcademy, handler.UpdateSchedule)
+	// 대강 일정 개별 삭제
+	g.Delete("/:id/instead/:insteadId/schedules/:scheduleId", middleware.Auth, middleware.OnlyAcademy, handler.DeleteSchedule)
 }
 
 // 채용공고 생성
@@ -54,16 +77,28 @@ func NewRecruitmentHandler(
 @apiBody {Object} info
 @apiBody {Boolean} info.isOpen 오픈할 건지
 @apiBody {Object} insteadInfo
-@apiBody {String} insteadInfo.minCareer 최소 경력
-@apiBody {String} insteadInfo.pay 금액 (수업 당)
-@apiBody {Object} insteadInfo.schedules
+@apiBody {Number} [insteadInfo.minCareerMonth] 최소 경력 (개월, 0~600)
+@apiBody {Number} insteadInfo.payAmount 급여 금액
+@apiBody {String} [insteadInfo.payCurrency="KRW"] 급여 통화 (ISO 4217)
+@apiBody {String="class","hour","day"} insteadInfo.payUnit 급여 단위 (수업 당, 시간 당, 일 당)
+@apiBody {Object} [insteadInfo.schedules]
 @apiBody {String} insteadInfo.schedules.startDateTime 수업 시작 일시
 @apiBody {String} insteadInfo.schedules.endDateTime 수업 종료 일시
+@apiBody {Object} [insteadInfo.recurrence] 반복 규칙 (있으면 schedules 대신 규칙으로 일정을 생성)
+@apiBody {String="DAILY,WEEKLY"} insteadInfo.recurrence.frequency 반복 주기
+@apiBody {Number} [insteadInfo.recurrence.interval=1] 반복 간격
+@apiBody {String[]="MO,TU,WE,TH,FR,SA,SU"} [insteadInfo.recurrence.weekdays] 반복 요일
+@apiBody {Number} [insteadInfo.recurrence.count] 반복 횟수 (최대 100, until 과 둘 중 하나는 필수)
+@apiBody {String} [insteadInfo.recurrence.until] 반복 종료 일시 (첫 수업 시작 일시 이후)
+@apiBody {String} insteadInfo.recurrence.startDateTime 첫 수업 시작 일시 (Asia/Seoul 기준)
+@apiBody {String} insteadInfo.recurrence.endDateTime 첫 수업 종료 일시
+@apiBody {String[]} [insteadInfo.recurrence.exDates] 제외할 일정의 시작 일시
 @apiSuccess (201) {Number} code 201
 @apiSuccess (201) {String} message ""
 @apiError JsonMissing <code>400</code> code: 3000
 @apiError ValidationError <code>400</code> code: 2xxx
 @apiError Conflict <code>400</code> code: 4000
+@apiError RecurrenceInvalid <code>400</code> code: 3011
 @apiError ResourceUnOwned <code>400</code> code: 4010
 @apiError InternalServerError <code>500</code> code: 500
 */
@@ -105,17 +140,29 @@ func (h *recruitmentHandler) Create(c *fiber.Ctx) error {
 @apiBody {Boolean} info.isOpen 오픈할 건지
 @apiBody {Boolean} info.isFinish 채용공고가 종료됐는지
 @apiBody {Object} [insteadInfo]
-@apiBody {String} insteadInfo.minCareer 최소 경력
-@apiBody {String} insteadInfo.pay 금액 (수업 당)
-@apiBody {Object} insteadInfo.schedules
+@apiBody {Number} [insteadInfo.minCareerMonth] 최소 경력 (개월, 0~600)
+@apiBody {Number} insteadInfo.payAmount 급여 금액
+@apiBody {String} [insteadInfo.payCurrency="KRW"] 급여 통화 (ISO 4217)
+@apiBody {String="class","hour","day"} insteadInfo.payUnit 급여 단위 (수업 당, 시간 당, 일 당)
+@apiBody {Object} [insteadInfo.schedules]
 @apiBody {String} insteadInfo.schedules.startDateTime 수업 시작 일시
 @apiBody {String} insteadInfo.schedules.endDateTime 수업 종료 일시
+@apiBody {Object} [insteadInfo.recurrence] 반복 규칙 (있으면 schedules 대신 규칙으로 일정을 생성)
+@apiBody {String="DAILY,WEEKLY"} insteadInfo.recurrence.frequency 반복 주기
+@apiBody {Number} [insteadInfo.recurrence.interval=1] 반복 간격
+@apiBody {String[]="MO,TU,WE,TH,FR,SA,SU"} [insteadInfo.recurrence.weekdays] 반복 요일
+@apiBody {Number} [insteadInfo.recurrence.count] 반복 횟수 (최대 100, until 과 둘 중 하나는 필수)
+@apiBody {String} [insteadInfo.recurrence.until] 반복 종료 일시 (첫 수업 시작 일시 이후)
+@apiBody {String} insteadInfo.recurrence.startDateTime 첫 수업 시작 일시 (Asia/Seoul 기준)
+@apiBody {String} insteadInfo.recurrence.endDateTime 첫 수업 종료 일시
+@apiBody {String[]} [insteadInfo.recurrence.exDates] 제외할 일정의 시작 일시
 @apiSuccess (200 or 201) {Number} code 200 or 201
 @apiSuccess (201) {String} message ""
 @apiError JsonMissing <code>400</code> code: 3000
 @apiError ParamsMissing <code>400</code> code: 3002
 @apiError ValidationError <code>400</code> code: 2xxx
 @apiError Conflict <code>400</code> code: 4000
+@apiError RecurrenceInvalid <code>400</code> code: 3011
 @apiError ResourceUnOwned <code>400</code> code: 4010
 @apiError InternalServerError <code>500</code> code: 500
 */
@@ -165,9 +212,11 @@ func (h *recruitmentHandler) Update(c *fiber.Ctx) error {
 @apiBody {Boolean} [info.isOpen] 오픈할 건지
 @apiBody {Boolean} [info.isFinish] 채용공고가 종료됐는지
 @apiBody {Object} [insteadInfo]
-@apiBody {String} [insteadInfo.minCareer] 최소 경력
-@apiBody {String} [insteadInfo.pay] 금액 (수업 당)
-@apiBody {Object} [insteadInfo.schedules]
+@apiBody {Number} [insteadInfo.minCareerMonth] 최소 경력 (개월, 0~600)
+@apiBody {Number} [insteadInfo.payAmount] 급여 금액
+@apiBody {String} [insteadInfo.payCurrency] 급여 통화 (ISO 4217)
+@apiBody {String="class","hour","day"} [insteadInfo.payUnit] 급여 단위 (수업 당, 시간 당, 일 당)
+@apiBody {Object[]} [insteadInfo.schedules] 주면 기존 일정을 모두 지우고 다시 생성합니다. (반복 규칙 해제)
 @apiBody {String} [insteadInfo.schedules.startDateTime] 수업 시작 일시
 @apiBody {String} [insteadInfo.schedules.endDateTime] 수업 종료 일시
 @apiSuccess (200 or 201) {Number} code 200 or 201
@@ -263,13 +312,18 @@ func (h *recruitmentHandler) SoftDelete(c *fiber.Ctx) error {
 @apiSuccess {String} result.isFinish 종료 여부
 @apiSuccess {Object} result.insteadInfo 대강 정보
 @apiSuccess {Number} result.insteadInfo.id 대강 아이디
-@apiSuccess {String} result.insteadInfo.minCareer 최소경력
-@apiSuccess {String} result.insteadInfo.pay 급여
+@apiSuccess {Number} result.insteadInfo.minCareerMonth 최소경력 (개월)
+@apiSuccess {Number} result.insteadInfo.payAmount 급여 금액
+@apiSuccess {String} result.insteadInfo.payCurrency 급여 통화
+@apiSuccess {String="class","hour","day"} result.insteadInfo.payUnit 급여 단위
 @apiSuccess {Number} [result.insteadInfo.passerId] 합격자 아이디
 @apiSuccess {Number} result.insteadInfo.applicantCount 지원자 총 명수
+@apiSuccess {Object} [result.insteadInfo.recurrence] 반복 규칙
 @apiSuccess {Object[]} [result.insteadInfo.schedules] 스케쥴
+@apiSuccess {Number} result.insteadInfo.schedules.id 스케쥴 아이디
 @apiSuccess {String} result.insteadInfo.schedules.startDateTime 시작 일시
 @apiSuccess {String} result.insteadInfo.schedules.endDateTime 종료 일시
+@apiSuccess {String} [result.insteadInfo.schedules.originalStartDateTime] 반복 규칙으로 생성된 원래 시작 일시
 @apiSuccess {Object[]} [result.insteadInfo.yogas] 요가 정보
 @apiSuccess {Number} result.insteadInfo.yogas.id 요가 아이디
 @apiSuccess {Number} result.insteadInfo.yogas.name 요가 이름
@@ -311,10 +365,18 @@ func (h *recruitmentHandler) Get(c *fiber.Ctx) error {
 @apiHeader Authorization accessToken (Bearer)
 @apiQuery {Number} [pageNo] 페이지 번호
 @apiQuery {Number} [pageSize] 페이지당 문서 개수
-@apiQuery {String} [startDateTime] 시작일시
+@apiQuery {String} [startDateTime] 시작일시 (endDateTime과 함께 보내면 구간과 겹치는 대강이 있는 공고만 조회)
 @apiQuery {String} [endDateTime] 종료일시
 @apiQuery {Number[]} [yogaIds] 요가 아이디
 @apiQuery {Number[]} [sigunguIds] 시군구 아이디
+@apiQuery {Number} [minPay] 최소 급여 (급여 금액이 이 이상인 대강이 있는 공고만 조회)
+@apiQuery {String="class","hour","day"} [payUnit] 급여 단위 (minPay 를 주거나 PAY 로 정렬할 때 필수, 같은 단위의 대강끼리만 비교)
+@apiQuery {Number} [maxCareer] 경력 (개월, 최소 경력 조건이 이 이하인 대강이 있는 공고만 조회)
+@apiQuery {Boolean} [isFinish] 마감 여부
+@apiQuery {Boolean} [hasOpenSlot] 합격자가 정해지지 않은 대강이 있는지 여부
+@apiQuery {Number[]} [sidoIds] 시도 아이디
+@apiQuery {String="START","NEWEST","PAY","UPDATED"} [orderCol="UPDATED"] 정렬 기준 (START: 남은 일정 중 가장 빠른 시작 일시, NEWEST: 작성일시, PAY: payUnit 단위 대강 중 가장 높은 급여, UPDATED: 수정일시)
+@apiQuery {String="DESC","ASC"} [orderType="DESC"] 정렬 방향
 @apiSuccess (200) {Number} code 200
 @apiSuccess (200) {String} message ""
 @apiSuccess {Object[]} result
@@ -323,8 +385,15 @@ func (h *recruitmentHandler) Get(c *fiber.Ctx) error {
 @apiSuccess {String[]} result.yogas 요가 이름
 @apiSuccess {String} result.sigungu 시군구 이름
 @apiSuccess {String[]} result.startDateTimes 시작 일시
+@apiSuccess {Object[]} result.pays 대강별 급여
+@apiSuccess {Number} result.pays.amount 급여 금액
+@apiSuccess {String} result.pays.currency 급여 통화
+@apiSuccess {String} result.pays.unit 급여 단위
+@apiSuccess {Boolean} result.isFinish 마감 여부
+@apiSuccess {Number} result.openSlotCount 합격자가 정해지지 않은 대강 수
 @apiSuccess {String} result.createdAt 생성일시
 @apiSuccess {String} result.updatedAt 업데이트일시
+@apiError ReqeustsInvalid <code>400</code> code: 2000 (급여 조건, 정렬에 payUnit 이 없을 때)
 @apiError ParamsMissing <code>400</code> code: 3002
 @apiError RecruitmentNotFound <code>400</code> code: 5006
 @apiError InternalServerError <code>500</code> code: 500
@@ -355,3 +424,382 @@ func (h *recruitmentHandler) List(c *fiber.Ctx) error {
 		Pagination: pagination,
 	})
 }
+
+// 대강 지원
+/**
+@api {post} /recruitment/:id/instead/:insteadId/apply 대강 지원
+@apiName postRecruitmentApply
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 선생님이 대강에 지원
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiSuccess (201) {Number} code 201
+@apiSuccess (201) {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError AlreadyApplied <code>409</code> code: 4011
+@apiError RecruitmentAlreadyClosed <code>409</code> code: 4012
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError OnlyTeacher <code>403</code> code: 6005
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) Apply(c *fiber.Ctx) error {
+	ctx := c.Context()
+	teacherId := ctx.UserValue("teacher_id").(int)
+
+	reqParam := new(request.RecruitmentInsteadParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+
+	if err := h.recruitmentUsecase.Apply(ctx, reqParam.Id, reqParam.InsteadId, teacherId); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(ex.Response{
+		Code:    http.StatusCreated,
+		Message: "",
+	})
+}
+
+// 스케쥴 충돌 확인
+/**
+@api {get} /recruitment/:id/instead/:insteadId/conflicts 스케쥴 충돌 확인
+@apiName getRecruitmentScheduleConflicts
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 지원하려는 대강이 이미 합격한 대강들과 시간이 겹치는지 확인
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiSuccess {Object[]} result 겹치는 대강 (없으면 빈 배열)
+@apiSuccess {Number} result.recruitmentId 채용공고 아이디
+@apiSuccess {Number} result.insteadId 대강 아이디
+@apiSuccess {Object[]} result.schedules 겹치는 스케쥴
+@apiSuccess {String} result.schedules.startDateTime 시작 일시
+@apiSuccess {String} result.schedules.endDateTime 종료 일시
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError OnlyTeacher <code>403</code> code: 6005
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) ScheduleConflicts(c *fiber.Ctx) error {
+	ctx := c.Context()
+	teacherId := ctx.UserValue("teacher_id").(int)
+
+	reqParam := new(request.RecruitmentInsteadParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+
+	conflicts, err := h.recruitmentUsecase.ScheduleConflicts(ctx, reqParam.Id, reqParam.InsteadId, teacherId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.ResponseWithData{
+		Code:    http.StatusOK,
+		Message: "",
+		Result:  response.NewRecruitmentScheduleConflictResponse(conflicts),
+	})
+}
+
+// 대강 지원 취소
+/**
+@api {delete} /recruitment/:id/instead/:insteadId/apply 대강 지원 취소
+@apiName deleteRecruitmentApply
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 선생님이 대강 지원을 취소
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError ApplicationStatusInvalid <code>409</code> code: 4013
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError ApplicantNotFound <code>404</code> code: 5008
+@apiError OnlyTeacher <code>403</code> code: 6005
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) CancelApply(c *fiber.Ctx) error {
+	ctx := c.Context()
+	teacherId := ctx.UserValue("teacher_id").(int)
+
+	reqParam := new(request.RecruitmentInsteadParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+
+	if err := h.recruitmentUsecase.CancelApply(ctx, reqParam.Id, reqParam.InsteadId, teacherId); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.Response{
+		Code:    http.StatusOK,
+		Message: "",
+	})
+}
+
+// 대강 지원자 리스트 조회
+/**
+@api {get} /recruitment/:id/instead/:insteadId/applicants 대강 지원자 리스트 조회
+@apiName getRecruitmentApplicants
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 채용공고를 작성한 학원이 대강 지원자를 조회
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiSuccess {Object[]} result
+@apiSuccess {Number} result.id 선생님 아이디
+@apiSuccess {String} result.name 선생님 이름
+@apiSuccess {Number} [result.age] 나이
+@apiSuccess {String} [result.profileImageUrl] 대표 이미지 주소
+@apiSuccess {String} [result.introduce] 자기소개
+@apiSuccess {String="applied,shortlisted,accepted,rejected,withdrawn,cancelled"} result.status 지원 상태
+@apiSuccess {String} result.appliedAt 지원일시
+@apiSuccess {String} result.updatedAt 상태 변경일시
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError OnlyOwnUser <code>403</code> code: 6007
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) ApplicantList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	academyId := ctx.UserValue("academy_id").(int)
+
+	reqParam := new(request.RecruitmentInsteadParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+
+	applicants, err := h.recruitmentUsecase.ApplicantList(ctx, reqParam.Id, reqParam.InsteadId, academyId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.ResponseWithData{
+		Code:    http.StatusOK,
+		Message: "",
+		Result:  response.NewRecruitmentApplicantListResponse(applicants),
+	})
+}
+
+// 지원자 서류 통과
+/**
+@api {post} /recruitment/:id/instead/:insteadId/applicants/:teacherId/shortlist 지원자 서류 통과
+@apiName postRecruitmentApplicantShortlist
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 지원자를 후보(shortlisted) 상태로 변경
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiParam {Number} teacherId 지원한 선생님 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError ApplicationStatusInvalid <code>409</code> code: 4013
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError ApplicantNotFound <code>404</code> code: 5008
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError OnlyOwnUser <code>403</code> code: 6007
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) Shortlist(c *fiber.Ctx) error {
+	return h.changeApplicationStatus(c, model.ApplicationShortlisted)
+}
+
+// 지원자 합격
+/**
+@api {post} /recruitment/:id/instead/:insteadId/applicants/:teacherId/accept 지원자 합격
+@apiName postRecruitmentApplicantAccept
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 지원자를 합격시키고 대강의 합격자로 지정합니다. 대기 중인 나머지 지원자는 불합격 처리됩니다.
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiParam {Number} teacherId 지원한 선생님 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError ApplicationStatusInvalid <code>409</code> code: 4013
+@apiError InsteadAlreadyFilled <code>409</code> code: 4014
+@apiError ScheduleConflict <code>409</code> code: 4015 (details: 겹치는 대강 아이디들)
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError ApplicantNotFound <code>404</code> code: 5008
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError OnlyOwnUser <code>403</code> code: 6007
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) Accept(c *fiber.Ctx) error {
+	return h.changeApplicationStatus(c, model.ApplicationAccepted)
+}
+
+// 지원자 불합격
+/**
+@api {post} /recruitment/:id/instead/:insteadId/applicants/:teacherId/reject 지원자 불합격
+@apiName postRecruitmentApplicantReject
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 지원자를 불합격 처리
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiParam {Number} teacherId 지원한 선생님 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError ApplicationStatusInvalid <code>409</code> code: 4013
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError ApplicantNotFound <code>404</code> code: 5008
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError OnlyOwnUser <code>403</code> code: 6007
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) Reject(c *fiber.Ctx) error {
+	return h.changeApplicationStatus(c, model.ApplicationRejected)
+}
+
+// 합격 취소
+/**
+@api {post} /recruitment/:id/instead/:insteadId/applicants/:teacherId/cancel 합격 취소
+@apiName postRecruitmentApplicantCancel
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 합격을 취소하고 대강의 합격자 지정을 해제합니다.
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiParam {Number} teacherId 지원한 선생님 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError ApplicationStatusInvalid <code>409</code> code: 4013
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError ApplicantNotFound <code>404</code> code: 5008
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError OnlyOwnUser <code>403</code> code: 6007
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) CancelAccept(c *fiber.Ctx) error {
+	return h.changeApplicationStatus(c, model.ApplicationCancelled)
+}
+
+func (h *recruitmentHandler) changeApplicationStatus(c *fiber.Ctx, status model.ApplicationStatus) error {
+	ctx := c.Context()
+	academyId := ctx.UserValue("academy_id").(int)
+
+	reqParam := new(request.RecruitmentApplicantParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+
+	err := h.recruitmentUsecase.ChangeApplicationStatus(ctx, reqParam.Id, reqParam.InsteadId, reqParam.TeacherId, academyId, status)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.Response{
+		Code:    http.StatusOK,
+		Message: "",
+	})
+}
+
+// 대강 일정 개별 수정
+/**
+@api {patch} /recruitment/:id/instead/:insteadId/schedules/:scheduleId 대강 일정 개별 수정
+@apiName patchRecruitmentSchedule
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 반복 규칙으로 생성된 일정 중 하나만 옮길 때 사용합니다. 시리즈 전체 수정(PUT) 시에는 규칙대로 다시 생성됩니다.
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiParam {Number} scheduleId 스케쥴 아이디
+@apiBody {String} startDateTime 수업 시작 일시
+@apiBody {String} endDateTime 수업 종료 일시
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiError ReqeustsInvalid <code>400</code> code: 2000
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError ScheduleNotFound <code>404</code> code: 5009
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError OnlyOwnUser <code>403</code> code: 6007
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) UpdateSchedule(c *fiber.Ctx) error {
+	ctx := c.Context()
+	academyId := ctx.UserValue("academy_id").(int)
+
+	reqBody := new(request.RecruitmentScheduleBody)
+	if err := fiberx.BodyParser(c, reqBody); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrJsonMissing, err.Error()))
+	}
+
+	reqParam := new(request.RecruitmentScheduleParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+
+	err := h.recruitmentUsecase.UpdateSchedule(ctx, reqBody, reqParam.Id, reqParam.InsteadId, reqParam.ScheduleId, academyId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.Response{
+		Code:    http.StatusOK,
+		Message: "",
+	})
+}
+
+// 대강 일정 개별 삭제
+/**
+@api {delete} /recruitment/:id/instead/:insteadId/schedules/:scheduleId 대강 일정 개별 삭제
+@apiName deleteRecruitmentSchedule
+@apiVersion 1.0.0
+@apiGroup recruitment
+@apiDescription 반복 규칙으로 생성된 일정이면 규칙의 제외 일정(exDates)에 추가됩니다.
+@apiHeader Authorization accessToken (Bearer)
+@apiParam {Number} id 채용공고 아이디
+@apiParam {Number} insteadId 대강 아이디
+@apiParam {Number} scheduleId 스케쥴 아이디
+@apiSuccess (200) {Number} code 200
+@apiSuccess (200) {String} message ""
+@apiError ParamsMissing <code>400</code> code: 3002
+@apiError InsteadNotFound <code>404</code> code: 5007
+@apiError ScheduleNotFound <code>404</code> code: 5009
+@apiError OnlyAcademy <code>403</code> code: 6004
+@apiError OnlyOwnUser <code>403</code> code: 6007
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *recruitmentHandler) DeleteSchedule(c *fiber.Ctx) error {
+	ctx := c.Context()
+	academyId := ctx.UserValue("academy_id").(int)
+
+	reqParam := new(request.RecruitmentScheduleParam)
+	if err := c.ParamsParser(reqParam); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrParamsMissing, nil))
+	}
+
+	err := h.recruitmentUsecase.DeleteSchedule(ctx, reqParam.Id, reqParam.InsteadId, reqParam.ScheduleId, academyId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.Response{
+		Code:    http.StatusOK,
+		Message: "",
+	})
+}
@@ -38,6 +38,8 @@ func NewTeacherHandler(
 	g.Put("/:id", middleware.Auth, middleware.OnlyTeacher, handler.Update)
 	// 선생님 부분 수정
 	g.Patch("/:id", middleware.Auth, middleware.OnlyTeacher, handler.Patch)
+	// 선생님 리스트 조회
+	g.Get("/list", handler.List)
 	// 선생님 상세 조회
 	g.Get("/:id", middleware.Auth, handler.Get)
 }
@@ -337,3 +339,66 @@ func (h *teacherHandler) Get(c *fiber.Ctx) error {
 		Result:  resp,
 	})
 }
+
+// 선생님 리스트 조회
+/**
+@api {get} /teacher/list 선생님 리스트 조회
+@apiName listTeacher
+@apiVersion 1.0.0
+@apiGroup teacher
+@apiDescription 프로필을 공개한 선생님 리스트 조회
+@apiQuery {Number} [pageNo=1] 페이지 번호
+@apiQuery {Number} [pageSize=10] 페이지 당 문서 개수
+@apiQuery {Number} [yogaIds] 필터 요가 id ,로 멀티
+@apiQuery {Number} [sigunguIds] 필터 근무 가능 시군구 id ,로 멀티
+@apiQuery {Number} [minCareerYear] 필터 최소 경력 (년)
+@apiQuery {String} [agencyName] 필터 자격증 기관명
+@apiQuery {String="NAME,ID"} [orderCol="ID"] 정렬기준 컬럼 이름
+@apiQuery {String="ASC,DESC"} [orderType="DESC"] 정렬기준 방법
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object[]} result
+@apiSuccess {Number} result.id 아이디
+@apiSuccess {String} result.name 선생님 이름
+@apiSuccess {String} [result.age] 나이
+@apiSuccess {String} [result.introduce] 자기소개
+@apiSuccess {String} [result.profileImageUrl] 프로필 이미지 주소
+@apiSuccess {Object[]} [result.yoga] 요가
+@apiSuccess {Number} result.yoga.id 요가 아이디
+@apiSuccess {String} result.yoga.nameKor 요가 한글 이름
+@apiSuccess {Object[]} [result.possibleWorkSigungu] 근무 가능한 지역
+@apiSuccess {Number} result.possibleWorkSigungu.id 근무 가능한 지역 아이디
+@apiSuccess {String} result.possibleWorkSigungu.name 시군구 이름
+@apiSuccess {String} result.createdAt 생성일시
+@apiSuccess {String} result.updatedAt 업데이트일시
+@apiError QueryMissing <code>400</code> code: 3001
+@apiError ValidationError <code>400</code> code: 2xxx
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *teacherHandler) List(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	reqQueries := request.NewTeacherListQueries()
+
+	if err := fiberx.QueryParser(c, reqQueries); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewHttpError(ex.ErrQueryStringMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(reqQueries); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ex.NewInvalidInputError(err))
+	}
+
+	teachers, pagination, err := h.teacherUsecase.List(ctx, reqQueries)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	resp := response.NewTeacherListResponse(teachers)
+
+	return c.Status(http.StatusOK).JSON(ex.ResponseWithPagination{
+		Code:       http.StatusOK,
+		Message:    "",
+		Result:     resp,
+		Pagination: pagination,
+	})
+}
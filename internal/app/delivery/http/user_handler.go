@@ -10,28 +10,40 @@ import (
 	"onthemat/internal/app/transport/response"
 	"onthemat/internal/app/usecase"
 	"onthemat/internal/app/utils"
+	"onthemat/pkg/validatorx"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type UserHandler struct {
 	UserUseCase usecase.UserUseCase
+	AuthUseCase usecase.AuthUseCase
 	Router      fiber.Router
 	Middleware  middlewares.MiddleWare
+	Validator   validatorx.Validator
 }
 
 func NewUserHandler(
 	middleware middlewares.MiddleWare,
 	userUseCase usecase.UserUseCase,
+	authUseCase usecase.AuthUseCase,
+	validator validatorx.Validator,
 	router fiber.Router,
 ) {
 	handler := &UserHandler{
 		UserUseCase: userUseCase,
+		AuthUseCase: authUseCase,
 		Middleware:  middleware,
+		Validator:   validator,
 	}
 	g := router.Group("/user")
 	// 유저 정보 조회
 	g.Get("/me", middleware.Auth, handler.GetMe)
+	// 탈퇴
+	g.Delete("/me", middleware.Auth, handler.Withdraw)
+	// 휴대폰 번호 인증
+	g.Post("/phone/verify/request", middleware.RateLimit(middlewares.RateLimitSms), middleware.Auth, handler.RequestPhoneVerification)
+	g.Post("/phone/verify/confirm", middleware.Auth, handler.ConfirmPhoneVerification)
 	g.Put("/:id", middleware.Auth, handler.Update)
 }
 
@@ -49,10 +61,16 @@ func NewUserHandler(
 @apiSuccess {Number} result.id 아이디
 @apiSuccess {String} [result.email] 이메일
 @apiSuccess {String} [result.nickname] 닉네임
-@apiSuccess {String="kakao,google,naver"} [result.social_name] 소셜로그인 타입
-@apiSuccess {String} [result.social_key] 소셜 고유 Key
+@apiSuccess {String="kakao,google,naver"} [result.social_name] 처음 연결한 소셜로그인 타입
+@apiSuccess {String} [result.social_key] 처음 연결한 소셜 고유 Key
+@apiSuccess {Object[]} result.identities 연결된 소셜 계정 목록
+@apiSuccess {String="kakao,google,naver"} result.identities.provider 소셜로그인 타입
+@apiSuccess {String} [result.identities.email] 소셜 계정 이메일
+@apiSuccess {String} result.identities.linkedAt 연결 일시
 @apiSuccess {String="academy,teacher,superAdmin"} [result.type] 유저 타입
-@apiSuccess {String} [result.phone_num] 이메일
+@apiSuccess {String} [result.phone_num] 휴대폰 번호
+@apiSuccess {Boolean} result.is_phone_verified 휴대폰 번호 인증 여부
+@apiSuccess {Boolean} result.is_two_factor_enabled 2단계 인증 사용 여부
 @apiSuccess {String} result.createdAt 생성일시
 @apiSuccess {String} result.updatedAt 업데이트일시
 @apiError UserNotFound <code>404</code> code: 5001
@@ -76,6 +94,140 @@ func (h *UserHandler) GetMe(c *fiber.Ctx) error {
 	})
 }
 
+// 탈퇴
+/**
+@api {delete} /user/me 탈퇴
+@apiName withdraw
+@apiVersion 1.0.0
+@apiGroup user
+@apiDescription 탈퇴하고 모든 기기에서 로그아웃한다. 학원의 지난 공고, 선생님의 합격 이력은 남는다.
+유예 기간(restorableUntil) 안에 로그인하면 계정을 복구할 수 있고(/auth/restore), 이후에는 개인정보(이메일, 휴대폰 번호, 닉네임, 소셜 키)가 익명화된다.
+@apiHeader Authorization accessToken (Bearer)
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object} result
+@apiSuccess {String} result.deletedAt 탈퇴 일시
+@apiSuccess {String} result.restorableUntil 복구 가능 기한
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError SessionRevoked <code>401</code> code: 6009
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *UserHandler) Withdraw(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	userId := ctx.UserValue("user_id").(int)
+
+	result, err := h.AuthUseCase.Withdraw(ctx, userId)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.ResponseWithData{
+		Code:    200,
+		Message: "",
+		Result:  result,
+	})
+}
+
+// 휴대폰 인증번호 요청
+/**
+@api {post} /user/phone/verify/request 휴대폰 인증번호 요청
+@apiName requestPhoneVerification
+@apiVersion 1.0.0
+@apiGroup user
+@apiDescription 입력한 휴대폰 번호로 6자리 인증번호를 문자로 보낸다. 인증번호는 3분 동안 유효하다.
+1분에 한 번, 하루에 유저와 휴대폰 번호별로 5번까지 요청할 수 있다.
+@apiHeader Authorization accessToken (Bearer)
+@apiBody {String} phone_num 하이픈 없는 휴대폰 번호
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiSuccess {Object} result
+@apiSuccess {String} result.expiredAt 인증번호 만료 일시
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ValidationError <code>400</code> code: 2000
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError TooManyRequests <code>429</code> code: 8000 (details: retryAfter, Retry-After 헤더)
+@apiError PhoneVerifyCooldown <code>429</code> code: 8001
+@apiError PhoneVerifyAttemptsExceeded <code>429</code> code: 8002
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *UserHandler) RequestPhoneVerification(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	body := new(request.UserPhoneVerifyRequestBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	userId := ctx.UserValue("user_id").(int)
+
+	result, err := h.UserUseCase.RequestPhoneVerification(ctx, userId, body)
+	if err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.ResponseWithData{
+		Code:    200,
+		Message: "",
+		Result:  result,
+	})
+}
+
+// 휴대폰 인증번호 확인
+/**
+@api {post} /user/phone/verify/confirm 휴대폰 인증번호 확인
+@apiName confirmPhoneVerification
+@apiVersion 1.0.0
+@apiGroup user
+@apiDescription 인증번호가 맞으면 인증한 번호를 내 휴대폰 번호로 저장하고 인증 완료로 표시한다.
+인증번호 하나로 5번까지 틀릴 수 있고, 넘으면 인증번호를 다시 요청해야 한다.
+@apiHeader Authorization accessToken (Bearer)
+@apiBody {String} code 6자리 인증번호
+@apiSuccess {Number} code 200
+@apiSuccess {String} message ""
+@apiError JsonMissing <code>400</code> code: 3000
+@apiError ValidationError <code>400</code> code: 2000
+@apiError PhoneVerifyCodeInvalid <code>400</code> code: 3017 (details.remainingAttempts)
+@apiError PhoneVerifyCodeExpired <code>400</code> code: 3018
+@apiError TokenExpired <code>401</code> code: 6002
+@apiError UserNotFound <code>404</code> code: 5001
+@apiError InternalServerError <code>500</code> code: 500
+*/
+func (h *UserHandler) ConfirmPhoneVerification(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	body := new(request.UserPhoneVerifyConfirmBody)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(http.StatusBadRequest).
+			JSON(ex.NewHttpError(ex.ErrJsonMissing, nil))
+	}
+
+	if err := h.Validator.ValidateStruct(body); err != nil {
+		return c.
+			Status(http.StatusBadRequest).
+			JSON(ex.NewInvalidInputError(err))
+	}
+
+	userId := ctx.UserValue("user_id").(int)
+
+	if err := h.UserUseCase.ConfirmPhoneVerification(ctx, userId, body); err != nil {
+		return utils.NewError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ex.Response{
+		Code:    200,
+		Message: "",
+	})
+}
+
 func (h *UserHandler) Update(c *fiber.Ctx) error {
 	ctx := c.Context()
 	param := new(request.UserUpdateParam)
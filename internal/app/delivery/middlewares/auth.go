@@ -34,9 +34,22 @@ func (m *middleWare) Auth(c *fiber.Ctx) error {
 			JSON(ex.NewHttpError(ex.ErrTokenInvalid, nil))
 	}
 
+	// 로그아웃, 폐기된 세션의 토큰은 만료 전이라도 사용할 수 없다.
+	isActive, err := m.sessions.IsActive(ctx, claim.UserId, claim.Uuid)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).
+			JSON(ex.NewHttpError(ex.ErrInternalError, nil))
+	}
+	if !isActive {
+		return c.Status(http.StatusUnauthorized).
+			JSON(ex.NewHttpError(ex.ErrSessionRevoked, nil))
+	}
+
 	ctx.SetUserValue("login_type", claim.LoginType)
 	ctx.SetUserValue("user_type", claim.UserType)
 	ctx.SetUserValue("user_id", claim.UserId)
+	ctx.SetUserValue("session_id", claim.Uuid)
+	ctx.SetUserValue("two_factor", claim.TwoFactor)
 	return c.Next()
 }
 
@@ -49,6 +62,21 @@ func (m *middleWare) OnlySuperAdmin(c *fiber.Ctx) error {
 			JSON(ex.NewHttpError(ex.ErrOnlySuperAdmin, nil))
 	}
 
+	// 관리 기능은 2단계 인증을 켠 뒤에만 사용할 수 있다.
+	user, err := m.userRepo.Get(c.Context(), c.Context().UserValue("user_id").(int))
+	if err != nil {
+		return c.
+			Status(http.StatusForbidden).
+			JSON(ex.NewHttpError(ex.ErrOnlySuperAdmin, nil))
+	}
+	// 2단계 인증을 켰더라도 비밀번호만으로 로그인한 세션은 사용할 수 없다.
+	twoFactor, _ := c.Context().UserValue("two_factor").(bool)
+	if user.TotpEnabledAt == nil || !twoFactor {
+		return c.
+			Status(http.StatusForbidden).
+			JSON(ex.NewHttpError(ex.ErrTwoFactorRequired, nil))
+	}
+
 	return c.Next()
 }
 
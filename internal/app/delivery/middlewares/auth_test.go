@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ex "onthemat/internal/app/common"
+	"onthemat/internal/app/mocks"
+	"onthemat/internal/app/utils"
+	"onthemat/pkg/ent"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOnlySuperAdmin(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	m := NewMiddelwWare(nil, nil, nil, mockUserRepo, nil, nil, nil, nil)
+
+	// Auth 미들웨어가 토큰에서 꺼내 담는 값을 그대로 넣는다.
+	newApp := func(userType string, twoFactor bool) *fiber.App {
+		app := fiber.New()
+		app.Get("/", func(c *fiber.Ctx) error {
+			c.Context().SetUserValue("user_type", userType)
+			c.Context().SetUserValue("user_id", 1)
+			c.Context().SetUserValue("two_factor", twoFactor)
+			return c.Next()
+		}, m.OnlySuperAdmin, func(c *fiber.Ctx) error {
+			return c.SendString("ok")
+		})
+		return app
+	}
+	enabledAt := time.Now()
+
+	t.Run("관리자가 아님", func(t *testing.T) {
+		resp, _ := newApp("teacher", true).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("2단계 인증을 켜지 않음", func(t *testing.T) {
+		mockUserRepo.On("Get", mock.Anything, 1).Return(&ent.User{ID: 1}, nil).Once()
+
+		resp, _ := newApp("superAdmin", false).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("비밀번호만으로 로그인한 세션", func(t *testing.T) {
+		mockUserRepo.On("Get", mock.Anything, 1).Return(&ent.User{ID: 1, TotpEnabledAt: &enabledAt}, nil).Once()
+
+		resp, _ := newApp("superAdmin", false).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, ex.ErrTwoFactorRequired, utils.MakeErrorForTests(resp.Body).ErrCode)
+	})
+
+	t.Run("2단계 인증으로 로그인한 세션", func(t *testing.T) {
+		mockUserRepo.On("Get", mock.Anything, 1).Return(&ent.User{ID: 1, TotpEnabledAt: &enabledAt}, nil).Once()
+
+		resp, _ := newApp("superAdmin", true).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
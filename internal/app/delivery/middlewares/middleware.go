@@ -1,9 +1,11 @@
 package middlewares
 
 import (
+	"onthemat/internal/app/config"
 	"onthemat/internal/app/repository"
 	"onthemat/internal/app/service"
 	"onthemat/internal/app/service/token"
+	"onthemat/pkg/auth/store"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -12,26 +14,41 @@ type MiddleWare interface {
 	Auth(c *fiber.Ctx) error
 	OnlyAcademy(c *fiber.Ctx) error
 	OnlyTeacher(c *fiber.Ctx) error
+	// superAdmin 이면서 2단계 인증을 켠 유저만 통과시킨다.
 	OnlySuperAdmin(c *fiber.Ctx) error
+	// 그룹(RateLimitDefault 등)의 설정으로 요청 횟수를 제한한다.
+	RateLimit(group string) fiber.Handler
 }
 
 type middleWare struct {
 	authSvc     service.AuthService
 	tokensvc    token.TokenService
+	sessions    token.SessionChecker
+	userRepo    repository.UserRepository
 	teacherRepo repository.TeacherRepository
 	academyRepo repository.AcademyRepository
+	store       store.Store
+	config      *config.Config
 }
 
 func NewMiddelwWare(
 	authSvc service.AuthService,
 	tokensvc token.TokenService,
+	sessions token.SessionChecker,
+	userRepo repository.UserRepository,
 	teacherRepo repository.TeacherRepository,
 	academyRepo repository.AcademyRepository,
+	store store.Store,
+	config *config.Config,
 ) MiddleWare {
 	return &middleWare{
 		authSvc:     authSvc,
 		tokensvc:    tokensvc,
+		sessions:    sessions,
+		userRepo:    userRepo,
 		teacherRepo: teacherRepo,
 		academyRepo: academyRepo,
+		store:       store,
+		config:      config,
 	}
 }
@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	ex "onthemat/internal/app/common"
+	"onthemat/pkg/auth/store"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 요청 횟수 제한 그룹. 횟수와 기간은 설정(config.RateLimit)에서 그룹별로 정한다.
+const (
+	// /api/v1 전체
+	RateLimitDefault = "default"
+	RateLimitLogin   = "login"
+	// 메일을 보내는 요청 (회원가입, 비밀번호 재설정)
+	RateLimitEmail = "email"
+	// 가입 여부를 알 수 있는 요청 (이메일 중복체크, 계정 복구)
+	RateLimitLookup = "lookup"
+	// 문자를 보내는 요청 (휴대폰 번호 인증)
+	RateLimitSms = "sms"
+)
+
+type RateLimitConfig struct {
+	// store key 에 붙여 그룹끼리 횟수를 따로 센다.
+	Name   string
+	Limit  int
+	Window time.Duration
+	// 요청을 구분하는 값. 기본은 클라이언트 IP
+	Key func(c *fiber.Ctx) string
+}
+
+func (m *middleWare) RateLimit(group string) fiber.Handler {
+	rl := m.config.RateLimit
+
+	var limit, window int
+	switch group {
+	case RateLimitDefault:
+		limit, window = rl.DefaultLimit, rl.DefaultWindow
+	case RateLimitLogin:
+		limit, window = rl.LoginLimit, rl.LoginWindow
+	case RateLimitEmail:
+		limit, window = rl.EmailLimit, rl.EmailWindow
+	case RateLimitLookup:
+		limit, window = rl.LookupLimit, rl.LookupWindow
+	case RateLimitSms:
+		limit, window = rl.SmsLimit, rl.SmsWindow
+	default:
+		panic(fmt.Sprintf("middlewares: 알 수 없는 요청 제한 그룹입니다 (%s)", group))
+	}
+
+	if !rl.Enabled || limit <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return NewRateLimiter(m.store, RateLimitConfig{
+		Name:   group,
+		Limit:  limit,
+		Window: time.Duration(window) * time.Second,
+	})
+}
+
+// Redis sliding window 로 Window 동안 Key 별로 Limit 번까지 허용한다.
+// 넘으면 Retry-After 헤더와 함께 429 를 반환한다.
+func NewRateLimiter(store store.Store, config RateLimitConfig) fiber.Handler {
+	if config.Key == nil {
+		config.Key = func(c *fiber.Ctx) string {
+			return c.IP()
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := "rate-limit:" + config.Name + ":" + config.Key(c)
+		allowed, retryAfter, err := store.SlidingWindow(c.Context(), key, config.Limit, config.Window)
+		if err != nil {
+			// Redis 장애로 서비스 전체가 멈추지 않도록 제한 없이 통과시킨다.
+			log.Printf("[rate-limit] %s: %v", key, err)
+			return c.Next()
+		}
+
+		if !allowed {
+			seconds := int(math.Max(1, math.Ceil(retryAfter.Seconds())))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+			return c.Status(http.StatusTooManyRequests).
+				JSON(ex.NewHttpError(ex.ErrTooManyRequests, map[string]int{"retryAfter": seconds}))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,99 @@
+package middlewares
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/config"
+	pkgMock "onthemat/pkg/mocks"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRateLimitApp(handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/", handler, func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app
+}
+
+func TestRateLimiter(t *testing.T) {
+	mockStore := new(pkgMock.Store)
+	app := newRateLimitApp(NewRateLimiter(mockStore, RateLimitConfig{
+		Name:   "login",
+		Limit:  10,
+		Window: time.Minute,
+		Key: func(c *fiber.Ctx) string {
+			return "1.2.3.4"
+		},
+	}))
+
+	t.Run("허용", func(t *testing.T) {
+		mockStore.On("SlidingWindow", mock.Anything, "rate-limit:login:1.2.3.4", 10, time.Minute).Return(true, time.Duration(0), nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+	})
+
+	t.Run("초과", func(t *testing.T) {
+		mockStore.On("SlidingWindow", mock.Anything, "rate-limit:login:1.2.3.4", 10, time.Minute).Return(false, 1500*time.Millisecond, nil).Once()
+
+		resp, _ := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), `"code":8000`)
+	})
+
+	t.Run("store 에러면 통과", func(t *testing.T) {
+		mockStore.On("SlidingWindow", mock.Anything, "rate-limit:login:1.2.3.4", 10, time.Minute).Return(false, time.Duration(0), errors.New("redis down")).Once()
+
+		resp, _ := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestRateLimitGroup(t *testing.T) {
+	c := config.NewConfig()
+	c.RateLimit.Enabled = true
+	c.RateLimit.LookupLimit = 20
+	c.RateLimit.LookupWindow = 60
+	mockStore := new(pkgMock.Store)
+	m := NewMiddelwWare(nil, nil, nil, nil, nil, nil, mockStore, c)
+
+	t.Run("그룹 설정으로 제한", func(t *testing.T) {
+		mockStore.On("SlidingWindow", mock.Anything, "rate-limit:lookup:0.0.0.0", 20, time.Minute).Return(true, time.Duration(0), nil).Once()
+
+		resp, _ := newRateLimitApp(m.RateLimit(RateLimitLookup)).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("문자 발송 그룹", func(t *testing.T) {
+		c.RateLimit.SmsLimit = 5
+		c.RateLimit.SmsWindow = 600
+		mockStore.On("SlidingWindow", mock.Anything, "rate-limit:sms:0.0.0.0", 5, 10*time.Minute).Return(false, 30*time.Second, nil).Once()
+
+		resp, _ := newRateLimitApp(m.RateLimit(RateLimitSms)).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("횟수가 0 이면 제한하지 않는다", func(t *testing.T) {
+		resp, _ := newRateLimitApp(m.RateLimit(RateLimitLogin)).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockStore.AssertNotCalled(t, "SlidingWindow", mock.Anything, "rate-limit:login:0.0.0.0", mock.Anything, mock.Anything)
+	})
+
+	t.Run("없는 그룹", func(t *testing.T) {
+		assert.Panics(t, func() { m.RateLimit("unknown") })
+	})
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"time"
+
+	"onthemat/internal/app/usecase"
+)
+
+// 모든 일정이 지난 공고를 마감한다.
+func RecruitmentExpireJob(recruitmentUsecase usecase.RecruitmentUsecase, interval time.Duration) Job {
+	return Job{
+		Name:     "recruitment expire",
+		LockKey:  "lock:scheduler:recruitment-expire",
+		Interval: interval,
+		Run:      recruitmentUsecase.FinishExpired,
+	}
+}
+
+// 탈퇴 유예 기간이 지난 유저의 개인정보를 익명화한다.
+func UserAnonymizeJob(authUsecase usecase.AuthUseCase, interval time.Duration) Job {
+	return Job{
+		Name:     "user anonymize",
+		LockKey:  "lock:scheduler:user-anonymize",
+		Interval: interval,
+		Run:      authUsecase.AnonymizeWithdrawn,
+	}
+}
+
+// outbox 에 쌓인 메일을 보낸다. 실패한 메일은 시도 횟수에 따라 간격을 늘려 다시 보낸다.
+func EmailOutboxJob(emailOutboxUsecase usecase.EmailOutboxUsecase, interval time.Duration) Job {
+	return Job{
+		Name:     "email outbox",
+		LockKey:  "lock:scheduler:email-outbox",
+		Interval: interval,
+		Run:      emailOutboxUsecase.Dispatch,
+	}
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"onthemat/pkg/auth/store"
+)
+
+// 주기적으로 실행할 작업. Run 은 처리한 건수를 반환한다.
+type Job struct {
+	Name     string
+	LockKey  string
+	Interval time.Duration
+	Run      func(ctx context.Context, now time.Time) (int, error)
+}
+
+// 등록된 작업을 주기적으로 실행한다.
+// 여러 인스턴스에서 실행되더라도 작업마다 Redis 락을 잡은 인스턴스 하나만 작업한다.
+type Scheduler struct {
+	store      store.Store
+	instanceId string
+	now        func() time.Time
+}
+
+func NewScheduler(store store.Store) *Scheduler {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return &Scheduler{
+		store:      store,
+		instanceId: hex.EncodeToString(b),
+		now:        time.Now,
+	}
+}
+
+// ctx 가 취소될 때까지 job.Interval 마다 실행한다. 시작 시 한 번 바로 실행한다.
+func (s *Scheduler) Start(ctx context.Context, job Job) {
+	go func() {
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.RunOnce(ctx, job); err != nil {
+				log.Printf("[scheduler] %s: %v", job.Name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// 락을 잡지 못하면 다른 인스턴스가 실행 중이므로 아무것도 하지 않는다.
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) (count int, err error) {
+	// 작업이 interval 보다 오래 걸려도 다음 주기에 다시 잡을 수 있도록 락은 interval 만큼만 유지한다.
+	locked, err := s.store.SetNX(ctx, job.LockKey, s.instanceId, job.Interval)
+	if err != nil || !locked {
+		return
+	}
+	defer s.store.DelIfEqual(context.Background(), job.LockKey, s.instanceId)
+
+	count, err = job.Run(ctx, s.now())
+	if err != nil {
+		return
+	}
+
+	if count > 0 {
+		log.Printf("[scheduler] %s: %d processed", job.Name, count)
+	}
+	return
+}
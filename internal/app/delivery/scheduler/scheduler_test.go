@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgMock "onthemat/pkg/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	scheduler *Scheduler
+	mockStore *pkgMock.Store
+	now       time.Time
+	calls     int
+	job       Job
+}
+
+// 각 테스트 시작 전 N회
+func (ts *SchedulerTestSuite) SetupTest() {
+	ts.mockStore = new(pkgMock.Store)
+	ts.scheduler = NewScheduler(ts.mockStore)
+	ts.now = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	ts.scheduler.now = func() time.Time { return ts.now }
+	ts.calls = 0
+	ts.job = Job{
+		Name:     "test",
+		LockKey:  "lock:scheduler:test",
+		Interval: time.Minute,
+		Run: func(ctx context.Context, now time.Time) (int, error) {
+			ts.calls++
+			ts.Equal(ts.now, now)
+			return 3, nil
+		},
+	}
+}
+
+// ------------------- Test Case -------------------
+
+func (ts *SchedulerTestSuite) TestRunOnce() {
+	ts.Run("락 획득 후 실행, 락 해제", func() {
+		ts.mockStore.On("SetNX", mock.Anything, "lock:scheduler:test", ts.scheduler.instanceId, time.Minute).
+			Return(true, nil).Once()
+		ts.mockStore.On("DelIfEqual", mock.Anything, "lock:scheduler:test", ts.scheduler.instanceId).
+			Return(true, nil).Once()
+
+		count, err := ts.scheduler.RunOnce(context.Background(), ts.job)
+		ts.NoError(err)
+		ts.Equal(3, count)
+		ts.Equal(1, ts.calls)
+		ts.mockStore.AssertExpectations(ts.T())
+	})
+
+	ts.Run("다른 인스턴스가 실행 중이면 건너뜀", func() {
+		ts.mockStore.On("SetNX", mock.Anything, "lock:scheduler:test", ts.scheduler.instanceId, time.Minute).
+			Return(false, nil).Once()
+
+		count, err := ts.scheduler.RunOnce(context.Background(), ts.job)
+		ts.NoError(err)
+		ts.Equal(0, count)
+		ts.Equal(1, ts.calls)
+	})
+
+	ts.Run("락 획득 실패", func() {
+		ts.mockStore.On("SetNX", mock.Anything, "lock:scheduler:test", ts.scheduler.instanceId, time.Minute).
+			Return(false, errors.New("redis down")).Once()
+
+		_, err := ts.scheduler.RunOnce(context.Background(), ts.job)
+		ts.Error(err)
+		ts.Equal(1, ts.calls)
+	})
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
@@ -0,0 +1,8 @@
+-- modify "rinstead_teacher" table
+ALTER TABLE "rinstead_teacher" ADD COLUMN "status" smallint NOT NULL DEFAULT 1, ADD COLUMN "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, ADD COLUMN "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP;
+-- backfill "rinstead_teacher"."status": 합격자(recruitment_instead.teacher_id)는 accepted(3), 나머지는 applied(1)
+UPDATE "rinstead_teacher" AS "rt" SET "status" = 3
+FROM "recruitment_instead" AS "ri"
+WHERE "ri"."id" = "rt"."r_instead_id" AND "ri"."teacher_id" = "rt"."teacher_id";
+-- modify "rinstead_teacher" table (M2M 조인 테이블 외래키 -> 지원서 엣지 외래키)
+ALTER TABLE "rinstead_teacher" DROP CONSTRAINT "rinstead_teacher_r_instead_id", DROP CONSTRAINT "rinstead_teacher_teacher_id", ADD CONSTRAINT "rinstead_teacher_recruitment_instead_instead" FOREIGN KEY ("r_instead_id") REFERENCES "recruitment_instead" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION, ADD CONSTRAINT "rinstead_teacher_teachers_teacher" FOREIGN KEY ("teacher_id") REFERENCES "teachers" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION;
@@ -0,0 +1,16 @@
+-- create "recruitment_instead_schedule" table
+CREATE TABLE "recruitment_instead_schedule" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "start_date_time" timestamp NOT NULL, "end_date_time" timestamp NOT NULL, "r_instead_id" bigint NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "recruitment_instead_schedule_recruitment_instead_schedules" FOREIGN KEY ("r_instead_id") REFERENCES "recruitment_instead" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "recruitmentinsteadschedule_r_instead_id" to table: "recruitment_instead_schedule"
+CREATE INDEX "recruitmentinsteadschedule_r_instead_id" ON "recruitment_instead_schedule" ("r_instead_id");
+-- create index "recruitmentinsteadschedule_start_date_time_end_date_time" to table: "recruitment_instead_schedule"
+CREATE INDEX "recruitmentinsteadschedule_start_date_time_end_date_time" ON "recruitment_instead_schedule" ("start_date_time", "end_date_time");
+-- create index "recruitmentinsteadschedule_period" to table: "recruitment_instead_schedule" (겹침(&&) 조회용)
+CREATE INDEX "recruitmentinsteadschedule_period" ON "recruitment_instead_schedule" USING GIST (tsrange("start_date_time", "end_date_time"));
+-- backfill "recruitment_instead_schedule" from "recruitment_instead"."schedule" (jsonb)
+INSERT INTO "recruitment_instead_schedule" ("r_instead_id", "start_date_time", "end_date_time")
+SELECT "ri"."id", ("c" ->> 'startDateTime')::timestamp, ("c" ->> 'endDateTime')::timestamp
+FROM "recruitment_instead" AS "ri", jsonb_array_elements("ri"."schedule") AS "c"
+WHERE "ri"."schedule" IS NOT NULL AND jsonb_typeof("ri"."schedule") = 'array'
+  AND ("c" ->> 'startDateTime') IS NOT NULL AND ("c" ->> 'endDateTime') IS NOT NULL;
+-- modify "recruitment_instead" table
+ALTER TABLE "recruitment_instead" DROP COLUMN "schedule";
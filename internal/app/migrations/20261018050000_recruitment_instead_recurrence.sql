@@ -0,0 +1,4 @@
+-- modify "recruitment_instead" table
+ALTER TABLE "recruitment_instead" ADD COLUMN "recurrence" jsonb NULL;
+-- modify "recruitment_instead_schedule" table
+ALTER TABLE "recruitment_instead_schedule" ADD COLUMN "original_start_date_time" timestamp NULL;
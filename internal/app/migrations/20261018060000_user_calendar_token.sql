@@ -0,0 +1,4 @@
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "calendar_token" character varying NULL;
+-- create index "users_calendar_token_key" to table: "users"
+CREATE UNIQUE INDEX "users_calendar_token_key" ON "users" ("calendar_token");
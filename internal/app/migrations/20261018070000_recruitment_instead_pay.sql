@@ -0,0 +1,28 @@
+-- modify "recruitment_instead" table
+ALTER TABLE "recruitment_instead" ADD COLUMN "min_career_month" bigint NOT NULL DEFAULT 0, ADD COLUMN "pay_amount" bigint NOT NULL DEFAULT 0, ADD COLUMN "pay_currency" character varying(3) NOT NULL DEFAULT 'KRW', ADD COLUMN "pay_unit" smallint NOT NULL DEFAULT 1;
+-- backfill structured pay / career from free-text columns
+-- 경력: "6개월" -> 6, "2년" / "2년 6개월" -> 30, 숫자만 있으면 년 단위로 본다. 해석할 수 없으면 0
+UPDATE "recruitment_instead" SET "min_career_month" = LEAST(600,
+  COALESCE((substring("min_career" from '(\d{1,3})\s*년'))::int * 12, 0)
+  + COALESCE((substring("min_career" from '(\d{1,4})\s*개월'))::int, 0)
+  + CASE WHEN "min_career" ~ '^\s*\d{1,3}\s*$' THEN (trim("min_career"))::int * 12 ELSE 0 END
+);
+-- 급여: 숫자만 추출 ("3만", "3만원" 은 만 단위), 단위는 시급/일당 표현으로 추정한다. 해석할 수 없으면 0
+UPDATE "recruitment_instead" SET
+  "pay_amount" = CASE
+    WHEN "pay" ~ '\d{1,6}\s*만' THEN (substring("pay" from '(\d{1,6})\s*만'))::bigint * 10000
+    WHEN regexp_replace("pay", '[^0-9]', '', 'g') ~ '^\d{1,12}$' THEN (regexp_replace("pay", '[^0-9]', '', 'g'))::bigint
+    ELSE 0
+  END,
+  "pay_currency" = CASE WHEN "pay" ~* '(\$|usd|달러)' THEN 'USD' ELSE 'KRW' END,
+  "pay_unit" = CASE
+    WHEN "pay" ~* '(시간|시급|hour)' THEN 2
+    WHEN "pay" ~* '(일당|하루|day)' THEN 3
+    ELSE 1
+  END;
+-- drop free-text columns
+ALTER TABLE "recruitment_instead" DROP COLUMN "min_career", DROP COLUMN "pay", ALTER COLUMN "pay_amount" DROP DEFAULT;
+-- create index "recruitmentinstead_pay_amount" to table: "recruitment_instead"
+CREATE INDEX "recruitmentinstead_pay_amount" ON "recruitment_instead" ("pay_amount");
+-- create index "recruitmentinstead_min_career_month" to table: "recruitment_instead"
+CREATE INDEX "recruitmentinstead_min_career_month" ON "recruitment_instead" ("min_career_month");
@@ -0,0 +1,2 @@
+-- modify "users" table
+ALTER TABLE "users" DROP COLUMN "temp_password";
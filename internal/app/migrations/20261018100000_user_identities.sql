@@ -0,0 +1,13 @@
+-- create "user_identities" table
+CREATE TABLE "user_identities" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "created_at" timestamp NOT NULL, "updated_at" timestamp NOT NULL, "provider" smallint NOT NULL, "provider_key" character varying NOT NULL, "email" varchar(100) NULL, "user_id" bigint NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "user_identities_users_Identities" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "useridentity_provider_provider_key" to table: "user_identities"
+CREATE UNIQUE INDEX "useridentity_provider_provider_key" ON "user_identities" ("provider", "provider_key");
+-- create index "useridentity_user_id_provider" to table: "user_identities"
+CREATE UNIQUE INDEX "useridentity_user_id_provider" ON "user_identities" ("user_id", "provider");
+-- backfill "user_identities" from "users"."social_name", "users"."social_key"
+INSERT INTO "user_identities" ("created_at", "updated_at", "provider", "provider_key", "email", "user_id")
+SELECT "u"."created_at", "u"."updated_at", "u"."social_name", "u"."social_key", "u"."email", "u"."id"
+FROM "users" AS "u"
+WHERE "u"."social_name" IS NOT NULL AND "u"."social_key" IS NOT NULL;
+-- modify "users" table
+ALTER TABLE "users" DROP COLUMN "social_name", DROP COLUMN "social_key";
@@ -0,0 +1,4 @@
+-- create "email_outbox" table
+CREATE TABLE "email_outbox" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "created_at" timestamp NOT NULL, "updated_at" timestamp NOT NULL, "recipient" varchar(100) NOT NULL, "template" character varying NOT NULL, "data" jsonb NULL, "status" smallint NOT NULL DEFAULT 1, "attempts" bigint NOT NULL DEFAULT 0, "next_attempt_at" timestamp NOT NULL, "last_error" character varying NULL, "sent_at" timestamp NULL, PRIMARY KEY ("id"));
+-- create index "emailoutbox_status_next_attempt_at" to table: "email_outbox"
+CREATE INDEX "emailoutbox_status_next_attempt_at" ON "email_outbox" ("status", "next_attempt_at");
@@ -0,0 +1,2 @@
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "is_phone_verified" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "totp_secret" character varying NULL, ADD COLUMN "totp_recovery_codes" jsonb NULL, ADD COLUMN "totp_enabled_at" timestamp NULL;
@@ -0,0 +1,2 @@
+-- backfill "users"."calendar_token": 발급된 토큰 원문을 sha256 해시로 바꾼다. (기존 구독 URL 은 그대로 동작)
+UPDATE "users" SET "calendar_token" = encode(sha256(convert_to("calendar_token", 'UTF8')), 'hex') WHERE "calendar_token" IS NOT NULL;
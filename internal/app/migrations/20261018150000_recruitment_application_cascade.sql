@@ -0,0 +1,2 @@
+-- modify "rinstead_teacher" table (공고 자리, 강사가 삭제되면 지원서도 함께 삭제)
+ALTER TABLE "rinstead_teacher" DROP CONSTRAINT "rinstead_teacher_recruitment_instead_instead", DROP CONSTRAINT "rinstead_teacher_teachers_teacher", ADD CONSTRAINT "rinstead_teacher_recruitment_instead_instead" FOREIGN KEY ("r_instead_id") REFERENCES "recruitment_instead" ("id") ON UPDATE NO ACTION ON DELETE CASCADE, ADD CONSTRAINT "rinstead_teacher_teachers_teacher" FOREIGN KEY ("teacher_id") REFERENCES "teachers" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+-- drop index "recruitmentinsteadschedule_period" from table: "recruitment_instead_schedule" (겹침 조회는 (start_date_time, end_date_time) 인덱스를 사용한다)
+DROP INDEX "recruitmentinsteadschedule_period";
@@ -92,29 +92,30 @@ var (
 	KakaoString      string     = "kakao"
 	GoogleString     string     = "google"
 	NaverString      string     = "naver"
+	AppleString      string     = "apple"
 	TeacherType      UserType   = 1
 	AcademyType      UserType   = 2
 	SuperAdminType   UserType   = 11
 	KakaoSocialType  SocialType = 1
 	GoogleSocialType SocialType = 2
 	NaverSocialType  SocialType = 3
+	AppleSocialType  SocialType = 4
 )
 
+// 소셜 로그인 업체 이름. 업체를 추가할 때는 여기에만 등록한다. (DB 에는 SocialType 값이 저장된다)
+var socialTypeNames = map[SocialType]*string{
+	KakaoSocialType:  &KakaoString,
+	GoogleSocialType: &GoogleString,
+	NaverSocialType:  &NaverString,
+	AppleSocialType:  &AppleString,
+}
+
 func (t *SocialType) ToString() *string {
 	if t == nil {
 		return nil
 	}
 
-	var result *string
-	if *t == KakaoSocialType {
-		result = &KakaoString
-	} else if *t == GoogleSocialType {
-		result = &GoogleString
-	} else if *t == NaverSocialType {
-		result = &NaverString
-	}
-
-	return result
+	return socialTypeNames[*t]
 }
 
 func (t *UserType) ToString() *string {
@@ -158,25 +159,26 @@ func (t *UserType) ToUserType(v *string) *UserType {
 	return result
 }
 
+// 업체 이름(kakao, google, naver, apple)으로 SocialType 을 찾는다.
+func SocialTypeFromString(name string) (SocialType, bool) {
+	for socialType, socialName := range socialTypeNames {
+		if *socialName == name {
+			return socialType, true
+		}
+	}
+	return 0, false
+}
+
 func (t *SocialType) ToSocialType(v *string) *SocialType {
 	if v == nil {
 		return nil
 	}
 
-	var result *SocialType
-
-	if v == &GoogleString {
-		google := GoogleSocialType
-		result = &google
-
-	} else if v == &KakaoString {
-		kakao := KakaoSocialType
-		result = &kakao
-
-	} else if v == &NaverString {
-		naver := NaverSocialType
-		result = &naver
+	for socialType, socialName := range socialTypeNames {
+		if v == socialName {
+			result := socialType
+			return &result
+		}
 	}
-
-	return result
+	return nil
 }
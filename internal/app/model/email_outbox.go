@@ -0,0 +1,112 @@
+package model
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// 보낼 메일. 유저 변경과 같은 트랜잭션에서 쌓고, 스케줄러가 꺼내서 보낸다. (실패하면 재시도)
+type EmailOutbox struct {
+	ent.Schema
+}
+
+func (EmailOutbox) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "email_outbox"},
+	}
+}
+
+type EmailStatus int8
+
+var (
+	EmailPendingString string = "pending"
+	EmailSentString    string = "sent"
+	EmailDeadString    string = "dead"
+
+	EmailPending EmailStatus = 1
+	EmailSent    EmailStatus = 2
+	// 재시도 횟수를 넘겨 더 이상 보내지 않는다. 원인을 확인한 뒤 pending 으로 되돌리면 다시 보낸다.
+	EmailDead EmailStatus = 3
+)
+
+func (s *EmailStatus) ToString() *string {
+	if s == nil {
+		return nil
+	}
+
+	var result *string
+
+	switch *s {
+	case EmailPending:
+		result = &EmailPendingString
+	case EmailSent:
+		result = &EmailSentString
+	case EmailDead:
+		result = &EmailDeadString
+	}
+
+	return result
+}
+
+func (EmailOutbox) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("recipient").
+			SchemaType(map[string]string{
+				dialect.Postgres: "varchar(100)",
+			}).
+			MaxLen(100).
+			Comment("받는 사람 이메일"),
+
+		field.String("template").
+			Comment("메일 템플릿 이름"),
+
+		field.JSON("data", map[string]string{}).
+			Optional().
+			Sensitive().
+			Comment("템플릿 데이터. 토큰이 들어있으므로 보낸 뒤에는 지운다."),
+
+		field.Int8("status").
+			GoType(EmailStatus(0)).
+			Default(int8(EmailPending)).
+			Comment("1:pending 2:sent 3:dead"),
+
+		field.Int("attempts").
+			Default(0).
+			Comment("보내기 시도 횟수"),
+
+		field.Time("nextAttemptAt").
+			SchemaType(map[string]string{
+				dialect.Postgres: "timestamp",
+			}).
+			Comment("다음 시도 일시"),
+
+		field.String("lastError").
+			Optional().
+			Nillable().
+			Comment("마지막 실패 사유"),
+
+		field.Time("sentAt").
+			SchemaType(map[string]string{
+				dialect.Postgres: "timestamp",
+			}).
+			Optional().
+			Nillable().
+			Comment("보낸 일시"),
+	}
+}
+
+func (EmailOutbox) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		DefaultTimeMixin{},
+	}
+}
+
+func (EmailOutbox) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "nextAttemptAt"),
+	}
+}
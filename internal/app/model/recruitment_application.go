@@ -0,0 +1,148 @@
+package model
+
+import (
+	"onthemat/internal/app/transport"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// 대강 지원서 (RecruitmentInstead <-> Teacher)
+type RecruitmentApplication struct {
+	ent.Schema
+}
+
+func (RecruitmentApplication) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		field.ID("r_instead_id", "teacher_id"),
+		entsql.Annotation{Table: "rinstead_teacher"},
+	}
+}
+
+type ApplicationStatus int8
+
+var (
+	ApplicationAppliedString     string = "applied"
+	ApplicationShortlistedString string = "shortlisted"
+	ApplicationAcceptedString    string = "accepted"
+	ApplicationRejectedString    string = "rejected"
+	ApplicationWithdrawnString   string = "withdrawn"
+	ApplicationCancelledString   string = "cancelled"
+
+	ApplicationApplied     ApplicationStatus = 1
+	ApplicationShortlisted ApplicationStatus = 2
+	ApplicationAccepted    ApplicationStatus = 3
+	ApplicationRejected    ApplicationStatus = 4
+	ApplicationWithdrawn   ApplicationStatus = 5
+	ApplicationCancelled   ApplicationStatus = 6
+)
+
+// 현재 상태에서 변경 가능한 상태들
+var applicationTransitions = map[ApplicationStatus][]ApplicationStatus{
+	ApplicationApplied:     {ApplicationShortlisted, ApplicationAccepted, ApplicationRejected, ApplicationWithdrawn},
+	ApplicationShortlisted: {ApplicationAccepted, ApplicationRejected, ApplicationWithdrawn},
+	ApplicationAccepted:    {ApplicationCancelled},
+	ApplicationRejected:    {},
+	ApplicationWithdrawn:   {ApplicationApplied},
+	ApplicationCancelled:   {},
+}
+
+func (s ApplicationStatus) CanTransitionTo(next ApplicationStatus) bool {
+	for _, v := range applicationTransitions[s] {
+		if v == next {
+			return true
+		}
+	}
+	return false
+}
+
+// 아직 학원의 결정을 기다리는 상태인지
+func (s ApplicationStatus) IsPending() bool {
+	return s == ApplicationApplied || s == ApplicationShortlisted
+}
+
+func (s *ApplicationStatus) ToString() *string {
+	if s == nil {
+		return nil
+	}
+
+	var result *string
+
+	switch *s {
+	case ApplicationApplied:
+		result = &ApplicationAppliedString
+	case ApplicationShortlisted:
+		result = &ApplicationShortlistedString
+	case ApplicationAccepted:
+		result = &ApplicationAcceptedString
+	case ApplicationRejected:
+		result = &ApplicationRejectedString
+	case ApplicationWithdrawn:
+		result = &ApplicationWithdrawnString
+	case ApplicationCancelled:
+		result = &ApplicationCancelledString
+	}
+
+	return result
+}
+
+func (RecruitmentApplication) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("r_instead_id"),
+
+		field.Int("teacher_id"),
+
+		field.Int8("status").
+			GoType(ApplicationStatus(0)).
+			Default(int8(ApplicationApplied)).
+			Comment("지원 상태 1:applied 2:shortlisted 3:accepted 4:rejected 5:withdrawn 6:cancelled"),
+
+		// 기존 지원 내역이 있는 테이블에 컬럼이 추가되므로 DB 기본값을 둔다.
+		field.Time("createdAt").
+			Immutable().
+			SchemaType(
+				map[string]string{
+					dialect.Postgres: "timestamp",
+				},
+			).
+			GoType(transport.TimeString{}).
+			Default(transport.TimeString{}.Now).
+			Annotations(entsql.Annotation{Default: "CURRENT_TIMESTAMP"}),
+
+		field.Time("updatedAt").
+			GoType(transport.TimeString{}).
+			SchemaType(
+				map[string]string{
+					dialect.Postgres: "timestamp",
+				},
+			).
+			Default(transport.TimeString{}.Now).
+			UpdateDefault(transport.TimeString{}.Now).
+			Annotations(entsql.Annotation{Default: "CURRENT_TIMESTAMP"}),
+	}
+}
+
+func (RecruitmentApplication) Edges() []ent.Edge {
+	return []ent.Edge{
+		// 공고를 수정하며 삭제된 자리의 지원서는 함께 삭제한다.
+		edge.To("instead", RecruitmentInstead.Type).
+			Unique().
+			Required().
+			Field("r_instead_id").
+			Annotations(entsql.Annotation{
+				OnDelete: entsql.Cascade,
+			}),
+
+		edge.To("teacher", Teacher.Type).
+			Unique().
+			Required().
+			Field("teacher_id").
+			Annotations(entsql.Annotation{
+				OnDelete: entsql.Cascade,
+			}),
+	}
+}
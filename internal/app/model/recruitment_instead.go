@@ -1,31 +1,78 @@
 package model
 
 import (
-	"onthemat/internal/app/transport"
-
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 )
 
 type RecruitmentInstead struct {
 	ent.Schema
 }
 
-func (RecruitmentInstead) Annotations() []schema.Annotation {
-	return []schema.Annotation{
-		entsql.Annotation{Table: "recruitment_instead"},
+type PayUnit int8
+
+var (
+	PayPerClassString string = "class"
+	PayPerHourString  string = "hour"
+	PayPerDayString   string = "day"
+
+	PayPerClass PayUnit = 1
+	PayPerHour  PayUnit = 2
+	PayPerDay   PayUnit = 3
+)
+
+const DefaultPayCurrency = "KRW"
+
+func (u *PayUnit) ToString() *string {
+	if u == nil {
+		return nil
+	}
+
+	var result *string
+
+	switch *u {
+	case PayPerClass:
+		result = &PayPerClassString
+	case PayPerHour:
+		result = &PayPerHourString
+	case PayPerDay:
+		result = &PayPerDayString
 	}
+
+	return result
 }
 
-type Schedule struct {
-	StartDateTime transport.TimeString `json:"startDateTime"`
-	EndDateTime   transport.TimeString `json:"endDateTime"`
+func (u *PayUnit) ToPayUnit(v *string) *PayUnit {
+	if v == nil {
+		return nil
+	}
+
+	var result *PayUnit
+
+	switch *v {
+	case PayPerClassString:
+		class := PayPerClass
+		result = &class
+	case PayPerHourString:
+		hour := PayPerHour
+		result = &hour
+	case PayPerDayString:
+		day := PayPerDay
+		result = &day
+	}
+
+	return result
 }
 
-var FieldStartDateTime = "startDateTime"
+func (RecruitmentInstead) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "recruitment_instead"},
+	}
+}
 
 func (RecruitmentInstead) Fields() []ent.Field {
 	return []ent.Field{
@@ -37,13 +84,35 @@ func (RecruitmentInstead) Fields() []ent.Field {
 			Optional().
 			Nillable(),
 
-		field.String("minCareer").
-			Comment("최소 경력"),
+		field.Int("minCareerMonth").
+			NonNegative().
+			Default(0).
+			Comment("최소 경력 (개월)"),
 
-		field.String("pay").
-			Comment("급여"),
+		field.Int("payAmount").
+			NonNegative().
+			Comment("급여 금액"),
 
-		field.JSON("schedule", []*Schedule{}).Optional(),
+		field.String("payCurrency").
+			MaxLen(3).
+			Default(DefaultPayCurrency).
+			Comment("급여 통화 (ISO 4217)"),
+
+		field.Int8("payUnit").
+			GoType(PayUnit(0)).
+			Default(int8(PayPerClass)).
+			Comment("급여 단위 1:class 2:hour 3:day"),
+
+		field.JSON("recurrence", &Recurrence{}).
+			Optional().
+			Comment("반복 규칙 (있으면 schedules 는 규칙으로 생성된 일정들)"),
+	}
+}
+
+func (RecruitmentInstead) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("payAmount"),
+		index.Fields("minCareerMonth"),
 	}
 }
 
@@ -67,7 +136,13 @@ func (RecruitmentInstead) Edges() []ent.Edge {
 			Field("teacher_id"),
 
 		edge.To("applicant", Teacher.Type).
-			StorageKey(edge.Table("rinstead_teacher"), edge.Columns("r_instead_id", "teacher_id")),
+			StorageKey(edge.Columns("r_instead_id", "teacher_id")).
+			Through("applications", RecruitmentApplication.Type),
+
+		edge.To("schedules", RecruitmentInsteadSchedule.Type).
+			Annotations(entsql.Annotation{
+				OnDelete: entsql.Cascade,
+			}),
 
 		edge.To("yoga", Yoga.Type).
 			StorageKey(edge.Table("rinstead_yoga"), edge.Columns("r_instead_id", "yoga_id")),
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"onthemat/internal/app/transport"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// 대강 수업 일정
+type RecruitmentInsteadSchedule struct {
+	ent.Schema
+}
+
+func (RecruitmentInsteadSchedule) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "recruitment_instead_schedule"},
+	}
+}
+
+type Schedule struct {
+	StartDateTime transport.TimeString `json:"startDateTime"`
+	EndDateTime   transport.TimeString `json:"endDateTime"`
+}
+
+func (RecruitmentInsteadSchedule) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("id"),
+
+		field.Int("r_instead_id"),
+
+		field.Time("startDateTime").
+			SchemaType(
+				map[string]string{
+					dialect.Postgres: "timestamp",
+				},
+			).
+			GoType(transport.TimeString{}).
+			Comment("수업 시작 일시"),
+
+		field.Time("endDateTime").
+			SchemaType(
+				map[string]string{
+					dialect.Postgres: "timestamp",
+				},
+			).
+			GoType(transport.TimeString{}).
+			Comment("수업 종료 일시"),
+
+		field.Time("originalStartDateTime").
+			SchemaType(
+				map[string]string{
+					dialect.Postgres: "timestamp",
+				},
+			).
+			GoType(transport.TimeString{}).
+			Optional().
+			Nillable().
+			Comment("반복 규칙으로 생성된 일정의 원래 시작 일시 (개별 수정해도 유지된다)"),
+	}
+}
+
+func (RecruitmentInsteadSchedule) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("r_instead_id"),
+
+		// 겹침 조회 (startDateTime < :end AND endDateTime > :start)
+		index.Fields("startDateTime", "endDateTime"),
+	}
+}
+
+func (RecruitmentInsteadSchedule) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("instead", RecruitmentInstead.Type).
+			Ref("schedules").
+			Unique().
+			Required().
+			Field("r_instead_id"),
+	}
+}
@@ -0,0 +1,204 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"onthemat/internal/app/transport"
+)
+
+// 대강 일정 반복 규칙 (RFC 5545 RRULE 의 일부)
+// 일시는 다른 TimeString 과 같이 Asia/Seoul 기준 벽시계 시간으로 다룬다.
+// 마감, 정렬이 모두 Asia/Seoul 기준이므로 타임존은 입력받지 않고 Asia/Seoul 로 고정한다.
+type Recurrence struct {
+	Frequency     string                 `json:"frequency"`
+	Interval      int                    `json:"interval"`
+	Weekdays      []string               `json:"weekdays"`
+	Count         *int                   `json:"count"`
+	Until         *transport.TimeString  `json:"until"`
+	StartDateTime transport.TimeString   `json:"startDateTime"`
+	EndDateTime   transport.TimeString   `json:"endDateTime"`
+	ExDates       []transport.TimeString `json:"exDates"`
+}
+
+const (
+	FrequencyDaily  = "DAILY"
+	FrequencyWeekly = "WEEKLY"
+
+	DefaultTimezone = "Asia/Seoul"
+
+	// 규칙 하나로 생성할 수 있는 최대 일정 수
+	MaxOccurrences = 100
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+const (
+	ErrRecurrenceFrequencyInvalid = "지원하지 않는 반복 주기입니다"
+	ErrRecurrenceWeekdayInvalid   = "유효하지 않은 요일입니다"
+	ErrRecurrenceCountInvalid     = "반복 횟수는 1 이상이어야 합니다"
+	ErrRecurrenceEndInvalid       = "종료 일시는 시작 일시 이후여야 합니다"
+	ErrRecurrenceLimitMissing     = "반복 횟수나 반복 종료일 중 하나는 필요합니다"
+	ErrRecurrenceTooMany          = "반복 일정이 너무 많습니다"
+)
+
+// 규칙을 실제 일정들로 펼친다. ExDates 에 포함된 일정은 제외된다.
+func (r *Recurrence) Expand() ([]*Schedule, error) {
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return nil, err
+	}
+
+	start := InLocation(r.StartDateTime, loc)
+	duration := InLocation(r.EndDateTime, loc).Sub(start)
+	if duration <= 0 {
+		return nil, errors.New(ErrRecurrenceEndInvalid)
+	}
+
+	if r.Count == nil && r.Until == nil {
+		return nil, errors.New(ErrRecurrenceLimitMissing)
+	}
+
+	if r.Count != nil && *r.Count <= 0 {
+		return nil, errors.New(ErrRecurrenceCountInvalid)
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	days, err := r.weekdays()
+	if err != nil {
+		return nil, err
+	}
+
+	var until *time.Time
+	if r.Until != nil {
+		u := InLocation(*r.Until, loc)
+		// 첫 일정보다 이른 종료일은 일정을 하나도 만들지 못하므로 잘못된 입력으로 본다.
+		if u.Before(start) {
+			return nil, errors.New(ErrRecurrenceEndInvalid)
+		}
+		until = &u
+	}
+
+	var candidates func(step int) []time.Time
+	switch r.Frequency {
+	case FrequencyDaily:
+		candidates = func(step int) []time.Time {
+			d := start.AddDate(0, 0, step*interval)
+			if len(days) > 0 && !containsWeekday(days, d.Weekday()) {
+				return nil
+			}
+			return []time.Time{d}
+		}
+
+	case FrequencyWeekly:
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		// 주의 시작은 월요일 (WKST=MO)
+		weekStart := start.AddDate(0, 0, -mondayOffset(start.Weekday()))
+		candidates = func(step int) []time.Time {
+			week := weekStart.AddDate(0, 0, step*interval*7)
+			result := make([]time.Time, 0, len(days))
+			for _, d := range days {
+				result = append(result, week.AddDate(0, 0, mondayOffset(d)))
+			}
+			return result
+		}
+
+	default:
+		return nil, errors.New(ErrRecurrenceFrequencyInvalid)
+	}
+
+	exDates := make(map[int64]bool)
+	for _, v := range r.ExDates {
+		exDates[time.Time(v).Unix()] = true
+	}
+
+	result := make([]*Schedule, 0)
+	generated := 0
+	for step := 0; ; step++ {
+		for _, c := range candidates(step) {
+			if c.Before(start) {
+				continue
+			}
+
+			if until != nil && c.After(*until) {
+				return result, nil
+			}
+
+			if r.Count != nil && generated >= *r.Count {
+				return result, nil
+			}
+
+			generated++
+			if generated > MaxOccurrences {
+				return nil, errors.New(ErrRecurrenceTooMany)
+			}
+
+			s := wallClock(c)
+			if exDates[time.Time(s).Unix()] {
+				continue
+			}
+
+			result = append(result, &Schedule{
+				StartDateTime: s,
+				EndDateTime:   wallClock(c.Add(duration)),
+			})
+		}
+	}
+}
+
+func (r *Recurrence) weekdays() ([]time.Weekday, error) {
+	result := make([]time.Weekday, 0)
+	for _, v := range r.Weekdays {
+		d, ok := weekdays[v]
+		if !ok {
+			return nil, errors.New(ErrRecurrenceWeekdayInvalid)
+		}
+		if !containsWeekday(result, d) {
+			result = append(result, d)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return mondayOffset(result[i]) < mondayOffset(result[j])
+	})
+	return result, nil
+}
+
+// TimeString 의 벽시계 시간을 loc 기준 시간으로
+func InLocation(t transport.TimeString, loc *time.Location) time.Time {
+	v := time.Time(t)
+	return time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), 0, loc)
+}
+
+// loc 기준 시간을 TimeString 벽시계 시간으로
+func wallClock(t time.Time) transport.TimeString {
+	return transport.TimeString(time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC))
+}
+
+func mondayOffset(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+func containsWeekday(days []time.Weekday, d time.Weekday) bool {
+	for _, v := range days {
+		if v == d {
+			return true
+		}
+	}
+	return false
+}
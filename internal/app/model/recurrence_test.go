@@ -0,0 +1,133 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"onthemat/internal/app/model"
+	"onthemat/internal/app/transport"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func timeString(v string) transport.TimeString {
+	t, _ := time.Parse("2006-01-02T15:04:05", v)
+	return transport.TimeString(t)
+}
+
+func startDateTimes(schedules []*model.Schedule) []string {
+	result := make([]string, 0)
+	for _, s := range schedules {
+		result = append(result, s.StartDateTime.ToString())
+	}
+	return result
+}
+
+func TestRecurrenceExpand(t *testing.T) {
+	count := 4
+
+	t.Run("매주 화, 목 4회", func(t *testing.T) {
+		// 2022-12-06 은 화요일
+		r := &model.Recurrence{
+			Frequency:     model.FrequencyWeekly,
+			Weekdays:      []string{"TH", "TU"},
+			Count:         &count,
+			StartDateTime: timeString("2022-12-06T19:00:00"),
+			EndDateTime:   timeString("2022-12-06T20:00:00"),
+		}
+
+		schedules, err := r.Expand()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"2022-12-06T19:00:00",
+			"2022-12-08T19:00:00",
+			"2022-12-13T19:00:00",
+			"2022-12-15T19:00:00",
+		}, startDateTimes(schedules))
+		assert.Equal(t, "2022-12-15T20:00:00", schedules[3].EndDateTime.ToString())
+	})
+
+	t.Run("격주, 종료일까지, 제외 일정", func(t *testing.T) {
+		until := timeString("2022-12-31T23:59:59")
+		r := &model.Recurrence{
+			Frequency:     model.FrequencyWeekly,
+			Interval:      2,
+			Until:         &until,
+			StartDateTime: timeString("2022-12-01T10:00:00"),
+			EndDateTime:   timeString("2022-12-01T12:00:00"),
+			ExDates:       []transport.TimeString{timeString("2022-12-15T10:00:00")},
+		}
+
+		schedules, err := r.Expand()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"2022-12-01T10:00:00",
+			"2022-12-29T10:00:00",
+		}, startDateTimes(schedules))
+	})
+
+	t.Run("매일, 주말 제외", func(t *testing.T) {
+		// 2022-12-09 는 금요일
+		r := &model.Recurrence{
+			Frequency:     model.FrequencyDaily,
+			Weekdays:      []string{"MO", "TU", "WE", "TH", "FR"},
+			Count:         &count,
+			StartDateTime: timeString("2022-12-09T09:00:00"),
+			EndDateTime:   timeString("2022-12-09T10:00:00"),
+		}
+
+		schedules, err := r.Expand()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"2022-12-09T09:00:00",
+			"2022-12-12T09:00:00",
+			"2022-12-13T09:00:00",
+			"2022-12-14T09:00:00",
+		}, startDateTimes(schedules))
+	})
+
+	t.Run("실패", func(t *testing.T) {
+		_, err := (&model.Recurrence{
+			Frequency:     model.FrequencyWeekly,
+			StartDateTime: timeString("2022-12-01T10:00:00"),
+			EndDateTime:   timeString("2022-12-01T12:00:00"),
+		}).Expand()
+		assert.EqualError(t, err, model.ErrRecurrenceLimitMissing)
+
+		_, err = (&model.Recurrence{
+			Frequency:     model.FrequencyWeekly,
+			Count:         &count,
+			StartDateTime: timeString("2022-12-01T12:00:00"),
+			EndDateTime:   timeString("2022-12-01T10:00:00"),
+		}).Expand()
+		assert.EqualError(t, err, model.ErrRecurrenceEndInvalid)
+
+		zero := 0
+		_, err = (&model.Recurrence{
+			Frequency:     model.FrequencyWeekly,
+			Count:         &zero,
+			StartDateTime: timeString("2022-12-01T10:00:00"),
+			EndDateTime:   timeString("2022-12-01T12:00:00"),
+		}).Expand()
+		assert.EqualError(t, err, model.ErrRecurrenceCountInvalid)
+
+		// 반복 종료일이 첫 일정보다 이른 경우
+		until := timeString("2022-11-30T10:00:00")
+		_, err = (&model.Recurrence{
+			Frequency:     model.FrequencyWeekly,
+			Until:         &until,
+			StartDateTime: timeString("2022-12-01T10:00:00"),
+			EndDateTime:   timeString("2022-12-01T12:00:00"),
+		}).Expand()
+		assert.EqualError(t, err, model.ErrRecurrenceEndInvalid)
+
+		until = timeString("2030-12-01T10:00:00")
+		_, err = (&model.Recurrence{
+			Frequency:     model.FrequencyDaily,
+			Until:         &until,
+			StartDateTime: timeString("2022-12-01T10:00:00"),
+			EndDateTime:   timeString("2022-12-01T12:00:00"),
+		}).Expand()
+		assert.EqualError(t, err, model.ErrRecurrenceTooMany)
+	})
+}
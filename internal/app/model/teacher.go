@@ -69,7 +69,8 @@ func (Teacher) Edges() []ent.Edge {
 			Field("user_id"),
 
 		edge.From("recruitment_instead", RecruitmentInstead.Type).
-			Ref("applicant"),
+			Ref("applicant").
+			Through("applications", RecruitmentApplication.Type),
 
 		edge.To("passer", RecruitmentInstead.Type),
 
@@ -9,6 +9,7 @@ import (
 	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 )
 
 type User struct {
@@ -47,24 +48,6 @@ func (User) Fields() []ent.Field {
 			Sensitive().
 			Comment("패스워드"),
 
-		field.String("tempPassword").
-			Optional().
-			Nillable().
-			Sensitive().
-			Comment("임시 비밀번호"),
-
-		field.Int8("socialName").
-			GoType(SocialType(0)).
-			Optional().
-			Nillable().
-			Comment("소셜 로그인을 제공한 업체 이름 1:kakao, 2:google, 3:naver"),
-
-		field.String("socialKey").
-			Optional().
-			Nillable().
-			Unique().
-			Comment("소셜 로그인 시 발급되는 고유 키"),
-
 		field.String("nickname").
 			SchemaType(map[string]string{
 				dialect.Postgres: "varchar(50)",
@@ -89,18 +72,64 @@ func (User) Fields() []ent.Field {
 			Nillable().
 			Comment("휴대폰 번호"),
 
+		field.Bool("isPhoneVerified").
+			Default(false).
+			Comment("휴대폰 번호 인증 여부. 번호가 바뀌면 다시 인증해야 한다."),
+
 		field.Time("termAgreeAt").
 			SchemaType(map[string]string{
 				dialect.Postgres: "timestamp",
 			}).
 			Comment("약관 동의 일시"),
 
+		field.String("calendarToken").
+			Optional().
+			Nillable().
+			Unique().
+			Sensitive().
+			Comment("캘린더(.ics) 구독용 비밀 토큰의 sha256 해시"),
+
+		field.String("totpSecret").
+			Optional().
+			Nillable().
+			Sensitive().
+			Comment("2단계 인증(TOTP) 비밀키. base32 비밀키를 TOTP_ENCRYPTION_KEY 로 암호화한 값"),
+
+		field.JSON("totpRecoveryCodes", []string{}).
+			Optional().
+			Sensitive().
+			Comment("2단계 인증 복구 코드의 해시(sha256). 사용한 코드는 목록에서 지운다."),
+
+		field.Time("totpEnabledAt").
+			SchemaType(map[string]string{
+				dialect.Postgres: "timestamp",
+			}).
+			Optional().
+			Nillable().
+			Comment("2단계 인증을 켠 일시. 비어있으면 사용하지 않는다."),
+
 		field.Time("lastLoginAt").
 			Default(time.Now).
 			SchemaType(map[string]string{
 				dialect.Postgres: "timestamp",
 			}).
 			Comment("마지막 로그인 일시"),
+
+		field.Time("deletedAt").
+			SchemaType(map[string]string{
+				dialect.Postgres: "timestamp",
+			}).
+			Optional().
+			Nillable().
+			Comment("탈퇴 일시. 유예 기간 동안은 복구할 수 있다."),
+
+		field.Time("anonymizedAt").
+			SchemaType(map[string]string{
+				dialect.Postgres: "timestamp",
+			}).
+			Optional().
+			Nillable().
+			Comment("유예 기간이 지나 개인정보를 익명화한 일시"),
 	}
 }
 
@@ -110,6 +139,13 @@ func (User) Mixin() []ent.Mixin {
 	}
 }
 
+func (User) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("deletedAt"),
+	}
+}
+
+// 유저는 탈퇴해도 행을 지우지 않는다. (학원의 지난 공고, 선생님의 합격 이력 유지)
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("Academy", Academy.Type).
@@ -125,5 +161,11 @@ func (User) Edges() []ent.Edge {
 			}),
 
 		edge.To("Image", Image.Type),
+
+		// 연결된 소셜 로그인 계정
+		edge.To("Identities", UserIdentity.Type).
+			Annotations(entsql.Annotation{
+				OnDelete: entsql.Cascade,
+			}),
 	}
 }
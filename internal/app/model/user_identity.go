@@ -0,0 +1,71 @@
+package model
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// 유저에 연결된 소셜 로그인 계정. 한 유저에 업체별로 하나씩 연결할 수 있다.
+type UserIdentity struct {
+	ent.Schema
+}
+
+func (UserIdentity) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "user_identities"},
+	}
+}
+
+func (UserIdentity) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("user_id").
+			Comment("foreignKey"),
+
+		field.Int8("provider").
+			GoType(SocialType(0)).
+			Comment("소셜 로그인을 제공한 업체 이름 1:kakao, 2:google, 3:naver, 4:apple"),
+
+		field.String("providerKey").
+			Comment("소셜 로그인 시 발급되는 고유 키"),
+
+		field.String("email").
+			SchemaType(map[string]string{
+				dialect.Postgres: "varchar(100)",
+			}).
+			MaxLen(100).
+			Optional().
+			Nillable().
+			Comment("소셜 계정의 이메일"),
+	}
+}
+
+func (UserIdentity) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		DefaultTimeMixin{},
+	}
+}
+
+func (UserIdentity) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("provider", "providerKey").
+			Unique(),
+
+		index.Fields("user_id", "provider").
+			Unique(),
+	}
+}
+
+func (UserIdentity) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("User", User.Type).
+			Ref("Identities").
+			Unique().
+			Required().
+			Field("user_id"),
+	}
+}
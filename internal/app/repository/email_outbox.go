@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"onthemat/internal/app/model"
+	"onthemat/pkg/ent"
+	"onthemat/pkg/ent/emailoutbox"
+	"onthemat/pkg/entx"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+type EmailOutboxRepository interface {
+	Create(ctx context.Context, mail *ent.EmailOutbox) (*ent.EmailOutbox, error)
+	// 보낼 때가 된 메일을 오래된 순서로 가져가고, lease 동안 다른 인스턴스가 가져가지 못하게 다음 시도 일시를 미룬다.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*ent.EmailOutbox, error)
+	// 보낸 메일은 템플릿 데이터(토큰)를 지운다.
+	MarkSent(ctx context.Context, id int, sentAt time.Time) error
+	// dead 면 더 이상 재시도하지 않는다.
+	MarkFailed(ctx context.Context, id int, nextAttemptAt time.Time, lastError string, dead bool) error
+}
+
+type emailOutboxRepository struct {
+	db *ent.Client
+}
+
+func NewEmailOutboxRepository(db *ent.Client) EmailOutboxRepository {
+	return &emailOutboxRepository{
+		db: db,
+	}
+}
+
+func (repo *emailOutboxRepository) Create(ctx context.Context, mail *ent.EmailOutbox) (*ent.EmailOutbox, error) {
+	return createEmailOutbox(ctx, repo.db.EmailOutbox, mail)
+}
+
+// 다른 저장소에서 트랜잭션 안에서 메일을 쌓을 때도 사용한다.
+func createEmailOutbox(ctx context.Context, client *ent.EmailOutboxClient, mail *ent.EmailOutbox) (*ent.EmailOutbox, error) {
+	nextAttemptAt := mail.NextAttemptAt
+	if nextAttemptAt.IsZero() {
+		nextAttemptAt = time.Now()
+	}
+
+	return client.Create().
+		SetRecipient(mail.Recipient).
+		SetTemplate(mail.Template).
+		SetData(mail.Data).
+		SetNextAttemptAt(nextAttemptAt).
+		Save(ctx)
+}
+
+// 동시에 가져가는 인스턴스끼리 같은 메일을 잡지 않도록 잠긴 행은 건너뛴다. (FOR UPDATE SKIP LOCKED)
+// 보내는 도중 인스턴스가 죽으면 lease 가 지난 뒤 다시 가져간다.
+func (repo *emailOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) (mails []*ent.EmailOutbox, err error) {
+	err = entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		query := tx.EmailOutbox.Query().
+			Where(
+				emailoutbox.StatusEQ(model.EmailPending),
+				emailoutbox.NextAttemptAtLTE(now),
+			).
+			Order(ent.Asc(emailoutbox.FieldNextAttemptAt), ent.Asc(emailoutbox.FieldID)).
+			Limit(limit)
+		query.Modify(func(s *sql.Selector) {
+			s.ForUpdate(sql.WithLockAction(sql.SkipLocked))
+		})
+		if mails, err = query.All(ctx); err != nil || len(mails) == 0 {
+			return
+		}
+
+		ids := make([]int, len(mails))
+		for i, mail := range mails {
+			ids[i] = mail.ID
+		}
+		return tx.EmailOutbox.Update().
+			Where(emailoutbox.IDIn(ids...)).
+			SetNextAttemptAt(now.Add(lease)).
+			Exec(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (repo *emailOutboxRepository) MarkSent(ctx context.Context, id int, sentAt time.Time) error {
+	return repo.db.EmailOutbox.Update().
+		Where(
+			emailoutbox.IDEQ(id),
+			emailoutbox.StatusEQ(model.EmailPending),
+		).
+		SetStatus(model.EmailSent).
+		SetSentAt(sentAt).
+		AddAttempts(1).
+		ClearData().
+		ClearLastError().
+		Exec(ctx)
+}
+
+func (repo *emailOutboxRepository) MarkFailed(ctx context.Context, id int, nextAttemptAt time.Time, lastError string, dead bool) error {
+	status := model.EmailPending
+	if dead {
+		status = model.EmailDead
+	}
+
+	return repo.db.EmailOutbox.Update().
+		Where(
+			emailoutbox.IDEQ(id),
+			emailoutbox.StatusEQ(model.EmailPending),
+		).
+		SetStatus(status).
+		SetNextAttemptAt(nextAttemptAt).
+		SetLastError(lastError).
+		AddAttempts(1).
+		Exec(ctx)
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/config"
+	"onthemat/internal/app/infrastructure"
+	"onthemat/internal/app/model"
+	"onthemat/internal/app/utils"
+	"onthemat/pkg/ent"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type EmailOutboxRepositoryTestSuite struct {
+	suite.Suite
+	config          *config.Config
+	client          *ent.Client
+	emailOutboxRepo EmailOutboxRepository
+	userRepo        UserRepository
+	ctx             context.Context
+}
+
+// 모든 테스트 시작 전 1회
+func (ts *EmailOutboxRepositoryTestSuite) SetupSuite() {
+	t := ts.T()
+	ts.ctx = context.Background()
+
+	// 도커 디비 삭제 후 생성
+	utils.RepoTestClose(t)
+	time.Sleep(1 * time.Second)
+	ts.config = utils.RepoTestInit(t)
+	time.Sleep(3 * time.Second)
+	// 포스트그레스 연결
+	ts.client = infrastructure.NewPostgresDB(ts.config)
+
+	// 모듈 연결
+	ts.emailOutboxRepo = NewEmailOutboxRepository(ts.client)
+	ts.userRepo = NewUserRepository(ts.client)
+}
+
+// 모든 테스트 종료 후 1회
+func (ts *EmailOutboxRepositoryTestSuite) TearDownSuite() {
+	ts.client.Close()
+	utils.RepoTestClose(nil)
+}
+
+// 각 테스트 종료 후 N회
+func (ts *EmailOutboxRepositoryTestSuite) TearDownTest() {
+	utils.RepoTestTruncateTable(context.Background(), ts.client)
+}
+
+func (ts *EmailOutboxRepositoryTestSuite) TestCreateWithEmail() {
+	email := "new@onthemat.com"
+	password := "password"
+	mail := &ent.EmailOutbox{
+		Recipient: email,
+		Template:  "verify_email",
+		Data:      map[string]string{"verifyUrl": "https://onthemat.com/verify"},
+	}
+
+	ts.Run("유저와 메일을 함께 생성", func() {
+		_, err := ts.userRepo.CreateWithEmail(ts.ctx, &ent.User{Email: &email, Password: &password}, mail)
+		ts.NoError(err)
+
+		mails, err := ts.emailOutboxRepo.ClaimDue(ts.ctx, time.Now(), time.Minute, 10)
+		ts.NoError(err)
+		ts.Len(mails, 1)
+		ts.Equal(model.EmailPending, mails[0].Status)
+		ts.Equal(mail.Data, mails[0].Data)
+	})
+
+	ts.Run("유저 생성에 실패하면 메일도 쌓이지 않음", func() {
+		_, err := ts.userRepo.CreateWithEmail(ts.ctx, &ent.User{Email: &email, Password: &password}, mail)
+		ts.True(ent.IsConstraintError(err))
+		ts.Equal(1, ts.client.EmailOutbox.Query().CountX(ts.ctx))
+	})
+}
+
+func (ts *EmailOutboxRepositoryTestSuite) TestClaimDueAndMark() {
+	now := time.Now()
+	later, err := ts.emailOutboxRepo.Create(ts.ctx, &ent.EmailOutbox{
+		Recipient:     "later@onthemat.com",
+		Template:      "password_reset",
+		NextAttemptAt: now.Add(time.Hour),
+	})
+	ts.NoError(err)
+	due, err := ts.emailOutboxRepo.Create(ts.ctx, &ent.EmailOutbox{
+		Recipient:     "due@onthemat.com",
+		Template:      "password_reset",
+		Data:          map[string]string{"resetUrl": "https://onthemat.com/reset?token=abc"},
+		NextAttemptAt: now.Add(-time.Minute),
+	})
+	ts.NoError(err)
+
+	ts.Run("보낼 때가 된 메일만 가져가고, lease 동안은 다시 가져가지 않음", func() {
+		mails, err := ts.emailOutboxRepo.ClaimDue(ts.ctx, now, 5*time.Minute, 10)
+		ts.NoError(err)
+		ts.Len(mails, 1)
+		ts.Equal(due.ID, mails[0].ID)
+
+		mails, err = ts.emailOutboxRepo.ClaimDue(ts.ctx, now, 5*time.Minute, 10)
+		ts.NoError(err)
+		ts.Len(mails, 0)
+
+		// 보내던 인스턴스가 죽어 lease 가 지나면 다시 가져간다.
+		mails, err = ts.emailOutboxRepo.ClaimDue(ts.ctx, now.Add(6*time.Minute), 5*time.Minute, 10)
+		ts.NoError(err)
+		ts.Len(mails, 1)
+		ts.Equal(due.ID, mails[0].ID)
+	})
+
+	ts.Run("실패하면 다음 시도 시간을 늦추고, dead 면 더 이상 조회되지 않음", func() {
+		err := ts.emailOutboxRepo.MarkFailed(ts.ctx, later.ID, now.Add(-time.Second), "421", false)
+		ts.NoError(err)
+
+		mail, err := ts.client.EmailOutbox.Get(ts.ctx, later.ID)
+		ts.NoError(err)
+		ts.Equal(1, mail.Attempts)
+		ts.Equal("421", *mail.LastError)
+
+		err = ts.emailOutboxRepo.MarkFailed(ts.ctx, later.ID, now, "550", true)
+		ts.NoError(err)
+
+		mails, err := ts.emailOutboxRepo.ClaimDue(ts.ctx, now.Add(time.Hour), time.Minute, 10)
+		ts.NoError(err)
+		ts.Len(mails, 1)
+		ts.Equal(due.ID, mails[0].ID)
+	})
+
+	ts.Run("보낸 메일은 데이터를 지움", func() {
+		err := ts.emailOutboxRepo.MarkSent(ts.ctx, due.ID, now)
+		ts.NoError(err)
+
+		mail, err := ts.client.EmailOutbox.Get(ts.ctx, due.ID)
+		ts.NoError(err)
+		ts.Equal(model.EmailSent, mail.Status)
+		ts.Nil(mail.Data)
+		ts.NotNil(mail.SentAt)
+
+		mails, err := ts.emailOutboxRepo.ClaimDue(ts.ctx, now.Add(2*time.Hour), time.Minute, 10)
+		ts.NoError(err)
+		ts.Len(mails, 0)
+	})
+}
+
+func TestEmailOutboxRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailOutboxRepositoryTestSuite))
+}
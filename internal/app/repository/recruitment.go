@@ -3,9 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
+	"strconv"
 	"time"
 
+	"onthemat/internal/app/common"
 	"onthemat/internal/app/model"
 	"onthemat/internal/app/transport"
 	"onthemat/internal/app/transport/request"
@@ -13,8 +14,11 @@ import (
 	"onthemat/pkg/ent"
 	"onthemat/pkg/ent/academy"
 	"onthemat/pkg/ent/areasigungu"
+	"onthemat/pkg/ent/predicate"
 	"onthemat/pkg/ent/recruitment"
+	ra "onthemat/pkg/ent/recruitmentapplication"
 	ri "onthemat/pkg/ent/recruitmentinstead"
+	ris "onthemat/pkg/ent/recruitmentinsteadschedule"
 	"onthemat/pkg/ent/teacher"
 	"onthemat/pkg/ent/yoga"
 	"onthemat/pkg/entx"
@@ -28,10 +32,38 @@ type RecruitmentRepository interface {
 	Update(ctx context.Context, d *ent.Recruitment) (err error)
 	Patch(ctx context.Context, d *request.RecruitmentPatchBody, id, academyId int) (isCreated bool, err error)
 	PatchDeletedAt(ctx context.Context, id, academyId int) (err error)
-	Total(ctx context.Context, startDateTime, endDateTime *transport.TimeString, yogaIds, sigunguId *[]int) (result int, err error)
-	List(ctx context.Context, pgModule *utils.Pagination, startDateTime, endDateTime *transport.TimeString, yogaIds, sigunguId *[]int) ([]*ent.Recruitment, error)
+	Total(ctx context.Context,
+		startDateTime, endDateTime *transport.TimeString, yogaIds, sigunguId, sidoIds *[]int,
+		minPay *int, payUnit *model.PayUnit, maxCareer *int, isFinish, hasOpenSlot *bool) (result int, err error)
+	List(ctx context.Context, pgModule *utils.Pagination,
+		startDateTime, endDateTime *transport.TimeString, yogaIds, sigunguId, sidoIds *[]int,
+		minPay *int, payUnit *model.PayUnit, maxCareer *int, isFinish, hasOpenSlot *bool,
+		orderCol *string, orderType common.Sorts) ([]*ent.Recruitment, error)
 	Exist(ctx context.Context, id int) (bool, error)
 	Get(ctx context.Context, id int) (*ent.Recruitment, error)
+
+	// 대강 지원
+	GetInstead(ctx context.Context, id, insteadId int) (*ent.RecruitmentInstead, error)
+	GetApplication(ctx context.Context, insteadId, teacherId int) (*ent.RecruitmentApplication, error)
+	CreateApplication(ctx context.Context, insteadId, teacherId int) (err error)
+	UpdateApplicationStatus(ctx context.Context, insteadId, teacherId int, status model.ApplicationStatus) (err error)
+	ApplicationList(ctx context.Context, insteadId int) ([]*ent.RecruitmentApplication, error)
+	// 선생님이 이미 합격한 대강과 일정이 겹치면 합격시키지 않고 겹치는 대강들을 반환한다.
+	AcceptApplication(ctx context.Context, insteadId, teacherId int) (conflicts []*ent.RecruitmentInstead, err error)
+	CancelAcceptedApplication(ctx context.Context, insteadId, teacherId int) (err error)
+	OverlappedPasserInsteadList(ctx context.Context, teacherId, insteadId int) ([]*ent.RecruitmentInstead, error)
+
+	// 모든 일정이 지난 공고 마감
+	FinishExpired(ctx context.Context, now transport.TimeString) (count int, err error)
+
+	// 캘린더
+	PasserScheduleList(ctx context.Context, teacherId int) ([]*ent.RecruitmentInstead, error)
+	WriterScheduleList(ctx context.Context, academyId int) ([]*ent.RecruitmentInstead, error)
+
+	// 대강 일정 (개별 수정)
+	GetSchedule(ctx context.Context, insteadId, scheduleId int) (*ent.RecruitmentInsteadSchedule, error)
+	UpdateSchedule(ctx context.Context, scheduleId int, startDateTime, endDateTime transport.TimeString) (err error)
+	DeleteSchedule(ctx context.Context, insteadId, scheduleId int, recurrence *model.Recurrence) (err error)
 }
 
 type recruitmentRepository struct {
@@ -82,7 +114,12 @@ func (repo *recruitmentRepository) PatchDeletedAt(ctx context.Context, id, acade
 	return
 }
 
-const ErrOnlyOwnUser = "소유자만 접근할 수 있습니다"
+const (
+	ErrOnlyOwnUser          = "소유자만 접근할 수 있습니다"
+	ErrInsteadAlreadyFilled = "이미 합격자가 정해진 대강입니다"
+	// 합격 처리하는 사이 지원자가 지원을 취소하는 등 상태가 바뀐 경우
+	ErrApplicationStatusChanged = "지원 상태가 변경되었습니다"
+)
 
 func (repo *recruitmentRepository) Update(ctx context.Context, d *ent.Recruitment) (err error) {
 	return entx.WithTx(ctx, repo.db.Debug(), func(tx *ent.Tx) (err error) {
@@ -152,7 +189,7 @@ func (repo *recruitmentRepository) Update(ctx context.Context, d *ent.Recruitmen
 }
 
 func (repo *recruitmentRepository) Patch(ctx context.Context, d *request.RecruitmentPatchBody, id, academyId int) (isCreated bool, err error) {
-	entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+	err = entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
 		client := tx.Client()
 
 		clause := client.Recruitment.Update().
@@ -180,28 +217,54 @@ func (repo *recruitmentRepository) Patch(ctx context.Context, d *request.Recruit
 				s := structs.New(v)
 				res := utils.GetUpdateableDataV2(s, ri.Columns)
 
+				// 급여 단위는 문자열로 받아 저장값으로 변환한다.
+				delete(res, ri.FieldPayUnit)
+				if payUnit := new(model.PayUnit).ToPayUnit(v.PayUnit); payUnit != nil {
+					res[ri.FieldPayUnit] = *payUnit
+				}
+
 				c := client.RecruitmentInstead
 
 				// Update
 				if v.ID != nil {
-					u := c.Update().Where(ri.IDEQ(*v.ID))
+					u := c.Update().Where(ri.IDEQ(*v.ID), ri.RecruitmentIDEQ(id))
 
 					for key, val := range res {
 						u.Mutation().SetField(key, val)
 					}
+					if v.Schedules != nil {
+						u.ClearRecurrence()
+					}
+
+					rowAffected, err := u.Save(ctx)
+					if err != nil {
+						return err
+					}
+					if rowAffected != 1 {
+						return errors.New(ErrOnlyOwnUser)
+					}
 
-					if err = u.Exec(ctx); err != nil {
-						return
+					if v.Schedules != nil {
+						err = repo.recruitInsteadRepo.replaceSchedules(ctx, client, newSchedules(v.Schedules), *v.ID)
+						if err != nil {
+							return err
+						}
 					}
 					// Create
 				} else {
-					cr := c.Create()
+					cr := c.Create().SetRecuritmentID(id)
 
 					for key, val := range res {
 						cr.Mutation().SetField(key, val)
 					}
-					if err = cr.Exec(ctx); err != nil {
-						return
+					created, err := cr.Save(ctx)
+					if err != nil {
+						return err
+					}
+
+					err = repo.recruitInsteadRepo.createSchedules(ctx, client, newSchedules(v.Schedules), created.ID)
+					if err != nil {
+						return err
 					}
 					isCreated = true
 				}
@@ -212,10 +275,26 @@ func (repo *recruitmentRepository) Patch(ctx context.Context, d *request.Recruit
 	return
 }
 
+func newSchedules(vals []request.Schedule) []*ent.RecruitmentInsteadSchedule {
+	result := make([]*ent.RecruitmentInsteadSchedule, len(vals))
+	for i, v := range vals {
+		result[i] = &ent.RecruitmentInsteadSchedule{
+			StartDateTime: v.StartDateTime,
+			EndDateTime:   v.EndDateTime,
+		}
+	}
+	return result
+}
+
 func (repo *recruitmentRepository) Get(ctx context.Context, id int) (*ent.Recruitment, error) {
 	return repo.db.Debug().Recruitment.Query().
 		WithRecruitmentInstead(
 			func(riq *ent.RecruitmentInsteadQuery) {
+				riq.WithSchedules(
+					func(risq *ent.RecruitmentInsteadScheduleQuery) {
+						risq.Order(ent.Asc(ris.FieldStartDateTime))
+					},
+				)
 				riq.WithYoga(
 					func(yq *ent.YogaQuery) {
 						yq.Select(yoga.FieldID, yoga.FieldNameKor)
@@ -236,11 +315,18 @@ func (repo *recruitmentRepository) Total(
 	startDateTime,
 	endDateTime *transport.TimeString,
 	yogaIds,
-	sigunguId *[]int,
+	sigunguId,
+	sidoIds *[]int,
+	minPay *int,
+	payUnit *model.PayUnit,
+	maxCareer *int,
+	isFinish,
+	hasOpenSlot *bool,
 ) (result int, err error) {
-	clause := repo.db.Recruitment.Query()
+	clause := repo.db.Recruitment.Query().
+		Where(recruitment.DeletedAtIsNil())
 
-	clause = repo.conditionQuery(clause, startDateTime, endDateTime, yogaIds, sigunguId)
+	clause = repo.conditionQuery(clause, startDateTime, endDateTime, yogaIds, sigunguId, sidoIds, minPay, payUnit, maxCareer, isFinish, hasOpenSlot)
 	result, err = clause.Count(ctx)
 	return
 }
@@ -251,12 +337,25 @@ func (repo *recruitmentRepository) List(
 	startDateTime,
 	endDateTime *transport.TimeString,
 	yogaIds,
-	sigunguId *[]int,
+	sigunguId,
+	sidoIds *[]int,
+	minPay *int,
+	payUnit *model.PayUnit,
+	maxCareer *int,
+	isFinish,
+	hasOpenSlot *bool,
+	orderCol *string,
+	orderType common.Sorts,
 ) ([]*ent.Recruitment, error) {
 	clause := repo.db.Debug().Recruitment.Query().
 		WithRecruitmentInstead(
 			func(riq *ent.RecruitmentInsteadQuery) {
-				riq.Select(ri.FieldID, ri.FieldRecruitmentID, ri.FieldSchedule)
+				riq.Select(ri.FieldID, ri.FieldRecruitmentID, ri.FieldTeacherID, ri.FieldMinCareerMonth, ri.FieldPayAmount, ri.FieldPayCurrency, ri.FieldPayUnit)
+				riq.WithSchedules(
+					func(risq *ent.RecruitmentInsteadScheduleQuery) {
+						risq.Order(ent.Asc(ris.FieldStartDateTime))
+					},
+				)
 				riq.WithYoga(
 					func(yq *ent.YogaQuery) {
 						yq.Select(yoga.FieldLevel, yoga.FieldNameKor)
@@ -272,10 +371,25 @@ func (repo *recruitmentRepository) List(
 		}).
 		Where(recruitment.DeletedAtIsNil()).
 		Limit(pgModule.GetLimit()).
-		Offset(pgModule.GetOffset()).
-		Order(ent.Desc(recruitment.FieldUpdatedAt))
+		Offset(pgModule.GetOffset())
+
+	useableOrder := map[string]func(orderType common.Sorts) ent.OrderFunc{
+		"START":   orderBySoonestStart,
+		"NEWEST":  orderByColumn(recruitment.FieldCreatedAt),
+		"PAY":     orderByMaxPay(payUnit),
+		"UPDATED": orderByColumn(recruitment.FieldUpdatedAt),
+	}
 
-	clause = repo.conditionQuery(clause, startDateTime, endDateTime, yogaIds, sigunguId)
+	order := useableOrder["UPDATED"]
+	if orderCol != nil {
+		if o, ok := useableOrder[*orderCol]; ok {
+			order = o
+		}
+	}
+	// 정렬값이 같으면 최신 공고 먼저
+	clause.Order(order(orderType), ent.Desc(recruitment.FieldID))
+
+	clause = repo.conditionQuery(clause, startDateTime, endDateTime, yogaIds, sigunguId, sidoIds, minPay, payUnit, maxCareer, isFinish, hasOpenSlot)
 	return clause.All(ctx)
 }
 
@@ -283,34 +397,295 @@ func (repo *recruitmentRepository) Exist(ctx context.Context, id int) (bool, err
 	return repo.db.Recruitment.Query().Where(recruitment.IDEQ(id)).Exist(ctx)
 }
 
-// 시간으로 조회 // 요가로 조회 // 학원 위치로 조회
+func (repo *recruitmentRepository) GetInstead(ctx context.Context, id, insteadId int) (*ent.RecruitmentInstead, error) {
+	return repo.db.RecruitmentInstead.Query().
+		WithRecuritment().
+		WithSchedules().
+		Where(
+			ri.IDEQ(insteadId),
+			ri.HasRecuritmentWith(
+				recruitment.IDEQ(id),
+				recruitment.DeletedAtIsNil(),
+			),
+		).
+		Only(ctx)
+}
+
+func (repo *recruitmentRepository) GetApplication(ctx context.Context, insteadId, teacherId int) (*ent.RecruitmentApplication, error) {
+	return repo.db.RecruitmentApplication.Query().
+		Where(
+			ra.RInsteadIDEQ(insteadId),
+			ra.TeacherIDEQ(teacherId),
+		).
+		Only(ctx)
+}
+
+func (repo *recruitmentRepository) CreateApplication(ctx context.Context, insteadId, teacherId int) (err error) {
+	return repo.db.RecruitmentApplication.Create().
+		SetRInsteadID(insteadId).
+		SetTeacherID(teacherId).
+		SetStatus(model.ApplicationApplied).
+		Exec(ctx)
+}
+
+func (repo *recruitmentRepository) UpdateApplicationStatus(ctx context.Context, insteadId, teacherId int, status model.ApplicationStatus) (err error) {
+	return repo.db.RecruitmentApplication.Update().
+		Where(
+			ra.RInsteadIDEQ(insteadId),
+			ra.TeacherIDEQ(teacherId),
+		).
+		SetStatus(status).
+		Exec(ctx)
+}
+
+func (repo *recruitmentRepository) ApplicationList(ctx context.Context, insteadId int) ([]*ent.RecruitmentApplication, error) {
+	return repo.db.RecruitmentApplication.Query().
+		WithTeacher(func(tq *ent.TeacherQuery) {
+			tq.Select(
+				teacher.FieldID,
+				teacher.FieldName,
+				teacher.FieldAge,
+				teacher.FieldProfileImageUrl,
+				teacher.FieldIntroduce,
+			)
+		}).
+		Where(ra.RInsteadIDEQ(insteadId)).
+		Order(ent.Asc(ra.FieldCreatedAt)).
+		All(ctx)
+}
+
+// 합격 처리 후 대강의 합격자를 지정하고, 대기 중인 나머지 지원자들은 불합격 처리한다.
+// 같은 선생님을 동시에 합격시키는 요청끼리 일정 겹침 확인을 건너뛰지 않도록 선생님 행을 잠근 뒤 확인한다.
+func (repo *recruitmentRepository) AcceptApplication(ctx context.Context, insteadId, teacherId int) (conflicts []*ent.RecruitmentInstead, err error) {
+	err = entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		client := tx.Client()
+
+		lock := client.Teacher.Query().Where(teacher.IDEQ(teacherId))
+		lock.Modify(func(s *sql.Selector) {
+			s.ForUpdate()
+		})
+		if _, err = lock.Only(ctx); err != nil {
+			return
+		}
+
+		conflicts, err = overlappedPasserInsteadList(ctx, client, teacherId, insteadId)
+		if err != nil || len(conflicts) > 0 {
+			return
+		}
+
+		rowAffected, err := client.RecruitmentInstead.Update().
+			Where(
+				ri.IDEQ(insteadId),
+				ri.TeacherIDIsNil(),
+			).
+			SetPasserID(teacherId).
+			Save(ctx)
+		if err != nil {
+			return
+		}
+
+		if rowAffected != 1 {
+			err = errors.New(ErrInsteadAlreadyFilled)
+			return
+		}
+
+		rowAffected, err = client.RecruitmentApplication.Update().
+			Where(
+				ra.RInsteadIDEQ(insteadId),
+				ra.TeacherIDEQ(teacherId),
+				ra.StatusIn(model.ApplicationApplied, model.ApplicationShortlisted),
+			).
+			SetStatus(model.ApplicationAccepted).
+			Save(ctx)
+		if err != nil {
+			return
+		}
+
+		if rowAffected != 1 {
+			err = errors.New(ErrApplicationStatusChanged)
+			return
+		}
+
+		return client.RecruitmentApplication.Update().
+			Where(
+				ra.RInsteadIDEQ(insteadId),
+				ra.TeacherIDNEQ(teacherId),
+				ra.StatusIn(model.ApplicationApplied, model.ApplicationShortlisted),
+			).
+			SetStatus(model.ApplicationRejected).
+			Exec(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (repo *recruitmentRepository) CancelAcceptedApplication(ctx context.Context, insteadId, teacherId int) (err error) {
+	return entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		client := tx.Client()
+
+		err = client.RecruitmentApplication.Update().
+			Where(
+				ra.RInsteadIDEQ(insteadId),
+				ra.TeacherIDEQ(teacherId),
+			).
+			SetStatus(model.ApplicationCancelled).
+			Exec(ctx)
+		if err != nil {
+			return
+		}
+
+		return client.RecruitmentInstead.Update().
+			Where(
+				ri.IDEQ(insteadId),
+				ri.TeacherIDEQ(teacherId),
+			).
+			ClearPasser().
+			Exec(ctx)
+	})
+}
+
+// 선생님이 합격자로 지정된 대강 중 insteadId 대강과 스케쥴이 겹치는 대강들 (겹치는 스케쥴만 함께 조회)
+func (repo *recruitmentRepository) OverlappedPasserInsteadList(ctx context.Context, teacherId, insteadId int) ([]*ent.RecruitmentInstead, error) {
+	return overlappedPasserInsteadList(ctx, repo.db, teacherId, insteadId)
+}
+
+// 합격 처리 트랜잭션 안에서도 사용한다.
+func overlappedPasserInsteadList(ctx context.Context, client *ent.Client, teacherId, insteadId int) ([]*ent.RecruitmentInstead, error) {
+	overlapped := scheduleOverlapsInstead(insteadId)
+
+	return client.RecruitmentInstead.Query().
+		Select(ri.FieldID, ri.FieldRecruitmentID).
+		WithSchedules(
+			func(risq *ent.RecruitmentInsteadScheduleQuery) {
+				risq.Where(overlapped).
+					Order(ent.Asc(ris.FieldStartDateTime))
+			},
+		).
+		Where(
+			ri.IDNEQ(insteadId),
+			ri.TeacherIDEQ(teacherId),
+			ri.HasRecuritmentWith(recruitment.DeletedAtIsNil()),
+			ri.HasSchedulesWith(overlapped),
+		).
+		All(ctx)
+}
+
+// 선생님이 합격한 대강들의 일정 (학원 정보 포함)
+func (repo *recruitmentRepository) PasserScheduleList(ctx context.Context, teacherId int) ([]*ent.RecruitmentInstead, error) {
+	return repo.db.RecruitmentInstead.Query().
+		WithSchedules(
+			func(risq *ent.RecruitmentInsteadScheduleQuery) {
+				risq.Order(ent.Asc(ris.FieldStartDateTime))
+			},
+		).
+		WithRecuritment(func(rq *ent.RecruitmentQuery) {
+			rq.WithWriter(func(aq *ent.AcademyQuery) {
+				aq.Select(academy.FieldID, academy.FieldName, academy.FieldAddressRoad, academy.FieldAddressDetail)
+			})
+		}).
+		Where(
+			ri.TeacherIDEQ(teacherId),
+			ri.HasRecuritmentWith(recruitment.DeletedAtIsNil()),
+		).
+		All(ctx)
+}
+
+// 학원이 작성한 채용공고들의 대강 일정 (합격자 정보 포함)
+func (repo *recruitmentRepository) WriterScheduleList(ctx context.Context, academyId int) ([]*ent.RecruitmentInstead, error) {
+	return repo.db.RecruitmentInstead.Query().
+		WithSchedules(
+			func(risq *ent.RecruitmentInsteadScheduleQuery) {
+				risq.Order(ent.Asc(ris.FieldStartDateTime))
+			},
+		).
+		WithRecuritment().
+		WithPasser(func(tq *ent.TeacherQuery) {
+			tq.Select(teacher.FieldID, teacher.FieldName)
+		}).
+		Where(
+			ri.HasRecuritmentWith(
+				recruitment.AcademyIDEQ(academyId),
+				recruitment.DeletedAtIsNil(),
+			),
+		).
+		All(ctx)
+}
+
+func (repo *recruitmentRepository) GetSchedule(ctx context.Context, insteadId, scheduleId int) (*ent.RecruitmentInsteadSchedule, error) {
+	return repo.db.RecruitmentInsteadSchedule.Query().
+		Where(
+			ris.IDEQ(scheduleId),
+			ris.RInsteadIDEQ(insteadId),
+		).
+		Only(ctx)
+}
+
+func (repo *recruitmentRepository) UpdateSchedule(ctx context.Context, scheduleId int, startDateTime, endDateTime transport.TimeString) (err error) {
+	return repo.db.RecruitmentInsteadSchedule.UpdateOneID(scheduleId).
+		SetStartDateTime(startDateTime).
+		SetEndDateTime(endDateTime).
+		Exec(ctx)
+}
+
+// 일정을 지우고, 반복 규칙이 있으면 제외 일정이 추가된 규칙으로 갱신한다.
+func (repo *recruitmentRepository) DeleteSchedule(ctx context.Context, insteadId, scheduleId int, recurrence *model.Recurrence) (err error) {
+	return entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		client := tx.Client()
+
+		_, err = client.RecruitmentInsteadSchedule.Delete().
+			Where(
+				ris.IDEQ(scheduleId),
+				ris.RInsteadIDEQ(insteadId),
+			).
+			Exec(ctx)
+		if err != nil {
+			return
+		}
+
+		if recurrence != nil {
+			err = client.RecruitmentInstead.UpdateOneID(insteadId).
+				SetRecurrence(recurrence).
+				Exec(ctx)
+		}
+		return
+	})
+}
+
+// 아직 마감되지 않은 공고 중, 일정이 하나 이상 있고 합격자가 없는 대강의 일정이 모두 now 이전에 끝난 공고를 마감한다.
+// 합격자가 정해진 대강은 더 모집하지 않으므로 판단에서 제외한다. (모든 대강의 합격자가 정해졌으면 마감)
+func (repo *recruitmentRepository) FinishExpired(ctx context.Context, now transport.TimeString) (count int, err error) {
+	return repo.db.Recruitment.Update().
+		Where(
+			recruitment.IsFinishEQ(false),
+			recruitment.DeletedAtIsNil(),
+			recruitment.HasRecruitmentInsteadWith(ri.HasSchedules()),
+			recruitment.Not(
+				recruitment.HasRecruitmentInsteadWith(
+					ri.TeacherIDIsNil(),
+					ri.HasSchedulesWith(ris.EndDateTimeGT(now)),
+				),
+			),
+		).
+		SetIsFinish(true).
+		Save(ctx)
+}
+
+// 시간으로 조회 // 요가로 조회 // 학원 위치로 조회 // 급여, 경력으로 조회 // 마감, 빈자리 여부로 조회
 func (repo *recruitmentRepository) conditionQuery(
 	clause *ent.RecruitmentQuery,
 	startDateTime, endDateTime *transport.TimeString,
-	yogaIds, sigunguId *[]int,
+	yogaIds, sigunguId, sidoIds *[]int,
+	minPay *int,
+	payUnit *model.PayUnit,
+	maxCareer *int,
+	isFinish, hasOpenSlot *bool,
 ) *ent.RecruitmentQuery {
 	if startDateTime != nil && endDateTime != nil {
 		clause.Where(
 			recruitment.HasRecruitmentInsteadWith(
-				func(s *sql.Selector) {
-					tableName := fmt.Sprintf(`%s, jsonb_array_elements(%s) c`, ri.Table, ri.FieldSchedule)
-					s.From(sql.Table(tableName))
-
-					p := &sql.Predicate{}
-					p.Append(func(b *sql.Builder) {
-						// startDateTime으로 조회
-						b.WriteString("c ->> '" + model.FieldStartDateTime + "'").
-							WriteOp(sql.OpGTE).Arg(startDateTime.ToString())
-
-						b.WriteString(" AND ")
-
-						// endDateTime으로 조회
-						b.WriteString("c ->>'" + model.FieldStartDateTime + "'").
-							WriteOp(sql.OpLTE).
-							Arg(endDateTime.ToString())
-					})
-					s.Where(p)
-				},
+				ri.HasSchedulesWith(scheduleOverlaps(*startDateTime, *endDateTime)),
 			),
 		)
 	}
@@ -329,9 +704,135 @@ func (repo *recruitmentRepository) conditionQuery(
 			),
 		)
 	}
+
+	if sidoIds != nil {
+		clause.Where(
+			recruitment.HasWriterWith(
+				academy.HasAreaSigunguWith(
+					areasigungu.AreaSidoIDIn(*sidoIds...),
+				),
+			),
+		)
+	}
+
+	if isFinish != nil {
+		clause.Where(recruitment.IsFinishEQ(*isFinish))
+	}
+
+	// 합격자가 정해지지 않은 대강이 있는지
+	if hasOpenSlot != nil {
+		openSlot := recruitment.HasRecruitmentInsteadWith(ri.TeacherIDIsNil())
+		if *hasOpenSlot {
+			clause.Where(openSlot)
+		} else {
+			clause.Where(recruitment.Not(openSlot))
+		}
+	}
+
+	// 급여, 경력 조건은 하나의 대강이 모두 만족해야 한다.
+	insteadConditions := make([]predicate.RecruitmentInstead, 0)
+	if minPay != nil {
+		insteadConditions = append(insteadConditions, ri.PayAmountGTE(*minPay))
+	}
+	if payUnit != nil {
+		insteadConditions = append(insteadConditions, ri.PayUnitEQ(*payUnit))
+	}
+	if maxCareer != nil {
+		insteadConditions = append(insteadConditions, ri.MinCareerMonthLTE(*maxCareer))
+	}
+	if len(insteadConditions) > 0 {
+		clause.Where(
+			recruitment.HasRecruitmentInsteadWith(insteadConditions...),
+		)
+	}
 	return clause
 }
 
+func orderByColumn(column string) func(orderType common.Sorts) ent.OrderFunc {
+	return func(orderType common.Sorts) ent.OrderFunc {
+		if orderType == common.ASC {
+			return ent.Asc(column)
+		}
+		return ent.Desc(column)
+	}
+}
+
+// 아직 시작하지 않은 가장 빠른 일정 순, 남은 일정이 없는 공고는 항상 뒤로
+// 일정은 Asia/Seoul 기준 벽시계 시간으로 저장되어 있다.
+func orderBySoonestStart(orderType common.Sorts) ent.OrderFunc {
+	return func(s *sql.Selector) {
+		s.OrderExpr(sql.ExprFunc(func(b *sql.Builder) {
+			b.WriteString("(SELECT MIN(").
+				Ident(ris.Table).WriteByte('.').Ident(ris.FieldStartDateTime).
+				WriteString(") FROM ").Ident(ris.Table).
+				WriteString(" JOIN ").Ident(ri.Table).
+				WriteString(" ON ").Ident(ri.Table).WriteByte('.').Ident(ri.FieldID).
+				WriteString(" = ").Ident(ris.Table).WriteByte('.').Ident(ris.FieldRInsteadID).
+				WriteString(" WHERE ").Ident(ri.Table).WriteByte('.').Ident(ri.FieldRecruitmentID).
+				WriteString(" = ").Ident(s.C(recruitment.FieldID)).
+				WriteString(" AND ").Ident(ris.Table).WriteByte('.').Ident(ris.FieldStartDateTime).
+				WriteString(" >= (NOW() AT TIME ZONE 'Asia/Seoul'))").
+				WriteString(sortDirection(orderType)).
+				WriteString(" NULLS LAST")
+		}))
+	}
+}
+
+// 대강 중 가장 높은 급여 순, 급여 단위가 같은 대강끼리만 비교한다.
+func orderByMaxPay(payUnit *model.PayUnit) func(orderType common.Sorts) ent.OrderFunc {
+	return func(orderType common.Sorts) ent.OrderFunc {
+		return func(s *sql.Selector) {
+			s.OrderExpr(sql.ExprFunc(func(b *sql.Builder) {
+				b.WriteString("(SELECT MAX(").
+					Ident(ri.Table).WriteByte('.').Ident(ri.FieldPayAmount).
+					WriteString(") FROM ").Ident(ri.Table).
+					WriteString(" WHERE ").Ident(ri.Table).WriteByte('.').Ident(ri.FieldRecruitmentID).
+					WriteString(" = ").Ident(s.C(recruitment.FieldID))
+				if payUnit != nil {
+					b.WriteString(" AND ").Ident(ri.Table).WriteByte('.').Ident(ri.FieldPayUnit).
+						WriteString(" = " + strconv.Itoa(int(*payUnit)))
+				}
+				b.WriteString(")").
+					WriteString(sortDirection(orderType)).
+					WriteString(" NULLS LAST")
+			}))
+		}
+	}
+}
+
+func sortDirection(orderType common.Sorts) string {
+	if orderType == common.ASC {
+		return " ASC"
+	}
+	return " DESC"
+}
+
+// 스케쥴이 [start, end) 구간과 겹치는지
+// (시작 < end AND 끝 > start) 로 비교해 (start_date_time, end_date_time) 인덱스를 사용한다.
+func scheduleOverlaps(start, end transport.TimeString) predicate.RecruitmentInsteadSchedule {
+	return ris.And(
+		ris.StartDateTimeLT(end),
+		ris.EndDateTimeGT(start),
+	)
+}
+
+// 스케쥴이 insteadId 대강의 스케쥴 중 하나와 겹치는지
+func scheduleOverlapsInstead(insteadId int) predicate.RecruitmentInsteadSchedule {
+	return func(s *sql.Selector) {
+		t := sql.Table(ris.Table).As("target")
+
+		s.Where(sql.Exists(
+			sql.Select(t.C(ris.FieldID)).
+				From(t).
+				Where(sql.And(
+					sql.EQ(t.C(ris.FieldRInsteadID), insteadId),
+					sql.ColumnsLT(s.C(ris.FieldStartDateTime), t.C(ris.FieldEndDateTime)),
+					sql.ColumnsGT(s.C(ris.FieldEndDateTime), t.C(ris.FieldStartDateTime)),
+				)),
+		))
+	}
+}
+
 func (repo *recruitmentRepository) extractIdsFromInsteadrepo(vals []*ent.RecruitmentInstead) []int {
 	var result []int
 	for _, s := range vals {
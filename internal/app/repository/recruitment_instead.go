@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 
-	"onthemat/internal/app/model"
 	"onthemat/pkg/ent"
 	ri "onthemat/pkg/ent/recruitmentinstead"
+	ris "onthemat/pkg/ent/recruitmentinsteadschedule"
 )
 
 type recruitmentInsteadRepo struct{}
@@ -14,20 +14,16 @@ type recruitmentInsteadRepo struct{}
 func (repo *recruitmentInsteadRepo) createMany(ctx context.Context, db *ent.Client, vals []*ent.RecruitmentInstead, recruitmentId int) (err error) {
 	bulk := make([]*ent.RecruitmentInsteadCreate, len(vals))
 	for i, v := range vals {
-
-		schedules := make([]*model.Schedule, 0)
-		for _, s := range v.Schedule {
-			schedules = append(schedules, &model.Schedule{
-				StartDateTime: s.StartDateTime,
-				EndDateTime:   s.EndDateTime,
-			})
-		}
-
 		clause := db.RecruitmentInstead.Create().
 			SetRecuritmentID(recruitmentId).
-			SetMinCareer(v.MinCareer).
-			SetPay(v.Pay).
-			SetSchedule(schedules)
+			SetMinCareerMonth(v.MinCareerMonth).
+			SetPayAmount(v.PayAmount).
+			SetPayCurrency(v.PayCurrency).
+			SetPayUnit(v.PayUnit)
+
+		if v.Recurrence != nil {
+			clause.SetRecurrence(v.Recurrence)
+		}
 
 		if v.ID != 0 {
 			clause.SetID(v.ID)
@@ -36,16 +32,48 @@ func (repo *recruitmentInsteadRepo) createMany(ctx context.Context, db *ent.Clie
 		bulk[i] = clause
 	}
 
-	err = db.RecruitmentInstead.CreateBulk(bulk...).Exec(ctx)
+	created, err := db.RecruitmentInstead.CreateBulk(bulk...).Save(ctx)
 	if err != nil {
 		if err.Error() == "incosistent id values for batch insert" {
 			err = errors.New(ErrOnlyOwnUser)
 		}
 		return
 	}
+
+	for i, v := range created {
+		err = repo.createSchedules(ctx, db, vals[i].Edges.Schedules, v.ID)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
+func (repo *recruitmentInsteadRepo) createSchedules(ctx context.Context, db *ent.Client, vals []*ent.RecruitmentInsteadSchedule, insteadId int) error {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	bulk := make([]*ent.RecruitmentInsteadScheduleCreate, len(vals))
+	for i, v := range vals {
+		bulk[i] = db.RecruitmentInsteadSchedule.Create().
+			SetRInsteadID(insteadId).
+			SetStartDateTime(v.StartDateTime).
+			SetEndDateTime(v.EndDateTime).
+			SetNillableOriginalStartDateTime(v.OriginalStartDateTime)
+	}
+	return db.RecruitmentInsteadSchedule.CreateBulk(bulk...).Exec(ctx)
+}
+
+// 스케쥴은 수정하지 않고 모두 지운 뒤 다시 생성한다. (반복 규칙이 있으면 시리즈 전체 수정)
+func (repo *recruitmentInsteadRepo) replaceSchedules(ctx context.Context, db *ent.Client, vals []*ent.RecruitmentInsteadSchedule, insteadId int) error {
+	_, err := db.RecruitmentInsteadSchedule.Delete().Where(ris.RInsteadIDEQ(insteadId)).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return repo.createSchedules(ctx, db, vals, insteadId)
+}
+
 func (repo *recruitmentInsteadRepo) getIdsByRecruitId(ctx context.Context, db *ent.Client, recruitId int) ([]int, error) {
 	return db.RecruitmentInstead.Query().
 		Where(ri.RecruitmentIDEQ(recruitId)).IDs(ctx)
@@ -53,25 +81,24 @@ func (repo *recruitmentInsteadRepo) getIdsByRecruitId(ctx context.Context, db *e
 
 func (repo *recruitmentInsteadRepo) updateMany(ctx context.Context, db *ent.Client, vals []*ent.RecruitmentInstead, recruitId int) (err error) {
 	for _, v := range vals {
-		schedules := make([]*model.Schedule, 0)
-		for _, s := range v.Schedule {
-			schedules = append(schedules, &model.Schedule{
-				StartDateTime: s.StartDateTime,
-				EndDateTime:   s.EndDateTime,
-			})
-		}
-
-		rowAffcted, err := db.RecruitmentInstead.Update().
+		clause := db.RecruitmentInstead.Update().
 			Where(
 				ri.IDEQ(v.ID),
 				ri.RecruitmentIDEQ(recruitId),
 			).
 			SetRecuritmentID(recruitId).
-			SetMinCareer(v.MinCareer).
-			SetPay(v.Pay).
-			SetSchedule(schedules).
-			SetNillablePasserID(v.TeacherID).
-			Save(ctx)
+			SetMinCareerMonth(v.MinCareerMonth).
+			SetPayAmount(v.PayAmount).
+			SetPayCurrency(v.PayCurrency).
+			SetPayUnit(v.PayUnit)
+
+		if v.Recurrence != nil {
+			clause.SetRecurrence(v.Recurrence)
+		} else {
+			clause.ClearRecurrence()
+		}
+
+		rowAffcted, err := clause.Save(ctx)
 
 		if rowAffcted < 1 {
 			err = errors.New(ErrOnlyOwnUser)
@@ -80,6 +107,10 @@ func (repo *recruitmentInsteadRepo) updateMany(ctx context.Context, db *ent.Clie
 		if err != nil {
 			return err
 		}
+
+		if err = repo.replaceSchedules(ctx, db, v.Edges.Schedules, v.ID); err != nil {
+			return err
+		}
 	}
 	return
 }
@@ -6,11 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"onthemat/internal/app/common"
 	"onthemat/internal/app/config"
 	"onthemat/internal/app/infrastructure"
+	"onthemat/internal/app/model"
 	"onthemat/internal/app/transport"
 	"onthemat/internal/app/utils"
 	"onthemat/pkg/ent"
+	ri "onthemat/pkg/ent/recruitmentinstead"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -58,6 +61,173 @@ func (ts *RecruitmentTestSuite) TearDownTest() {
 }
 
 func (ts *RecruitmentTestSuite) BeforeTest(suiteName, testName string) {
+	if suiteName == "RecruitmentTestSuite" {
+		switch testName {
+		case "TestFinishExpired", "TestUpdateDeletesAppliedInstead", "TestAcceptApplication":
+			u, _ := ts.userRepo.Create(ts.ctx, &ent.User{})
+			ts.userNo = u.ID
+			err := ts.areaRepo.Create(ts.ctx, &ent.AreaSiDo{
+				Name:    "서울특별자치시",
+				AdmCode: "11",
+				Version: 1,
+			}, []*ent.AreaSiGungu{{Name: "종로구", AdmCode: "11010"}})
+			ts.NoError(err)
+			ts.client.Academy.Create().
+				SetUserID(u.ID).
+				SetSigunguID(1).
+				SetName("하타요가원").
+				SetBusinessCode("1234").
+				SetCallNumber("010122222222").
+				SetAddressRoad("전체주소").
+				ExecX(ts.ctx)
+			ts.client.Teacher.Create().
+				SetUserID(u.ID).
+				SetName("선생님").
+				ExecX(ts.ctx)
+		}
+	}
+}
+
+func (ts *RecruitmentTestSuite) TestFinishExpired() {
+	now := transport.TimeString(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	past := transport.TimeString(time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC))
+	future := transport.TimeString(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC))
+
+	// filled 는 대강별 합격자 여부
+	createRecruitment := func(schedules [][]*ent.RecruitmentInsteadSchedule, filled ...bool) int {
+		r := ts.client.Recruitment.Create().SetWriterID(1).SaveX(ts.ctx)
+		for i, v := range schedules {
+			c := ts.client.RecruitmentInstead.Create().
+				SetRecuritmentID(r.ID).
+				SetPayAmount(50000)
+			if i < len(filled) && filled[i] {
+				c.SetPasserID(1)
+			}
+			instead := c.SaveX(ts.ctx)
+			for _, s := range v {
+				ts.client.RecruitmentInsteadSchedule.Create().
+					SetRInsteadID(instead.ID).
+					SetStartDateTime(s.StartDateTime).
+					SetEndDateTime(s.EndDateTime).
+					ExecX(ts.ctx)
+			}
+		}
+		return r.ID
+	}
+	isFinish := func(id int) bool {
+		return ts.client.Recruitment.GetX(ts.ctx, id).IsFinish
+	}
+
+	ended := createRecruitment([][]*ent.RecruitmentInsteadSchedule{{{StartDateTime: past, EndDateTime: past}}})
+	noSchedule := createRecruitment([][]*ent.RecruitmentInsteadSchedule{{}})
+	filledFuture := createRecruitment([][]*ent.RecruitmentInsteadSchedule{{{StartDateTime: future, EndDateTime: future}}}, true)
+	openEndedFilledFuture := createRecruitment([][]*ent.RecruitmentInsteadSchedule{
+		{{StartDateTime: past, EndDateTime: past}},
+		{{StartDateTime: future, EndDateTime: future}},
+	}, false, true)
+	partlyEnded := createRecruitment([][]*ent.RecruitmentInsteadSchedule{
+		{{StartDateTime: past, EndDateTime: past}},
+		{{StartDateTime: future, EndDateTime: future}},
+	})
+
+	count, err := ts.recruitRepo.FinishExpired(ts.ctx, now)
+	ts.NoError(err)
+	ts.Equal(3, count)
+
+	ts.Run("모든 일정이 끝난 공고는 마감", func() {
+		ts.True(isFinish(ended))
+	})
+
+	ts.Run("일정이 없는 공고는 마감하지 않음", func() {
+		ts.False(isFinish(noSchedule))
+	})
+
+	ts.Run("모든 대강의 합격자가 정해지면 마감", func() {
+		ts.True(isFinish(filledFuture))
+	})
+
+	ts.Run("합격자가 정해진 대강의 남은 일정은 판단에서 제외", func() {
+		ts.True(isFinish(openEndedFilledFuture))
+	})
+
+	ts.Run("합격자가 없는 대강에 남은 일정이 있으면 마감하지 않음", func() {
+		ts.False(isFinish(partlyEnded))
+	})
+}
+
+func (ts *RecruitmentTestSuite) TestUpdateDeletesAppliedInstead() {
+	r := ts.client.Recruitment.Create().SetWriterID(1).SaveX(ts.ctx)
+	kept := ts.client.RecruitmentInstead.Create().SetRecuritmentID(r.ID).SetPayAmount(50000).SaveX(ts.ctx)
+	dropped := ts.client.RecruitmentInstead.Create().SetRecuritmentID(r.ID).SetPayAmount(50000).SaveX(ts.ctx)
+	ts.NoError(ts.recruitRepo.CreateApplication(ts.ctx, kept.ID, 1))
+	ts.NoError(ts.recruitRepo.CreateApplication(ts.ctx, dropped.ID, 1))
+
+	ts.Run("지원서가 있는 자리를 빼고 수정", func() {
+		err := ts.recruitRepo.Update(ts.ctx, &ent.Recruitment{
+			ID:        r.ID,
+			AcademyID: 1,
+			IsOpen:    true,
+			Edges: ent.RecruitmentEdges{
+				RecruitmentInstead: []*ent.RecruitmentInstead{kept},
+			},
+		})
+		ts.NoError(err)
+		ts.False(ts.client.RecruitmentInstead.Query().Where(ri.IDEQ(dropped.ID)).ExistX(ts.ctx))
+		_, err = ts.recruitRepo.GetApplication(ts.ctx, dropped.ID, 1)
+		ts.True(ent.IsNotFound(err))
+		_, err = ts.recruitRepo.GetApplication(ts.ctx, kept.ID, 1)
+		ts.NoError(err)
+	})
+
+	ts.Run("모든 자리를 삭제", func() {
+		err := ts.recruitRepo.Update(ts.ctx, &ent.Recruitment{ID: r.ID, AcademyID: 1, IsOpen: true})
+		ts.NoError(err)
+		_, err = ts.recruitRepo.GetApplication(ts.ctx, kept.ID, 1)
+		ts.True(ent.IsNotFound(err))
+	})
+}
+
+func (ts *RecruitmentTestSuite) TestAcceptApplication() {
+	start := transport.TimeString(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC))
+	end := transport.TimeString(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	createInstead := func() int {
+		r := ts.client.Recruitment.Create().SetWriterID(1).SaveX(ts.ctx)
+		instead := ts.client.RecruitmentInstead.Create().SetRecuritmentID(r.ID).SetPayAmount(50000).SaveX(ts.ctx)
+		ts.client.RecruitmentInsteadSchedule.Create().
+			SetRInsteadID(instead.ID).
+			SetStartDateTime(start).
+			SetEndDateTime(end).
+			ExecX(ts.ctx)
+		ts.NoError(ts.recruitRepo.CreateApplication(ts.ctx, instead.ID, 1))
+		return instead.ID
+	}
+	first := createInstead()
+	overlapped := createInstead()
+	withdrawn := createInstead()
+
+	ts.Run("합격", func() {
+		conflicts, err := ts.recruitRepo.AcceptApplication(ts.ctx, first, 1)
+		ts.NoError(err)
+		ts.Len(conflicts, 0)
+		ts.Equal(1, *ts.client.RecruitmentInstead.GetX(ts.ctx, first).TeacherID)
+	})
+
+	ts.Run("이미 합격한 대강과 일정이 겹치면 합격시키지 않음", func() {
+		conflicts, err := ts.recruitRepo.AcceptApplication(ts.ctx, overlapped, 1)
+		ts.NoError(err)
+		ts.Len(conflicts, 1)
+		ts.Equal(first, conflicts[0].ID)
+		ts.Nil(ts.client.RecruitmentInstead.GetX(ts.ctx, overlapped).TeacherID)
+	})
+
+	ts.Run("지원을 취소한 지원서는 합격시키지 않음", func() {
+		ts.NoError(ts.recruitRepo.CancelAcceptedApplication(ts.ctx, first, 1))
+		ts.NoError(ts.recruitRepo.UpdateApplicationStatus(ts.ctx, withdrawn, 1, model.ApplicationWithdrawn))
+
+		_, err := ts.recruitRepo.AcceptApplication(ts.ctx, withdrawn, 1)
+		ts.EqualError(err, ErrApplicationStatusChanged)
+		ts.Nil(ts.client.RecruitmentInstead.GetX(ts.ctx, withdrawn).TeacherID)
+	})
 }
 
 // raw query Test
@@ -77,7 +247,7 @@ func TestRecruitmentList(t *testing.T) {
 	endTime, _ := time.Parse("2006-01-02T15:04:05", "2022-12-03T00:00:00")
 	f := transport.TimeString(startTime)
 	e := transport.TimeString(endTime)
-	l, _ := repo.List(ctx, module, &f, &e, nil, nil)
+	l, _ := repo.List(ctx, module, &f, &e, nil, nil, nil, nil, nil, nil, nil, nil, nil, common.DESC)
 	fmt.Println(l)
 }
 
@@ -95,6 +265,10 @@ func TestRecruitmentListByYoga(t *testing.T) {
 	module := utils.NewPagination(1, 10)
 
 	// sigunguIds := []int{1}
-	l, _ := repo.List(ctx, module, nil, nil, nil, nil)
+	l, _ := repo.List(ctx, module, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, common.DESC)
 	fmt.Println(l)
 }
+
+func TestRecruitmentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecruitmentTestSuite))
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"onthemat/internal/app/common"
 	"onthemat/internal/app/model"
 	"onthemat/internal/app/transport/request"
 	"onthemat/internal/app/utils"
@@ -31,6 +32,11 @@ type TeacherRepository interface {
 	Patch(ctx context.Context, d *request.TeacherPatchBody, id, userId int) (isCreated bool, err error)
 	Get(ctx context.Context, id int) (res *ent.Teacher, err error)
 	GetOnlyIdByUserId(ctx context.Context, userId int) (id int, err error)
+	List(ctx context.Context,
+		pgModule *utils.Pagination,
+		yogaIDs, sigunguIDs *[]int, minCareerYear *int, agencyName *string,
+		orderCol *string, orderType common.Sorts) (result []*ent.Teacher, err error)
+	Total(ctx context.Context, yogaIDs, sigunguIDs *[]int, minCareerYear *int, agencyName *string) (result int, err error)
 }
 
 type teacherRepository struct {
@@ -447,6 +453,110 @@ func (repo *teacherRepository) Exist(ctx context.Context, id int) (bool, error)
 	return repo.db.Teacher.Query().Where(teacher.IDEQ(id)).Exist(ctx)
 }
 
+func (repo *teacherRepository) Total(ctx context.Context, yogaIDs, sigunguIDs *[]int, minCareerYear *int, agencyName *string) (result int, err error) {
+	clause := repo.db.Teacher.Query()
+
+	clause = repo.conditionQuery(clause, yogaIDs, sigunguIDs, minCareerYear, agencyName)
+	result, err = clause.Count(ctx)
+	return
+}
+
+func (repo *teacherRepository) List(ctx context.Context,
+	pgModule *utils.Pagination,
+	yogaIDs, sigunguIDs *[]int, minCareerYear *int, agencyName *string,
+	orderCol *string, orderType common.Sorts,
+) (result []*ent.Teacher, err error) {
+	clause := repo.db.Teacher.Query().
+		Select(
+			teacher.FieldID,
+			teacher.FieldName,
+			teacher.FieldAge,
+			teacher.FieldProfileImageUrl,
+			teacher.FieldIntroduce,
+			teacher.FieldCreatedAt,
+			teacher.FieldUpdatedAt,
+		).
+		WithSigungu(
+			func(asgq *ent.AreaSiGunguQuery) {
+				asgq.Select(areasigungu.FieldID, areasigungu.FieldName)
+			},
+		).
+		WithYoga(
+			func(yq *ent.YogaQuery) {
+				yq.Select(yoga.FieldID, yoga.FieldNameKor)
+			},
+		).
+		Limit(pgModule.GetLimit()).
+		Offset(pgModule.GetOffset())
+
+	useableOrderCol := map[string]string{
+		"NAME": teacher.FieldName,
+		"ID":   teacher.FieldID,
+	}
+
+	useableOrderFunc := map[common.Sorts]func(v ...string) ent.OrderFunc{
+		common.DESC: ent.Desc,
+		common.ASC:  ent.Asc,
+	}
+
+	if orderCol != nil {
+		clause.Order(useableOrderFunc[orderType](useableOrderCol[*orderCol]))
+	} else {
+		clause.Order(useableOrderFunc[orderType](teacher.FieldID))
+	}
+
+	clause = repo.conditionQuery(clause, yogaIDs, sigunguIDs, minCareerYear, agencyName)
+
+	result, err = clause.All(ctx)
+	return
+}
+
+// 공개된 프로필만 // 요가로 조회 // 근무 가능 지역으로 조회 // 경력으로 조회 // 자격증 기관으로 조회
+func (repo *teacherRepository) conditionQuery(
+	clause *ent.TeacherQuery,
+	yogaIDs, sigunguIDs *[]int,
+	minCareerYear *int,
+	agencyName *string,
+) *ent.TeacherQuery {
+	clause.Where(teacher.IsProfileOpenEQ(true))
+
+	if yogaIDs != nil {
+		clause.Where(teacher.HasYogaWith(yoga.IDIn(*yogaIDs...)))
+	}
+
+	if sigunguIDs != nil {
+		clause.Where(teacher.HasSigunguWith(areasigungu.IDIn(*sigunguIDs...)))
+	}
+
+	if agencyName != nil {
+		clause.Where(teacher.HasCertificationWith(tcf.AgencyNameContains(*agencyName)))
+	}
+
+	if minCareerYear != nil && *minCareerYear > 0 {
+		// 근무 경험 기간의 합 (종료일이 없으면 현재까지 근무중)
+		minCareerSeconds := *minCareerYear * 365 * 24 * 60 * 60
+
+		clause.Where(func(s *sql.Selector) {
+			t := sql.Table(twe.Table)
+			sub := sql.Select(t.C(twe.FieldTeacherID)).
+				From(t).
+				GroupBy(t.C(twe.FieldTeacherID)).
+				Having(sql.P(func(b *sql.Builder) {
+					b.WriteString("SUM(EXTRACT(EPOCH FROM (COALESCE(").
+						Ident(twe.FieldWorkEndAt).
+						WriteString(", NOW()) - ").
+						Ident(twe.FieldWorkStartAt).
+						WriteString(")))").
+						WriteOp(sql.OpGTE).
+						Arg(minCareerSeconds)
+				}))
+			s.Where(sql.In(s.C(teacher.FieldID), sub))
+		})
+	}
+
+	return clause
+}
+
 func extractIdsFromWorkExp(val []*ent.TeacherWorkExperience) []int {
 	var result []int
 	for _, s := range val {
@@ -2,27 +2,50 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"onthemat/pkg/ent"
+	"onthemat/pkg/ent/predicate"
 	"onthemat/pkg/ent/user"
+	"onthemat/pkg/ent/useridentity"
+	"onthemat/pkg/entx"
 )
 
+const withdrawnNickname = "탈퇴한 회원"
+
 type UserRepository interface {
 	Create(ctx context.Context, user *ent.User) (*ent.User, error)
+	// 소셜 로그인으로 가입할 때 유저와 소셜 계정을 함께 생성한다.
+	CreateWithIdentity(ctx context.Context, user *ent.User, identity *ent.UserIdentity) (*ent.User, error)
+	// 이메일로 가입할 때 유저와 보낼 인증 메일을 같은 트랜잭션으로 생성한다.
+	CreateWithEmail(ctx context.Context, user *ent.User, mail *ent.EmailOutbox) (*ent.User, error)
 	Update(ctx context.Context, user *ent.User) (*ent.User, error)
 	UpdateLogoUrl(ctx context.Context, u *ent.User) error
-	// UpdatePassword()
+	UpdatePassword(ctx context.Context, userId int, password string) error
+	// 인증을 마친 휴대폰 번호로 바꾼다.
+	UpdatePhoneVerified(ctx context.Context, userId int, phoneNum string) error
 
 	UpdateEmail(ctx context.Context, email string, userId int) error
-	UpdateTempPassword(ctx context.Context, u *ent.User) error
 	UpdateEmailVerifeid(ctx context.Context, userId int) error
-	UpdateSocialKey(ctx context.Context, u *ent.User) (err error)
-	GetBySocialKey(ctx context.Context, u *ent.User) (*ent.User, error)
 	GetByEmail(ctx context.Context, email string) (*ent.User, error)
-	GetByEmailPassword(ctx context.Context, u *ent.User) (*ent.User, error)
 	FindByEmail(ctx context.Context, email string) (bool, error)
 	Get(ctx context.Context, id int) (*ent.User, error)
+	// 연결된 소셜 계정(Edges.Identities)을 함께 조회한다.
+	GetWithIdentities(ctx context.Context, id int) (*ent.User, error)
+	UpdateCalendarToken(ctx context.Context, userId int, tokenHash string) error
+	// 2단계 인증을 켠다. 이미 켜져 있으면 NotFound 에러를 반환한다.
+	EnableTotp(ctx context.Context, userId int, secret string, recoveryCodes []string, enabledAt time.Time) error
+	DisableTotp(ctx context.Context, userId int) error
+	// 복구 코드(해시)가 남아있으면 지우고 true 를 반환한다. 같은 코드는 한 번만 사용할 수 있다.
+	UseTotpRecoveryCode(ctx context.Context, userId int, codeHash string) (bool, error)
+	GetByCalendarToken(ctx context.Context, tokenHash string) (*ent.User, error)
+
+	// 탈퇴 처리. 이미 탈퇴한 유저면 NotFound 에러를 반환한다.
+	Withdraw(ctx context.Context, userId int, deletedAt time.Time) error
+	// 익명화되기 전의 탈퇴 유저만 복구한다. 대상이 없으면 NotFound 에러를 반환한다.
+	Restore(ctx context.Context, userId int) error
+	// before 이전에 탈퇴한 유저의 개인정보를 지우고 익명화한 유저 수를 반환한다.
+	AnonymizeWithdrawn(ctx context.Context, before time.Time, now time.Time) (int, error)
 }
 
 type userRepository struct {
@@ -39,13 +62,20 @@ func (repo *userRepository) Get(ctx context.Context, id int) (*ent.User, error)
 	return repo.db.User.Query().Where(user.IDEQ(id)).Only(ctx)
 }
 
+func (repo *userRepository) GetWithIdentities(ctx context.Context, id int) (*ent.User, error) {
+	return repo.db.User.Query().
+		Where(user.IDEQ(id)).
+		WithIdentities(func(q *ent.UserIdentityQuery) {
+			q.Order(ent.Asc(useridentity.FieldID))
+		}).
+		Only(ctx)
+}
+
 func (repo *userRepository) Create(ctx context.Context, user *ent.User) (result *ent.User, err error) {
 	result, err = repo.db.User.Create().
 		SetNillableNickname(user.Nickname).
 		SetNillableEmail(user.Email).
 		SetNillablePassword(user.Password).
-		SetNillableSocialKey(user.SocialKey).
-		SetNillableSocialName(user.SocialName).
 		SetTermAgreeAt(user.TermAgreeAt).
 		SetNillablePhoneNum(user.PhoneNum).
 		Save(ctx)
@@ -55,10 +85,51 @@ func (repo *userRepository) Create(ctx context.Context, user *ent.User) (result
 	return
 }
 
+func (repo *userRepository) CreateWithIdentity(ctx context.Context, u *ent.User, identity *ent.UserIdentity) (result *ent.User, err error) {
+	err = entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		result, err = tx.User.Create().
+			SetNillableNickname(u.Nickname).
+			SetNillableEmail(u.Email).
+			SetTermAgreeAt(u.TermAgreeAt).
+			Save(ctx)
+		if err != nil {
+			return
+		}
+
+		_, err = tx.UserIdentity.Create().
+			SetUserID(result.ID).
+			SetProvider(identity.Provider).
+			SetProviderKey(identity.ProviderKey).
+			SetNillableEmail(identity.Email).
+			Save(ctx)
+		return
+	})
+	return
+}
+
+func (repo *userRepository) CreateWithEmail(ctx context.Context, u *ent.User, mail *ent.EmailOutbox) (result *ent.User, err error) {
+	err = entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		result, err = tx.User.Create().
+			SetNillableNickname(u.Nickname).
+			SetNillableEmail(u.Email).
+			SetNillablePassword(u.Password).
+			SetTermAgreeAt(u.TermAgreeAt).
+			Save(ctx)
+		if err != nil {
+			return
+		}
+
+		_, err = createEmailOutbox(ctx, tx.EmailOutbox, mail)
+		return
+	})
+	return
+}
+
 func (repo *userRepository) Update(ctx context.Context, user *ent.User) (result *ent.User, err error) {
 	result, err = repo.db.User.UpdateOneID(user.ID).
 		SetNillableNickname(user.Nickname).
 		SetNillablePhoneNum(user.PhoneNum).
+		SetIsPhoneVerified(user.IsPhoneVerified).
 		Save(ctx)
 	if err != nil {
 		return
@@ -66,25 +137,11 @@ func (repo *userRepository) Update(ctx context.Context, user *ent.User) (result
 	return
 }
 
-func (repo *userRepository) UpdateSocialKey(ctx context.Context, u *ent.User) (err error) {
-	return repo.db.User.UpdateOneID(u.ID).
-		SetSocialKey(*u.SocialKey).
-		SetSocialName(*u.SocialName).
-		Exec(ctx)
-}
-
 func (repo *userRepository) UpdateEmail(ctx context.Context, email string, userId int) error {
 	return repo.db.User.UpdateOneID(userId).
 		SetEmail(email).Exec(ctx)
 }
 
-func (repo *userRepository) UpdateTempPassword(ctx context.Context, u *ent.User) error {
-	return repo.db.User.Update().
-		SetNillableTempPassword(u.TempPassword).Where(
-		user.EmailEQ(*u.Email),
-	).Exec(ctx)
-}
-
 func (repo *userRepository) UpdateLogoUrl(ctx context.Context, u *ent.User) error {
 	return repo.db.User.Update().
 		SetLogoUrl(*u.LogoUrl).
@@ -98,16 +155,6 @@ func (repo *userRepository) UpdateEmailVerifeid(ctx context.Context, userId int)
 		SetIsEmailVerified(true).Exec(ctx)
 }
 
-func (repo *userRepository) GetBySocialKey(ctx context.Context, u *ent.User) (*ent.User, error) {
-	return repo.db.User.
-		Query().
-		Where(
-			user.SocialNameEQ(*u.SocialName),
-			user.SocialKeyEQ(*u.SocialKey),
-		).
-		Only(ctx)
-}
-
 func (repo *userRepository) GetByEmail(ctx context.Context, email string) (*ent.User, error) {
 	return repo.db.User.
 		Query().
@@ -115,29 +162,155 @@ func (repo *userRepository) GetByEmail(ctx context.Context, email string) (*ent.
 			user.EmailEQ(email)).Only(ctx)
 }
 
-func (repo *userRepository) GetByEmailPassword(ctx context.Context, u *ent.User) (*ent.User, error) {
-	if u.Password == nil || u.Email == nil {
-		return nil, errors.New("포인터 에러")
+func (repo *userRepository) UpdatePassword(ctx context.Context, userId int, password string) error {
+	return repo.db.User.UpdateOneID(userId).
+		SetPassword(password).Exec(ctx)
+}
+
+func (repo *userRepository) UpdatePhoneVerified(ctx context.Context, userId int, phoneNum string) error {
+	return repo.db.User.UpdateOneID(userId).
+		SetPhoneNum(phoneNum).
+		SetIsPhoneVerified(true).
+		Exec(ctx)
+}
+
+func (repo *userRepository) FindByEmail(ctx context.Context, email string) (bool, error) {
+	return repo.db.User.Query().Where(
+		user.EmailEQ(email),
+	).Exist(ctx)
+}
+
+func (repo *userRepository) UpdateCalendarToken(ctx context.Context, userId int, tokenHash string) error {
+	return repo.db.User.UpdateOneID(userId).
+		SetCalendarToken(tokenHash).Exec(ctx)
+}
+
+func (repo *userRepository) EnableTotp(ctx context.Context, userId int, secret string, recoveryCodes []string, enabledAt time.Time) error {
+	count, err := repo.db.User.Update().
+		SetTotpSecret(secret).
+		SetTotpRecoveryCodes(recoveryCodes).
+		SetTotpEnabledAt(enabledAt).
+		Where(
+			user.IDEQ(userId),
+			user.TotpEnabledAtIsNil(),
+		).Save(ctx)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return &ent.NotFoundError{}
 	}
+	return nil
+}
 
+func (repo *userRepository) DisableTotp(ctx context.Context, userId int) error {
+	return repo.db.User.UpdateOneID(userId).
+		ClearTotpSecret().
+		ClearTotpRecoveryCodes().
+		ClearTotpEnabledAt().
+		Exec(ctx)
+}
+
+func (repo *userRepository) UseTotpRecoveryCode(ctx context.Context, userId int, codeHash string) (used bool, err error) {
+	err = entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		// 유저 행을 먼저 갱신해 락을 잡는다. 같은 코드로 동시에 두 번 로그인하는 것을 막는다.
+		u, err := tx.User.UpdateOneID(userId).
+			Save(ctx)
+		if err != nil {
+			return
+		}
+
+		remaining := make([]string, 0, len(u.TotpRecoveryCodes))
+		for _, v := range u.TotpRecoveryCodes {
+			if v == codeHash && !used {
+				used = true
+				continue
+			}
+			remaining = append(remaining, v)
+		}
+		if !used {
+			return
+		}
+
+		return tx.User.UpdateOneID(userId).
+			SetTotpRecoveryCodes(remaining).
+			Exec(ctx)
+	})
+	return
+}
+
+func (repo *userRepository) GetByCalendarToken(ctx context.Context, tokenHash string) (*ent.User, error) {
 	return repo.db.User.
 		Query().
 		Where(
-			user.
-				Or(
-					user.PasswordEQ(*u.Password),
-					user.
-						And(
-							user.TempPasswordNotNil(),
-							user.TempPasswordEQ(*u.Password),
-						),
-				),
-			user.EmailEQ(*u.Email),
+			user.CalendarTokenEQ(tokenHash),
+			user.DeletedAtIsNil(),
 		).Only(ctx)
 }
 
-func (repo *userRepository) FindByEmail(ctx context.Context, email string) (bool, error) {
-	return repo.db.User.Query().Where(
-		user.EmailEQ(email),
-	).Exist(ctx)
+func (repo *userRepository) Withdraw(ctx context.Context, userId int, deletedAt time.Time) error {
+	count, err := repo.db.User.Update().
+		SetDeletedAt(deletedAt).
+		Where(
+			user.IDEQ(userId),
+			user.DeletedAtIsNil(),
+		).Save(ctx)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return &ent.NotFoundError{}
+	}
+	return nil
+}
+
+func (repo *userRepository) Restore(ctx context.Context, userId int) error {
+	count, err := repo.db.User.Update().
+		ClearDeletedAt().
+		Where(
+			user.IDEQ(userId),
+			user.DeletedAtNotNil(),
+			user.AnonymizedAtIsNil(),
+		).Save(ctx)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return &ent.NotFoundError{}
+	}
+	return nil
+}
+
+// 유니크 컬럼(이메일)은 NULL 로 지우고 소셜 계정 연결은 삭제해서 같은 이메일, 소셜 계정으로 다시 가입할 수 있게 한다.
+// 닉네임은 지난 공고, 지원 이력에 표시되므로 비우지 않고 대체 문구로 바꾼다.
+func (repo *userRepository) AnonymizeWithdrawn(ctx context.Context, before time.Time, now time.Time) (count int, err error) {
+	err = entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		withdrawn := []predicate.User{
+			user.DeletedAtLT(before),
+			user.AnonymizedAtIsNil(),
+		}
+
+		if _, err = tx.UserIdentity.Delete().
+			Where(useridentity.HasUserWith(withdrawn...)).
+			Exec(ctx); err != nil {
+			return
+		}
+
+		count, err = tx.User.Update().
+			ClearEmail().
+			ClearPassword().
+			ClearPhoneNum().
+			SetIsPhoneVerified(false).
+			ClearLogoUrl().
+			ClearCalendarToken().
+			ClearTotpSecret().
+			ClearTotpRecoveryCodes().
+			ClearTotpEnabledAt().
+			SetNickname(withdrawnNickname).
+			SetAnonymizedAt(now).
+			Where(withdrawn...).
+			Save(ctx)
+		return
+	})
+	return
 }
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"onthemat/internal/app/model"
+	"onthemat/pkg/ent"
+	"onthemat/pkg/ent/user"
+	"onthemat/pkg/ent/useridentity"
+	"onthemat/pkg/entx"
+)
+
+const (
+	ErrLastLoginMethod = "마지막 로그인 수단은 연결 해제할 수 없습니다"
+)
+
+type UserIdentityRepository interface {
+	// 소셜 계정이 연결된 유저를 조회한다.
+	GetUser(ctx context.Context, provider model.SocialType, providerKey string) (*ent.User, error)
+	List(ctx context.Context, userId int) ([]*ent.UserIdentity, error)
+	// 이미 다른 유저에 연결된 소셜 계정이거나 같은 업체가 이미 연결되어 있으면 ConstraintError 를 반환한다.
+	Create(ctx context.Context, identity *ent.UserIdentity) (*ent.UserIdentity, error)
+	// 비밀번호가 없고 마지막으로 남은 소셜 계정이면 ErrLastLoginMethod 에러를 반환한다.
+	Delete(ctx context.Context, userId int, provider model.SocialType) error
+}
+
+type userIdentityRepository struct {
+	db *ent.Client
+}
+
+func NewUserIdentityRepository(db *ent.Client) UserIdentityRepository {
+	return &userIdentityRepository{
+		db: db,
+	}
+}
+
+func (repo *userIdentityRepository) GetUser(ctx context.Context, provider model.SocialType, providerKey string) (*ent.User, error) {
+	return repo.db.User.Query().
+		Where(
+			user.HasIdentitiesWith(
+				useridentity.ProviderEQ(provider),
+				useridentity.ProviderKeyEQ(providerKey),
+			),
+		).
+		Only(ctx)
+}
+
+func (repo *userIdentityRepository) List(ctx context.Context, userId int) ([]*ent.UserIdentity, error) {
+	return repo.db.UserIdentity.Query().
+		Where(useridentity.UserIDEQ(userId)).
+		Order(ent.Asc(useridentity.FieldID)).
+		All(ctx)
+}
+
+func (repo *userIdentityRepository) Create(ctx context.Context, identity *ent.UserIdentity) (*ent.UserIdentity, error) {
+	return repo.db.UserIdentity.Create().
+		SetUserID(identity.UserID).
+		SetProvider(identity.Provider).
+		SetProviderKey(identity.ProviderKey).
+		SetNillableEmail(identity.Email).
+		Save(ctx)
+}
+
+func (repo *userIdentityRepository) Delete(ctx context.Context, userId int, provider model.SocialType) error {
+	return entx.WithTx(ctx, repo.db, func(tx *ent.Tx) (err error) {
+		// 유저 행을 먼저 갱신해 락을 잡는다. 동시에 두 업체를 해제해서 로그인 수단이 모두 사라지는 것을 막는다.
+		u, err := tx.User.UpdateOneID(userId).
+			Save(ctx)
+		if err != nil {
+			return
+		}
+
+		deleted, err := tx.UserIdentity.Delete().
+			Where(
+				useridentity.UserIDEQ(userId),
+				useridentity.ProviderEQ(provider),
+			).
+			Exec(ctx)
+		if err != nil {
+			return
+		}
+		if deleted == 0 {
+			return &ent.NotFoundError{}
+		}
+
+		remaining, err := tx.UserIdentity.Query().
+			Where(useridentity.UserIDEQ(userId)).
+			Count(ctx)
+		if err != nil {
+			return
+		}
+		if u.Password == nil && remaining == 0 {
+			return errors.New(ErrLastLoginMethod)
+		}
+		return
+	})
+}
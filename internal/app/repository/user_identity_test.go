@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/config"
+	"onthemat/internal/app/infrastructure"
+	"onthemat/internal/app/model"
+	"onthemat/internal/app/utils"
+	"onthemat/pkg/ent"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserIdentityRepositoryTestSuite struct {
+	suite.Suite
+	config           *config.Config
+	client           *ent.Client
+	userIdentityRepo UserIdentityRepository
+	userRepo         UserRepository
+	ctx              context.Context
+
+	// data
+	socialUserId   int
+	passwordUserId int
+}
+
+// 모든 테스트 시작 전 1회
+func (ts *UserIdentityRepositoryTestSuite) SetupSuite() {
+	t := ts.T()
+	ts.ctx = context.Background()
+
+	// 도커 디비 삭제 후 생성
+	utils.RepoTestClose(t)
+	time.Sleep(1 * time.Second)
+	ts.config = utils.RepoTestInit(t)
+	time.Sleep(3 * time.Second)
+	// 포스트그레스 연결
+	ts.client = infrastructure.NewPostgresDB(ts.config)
+
+	// 모듈 연결
+	ts.userIdentityRepo = NewUserIdentityRepository(ts.client)
+	ts.userRepo = NewUserRepository(ts.client)
+}
+
+// 모든 테스트 종료 후 1회
+func (ts *UserIdentityRepositoryTestSuite) TearDownSuite() {
+	ts.client.Close()
+	utils.RepoTestClose(nil)
+}
+
+// 각 테스트 종료 후 N회
+func (ts *UserIdentityRepositoryTestSuite) TearDownTest() {
+	utils.RepoTestTruncateTable(context.Background(), ts.client)
+}
+
+func (ts *UserIdentityRepositoryTestSuite) BeforeTest(suiteName, testName string) {
+	if suiteName == "UserIdentityRepositoryTestSuite" {
+		// 소셜 로그인만 하는 유저 (카카오)
+		u, err := ts.userRepo.CreateWithIdentity(ts.ctx, &ent.User{}, &ent.UserIdentity{
+			Provider:    model.KakaoSocialType,
+			ProviderKey: "kakaoKey",
+		})
+		ts.NoError(err)
+		ts.socialUserId = u.ID
+
+		// 비밀번호가 있는 유저
+		password := "password"
+		u, err = ts.userRepo.Create(ts.ctx, &ent.User{Password: &password})
+		ts.NoError(err)
+		ts.passwordUserId = u.ID
+	}
+}
+
+func (ts *UserIdentityRepositoryTestSuite) TestCreate() {
+	ts.Run("다른 업체 연결", func() {
+		_, err := ts.userIdentityRepo.Create(ts.ctx, &ent.UserIdentity{
+			UserID:      ts.socialUserId,
+			Provider:    model.GoogleSocialType,
+			ProviderKey: "googleKey",
+		})
+		ts.NoError(err)
+
+		identities, err := ts.userIdentityRepo.List(ts.ctx, ts.socialUserId)
+		ts.NoError(err)
+		ts.Len(identities, 2)
+
+		u, err := ts.userIdentityRepo.GetUser(ts.ctx, model.GoogleSocialType, "googleKey")
+		ts.NoError(err)
+		ts.Equal(ts.socialUserId, u.ID)
+	})
+
+	ts.Run("같은 업체의 다른 계정", func() {
+		_, err := ts.userIdentityRepo.Create(ts.ctx, &ent.UserIdentity{
+			UserID:      ts.socialUserId,
+			Provider:    model.KakaoSocialType,
+			ProviderKey: "otherKakaoKey",
+		})
+		ts.Equal(ent.IsConstraintError(err), true)
+	})
+
+	ts.Run("다른 유저에 연결된 계정", func() {
+		_, err := ts.userIdentityRepo.Create(ts.ctx, &ent.UserIdentity{
+			UserID:      ts.passwordUserId,
+			Provider:    model.KakaoSocialType,
+			ProviderKey: "kakaoKey",
+		})
+		ts.Equal(ent.IsConstraintError(err), true)
+	})
+}
+
+func (ts *UserIdentityRepositoryTestSuite) TestDelete() {
+	ts.Run("마지막 로그인 수단", func() {
+		err := ts.userIdentityRepo.Delete(ts.ctx, ts.socialUserId, model.KakaoSocialType)
+		ts.EqualError(err, ErrLastLoginMethod)
+
+		identities, err := ts.userIdentityRepo.List(ts.ctx, ts.socialUserId)
+		ts.NoError(err)
+		ts.Len(identities, 1)
+	})
+
+	ts.Run("연결되지 않은 업체", func() {
+		err := ts.userIdentityRepo.Delete(ts.ctx, ts.socialUserId, model.NaverSocialType)
+		ts.Equal(ent.IsNotFound(err), true)
+	})
+
+	ts.Run("다른 업체가 연결되어 있으면 해제", func() {
+		_, err := ts.userIdentityRepo.Create(ts.ctx, &ent.UserIdentity{
+			UserID:      ts.socialUserId,
+			Provider:    model.NaverSocialType,
+			ProviderKey: "naverKey",
+		})
+		ts.NoError(err)
+
+		err = ts.userIdentityRepo.Delete(ts.ctx, ts.socialUserId, model.KakaoSocialType)
+		ts.NoError(err)
+	})
+
+	ts.Run("비밀번호가 있으면 마지막 소셜 계정도 해제", func() {
+		_, err := ts.userIdentityRepo.Create(ts.ctx, &ent.UserIdentity{
+			UserID:      ts.passwordUserId,
+			Provider:    model.GoogleSocialType,
+			ProviderKey: "googleKey",
+		})
+		ts.NoError(err)
+
+		err = ts.userIdentityRepo.Delete(ts.ctx, ts.passwordUserId, model.GoogleSocialType)
+		ts.NoError(err)
+	})
+}
+
+func TestUserIdentityRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserIdentityRepositoryTestSuite))
+}
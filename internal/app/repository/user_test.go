@@ -43,12 +43,16 @@ type UserRepositoryTestSuite struct {
 		email string
 	}
 
-	testGetByEmailPassword [3]struct {
-		id           int
-		email        string
-		password     string
-		tempPassword string
-		nickname     string
+	testUpdatePasswordData struct {
+		id       int
+		email    string
+		password string
+	}
+
+	testWithdrawData struct {
+		id        int
+		email     string
+		socialKey string
 	}
 
 	testAddYogaData struct {
@@ -56,6 +60,10 @@ type UserRepositoryTestSuite struct {
 		email string
 	}
 
+	testTotpData struct {
+		id int
+	}
+
 	// Flag For BeforeRun
 	createSocialKey bool
 }
@@ -133,37 +141,39 @@ func (ts *UserRepositoryTestSuite) BeforeTest(suiteName, testName string) {
 
 			ts.testGetData.id = user.ID
 
-		case "TestGetByEmaillPassword":
-			// 첫 번째 유저
-
-			ts.testGetByEmailPassword[0].email = "asd@gmail.com"
-			ts.testGetByEmailPassword[0].password = "password"
-			user1, err := ts.userRepo.Create(ts.ctx, &ent.User{
-				Email:    &ts.testGetByEmailPassword[0].email,
-				Password: &ts.testGetByEmailPassword[0].password,
+		case "TestUpdatePassword":
+			ts.testUpdatePasswordData.email = "asd@gmail.com"
+			ts.testUpdatePasswordData.password = "legacyHash"
+			user, err := ts.userRepo.Create(ts.ctx, &ent.User{
+				Email:    &ts.testUpdatePasswordData.email,
+				Password: &ts.testUpdatePasswordData.password,
 			})
 			ts.NoError(err)
-			ts.testGetByEmailPassword[0].id = user1.ID
-
-			// 임시비밀번호 유저
-
-			ts.testGetByEmailPassword[1].email = "asd2@gmail.com"
-			ts.testGetByEmailPassword[1].password = "password"
-			ts.testGetByEmailPassword[1].tempPassword = "tempPassword"
-			ts.testGetByEmailPassword[1].nickname = "nickname"
-			user2, err := ts.userRepo.Create(ts.ctx, &ent.User{
-				Email:    &ts.testGetByEmailPassword[1].email,
-				Password: &ts.testGetByEmailPassword[1].password,
-				Nickname: &ts.testGetByEmailPassword[1].nickname,
+			ts.testUpdatePasswordData.id = user.ID
+
+		case "TestWithdraw":
+			ts.testWithdrawData.email = "withdraw@gmail.com"
+			ts.testWithdrawData.socialKey = "withdrawKey"
+			nickname := "nick"
+			phoneNum := "01043226633"
+			user, err := ts.userRepo.CreateWithIdentity(ts.ctx, &ent.User{
+				Email:    &ts.testWithdrawData.email,
+				Nickname: &nickname,
+				PhoneNum: &phoneNum,
+			}, &ent.UserIdentity{
+				Provider:    model.KakaoSocialType,
+				ProviderKey: ts.testWithdrawData.socialKey,
 			})
 			ts.NoError(err)
+			ts.testWithdrawData.id = user.ID
 
-			err = ts.userRepo.UpdateTempPassword(ts.ctx, &ent.User{
-				Email:        &ts.testGetByEmailPassword[1].email,
-				TempPassword: &ts.testGetByEmailPassword[1].tempPassword,
+		case "TestTotp":
+			email := "totp@gmail.com"
+			user, err := ts.userRepo.Create(ts.ctx, &ent.User{
+				Email: &email,
 			})
 			ts.NoError(err)
-			ts.testGetByEmailPassword[1].id = user2.ID
+			ts.testTotpData.id = user.ID
 
 		case "TestAddYoga":
 			ts.testAddYogaData.email = "asd@gmail.com"
@@ -185,8 +195,6 @@ func (ts *UserRepositoryTestSuite) TestCreate() {
 		ts.Empty(u.Nickname)
 		ts.Empty(u.Password)
 		ts.Empty(u.PhoneNum)
-		ts.Empty(u.SocialKey)
-		ts.Empty(u.SocialName)
 		ts.Equal(u.ID, 1)
 	})
 
@@ -207,25 +215,31 @@ func (ts *UserRepositoryTestSuite) TestCreate() {
 		ts.NoError(err)
 	})
 
-	ts.Run("SocialKey 중복됐을 때", func() {
+	ts.Run("소셜 계정과 함께 생성", func() {
 		ts.createSocialKey = true
-		socialName := model.KakaoSocialType
 		socialKey := "asdasdasd"
 
-		_, err := ts.userRepo.Create(ts.ctx, &ent.User{
-			SocialName: &socialName,
-			SocialKey:  &socialKey,
+		u, err := ts.userRepo.CreateWithIdentity(ts.ctx, &ent.User{}, &ent.UserIdentity{
+			Provider:    model.KakaoSocialType,
+			ProviderKey: socialKey,
 		})
-
 		ts.NoError(err)
 
-		// duplicated key
-		_, err = ts.userRepo.Create(ts.ctx, &ent.User{
-			SocialName: &socialName,
-			SocialKey:  &socialKey,
+		u, err = ts.userRepo.GetWithIdentities(ts.ctx, u.ID)
+		ts.NoError(err)
+		ts.Len(u.Edges.Identities, 1)
+		ts.Equal(socialKey, u.Edges.Identities[0].ProviderKey)
+
+		// 이미 연결된 소셜 계정이면 유저도 생성되지 않는다.
+		before, _ := ts.client.User.Query().Count(ts.ctx)
+		_, err = ts.userRepo.CreateWithIdentity(ts.ctx, &ent.User{}, &ent.UserIdentity{
+			Provider:    model.KakaoSocialType,
+			ProviderKey: socialKey,
 		})
-
 		ts.Equal(ent.IsConstraintError(err), true)
+
+		after, _ := ts.client.User.Query().Count(ts.ctx)
+		ts.Equal(before, after)
 	})
 
 	ts.Run("이메일 중복됐을 때", func() {
@@ -261,6 +275,24 @@ func (ts *UserRepositoryTestSuite) TestUpdate() {
 		ts.Equal(*user.Nickname, nickname)
 		ts.Equal(*user.PhoneNum, phoneNum)
 	})
+
+	ts.Run("휴대폰 번호 인증 후 번호 변경", func() {
+		err := ts.userRepo.UpdatePhoneVerified(ts.ctx, ts.testUpdateData.id, "01011112222")
+		ts.NoError(err)
+
+		user, err := ts.userRepo.Get(ts.ctx, ts.testUpdateData.id)
+		ts.NoError(err)
+		ts.Equal("01011112222", *user.PhoneNum)
+		ts.True(user.IsPhoneVerified)
+
+		phoneNum := "01033334444"
+		user, err = ts.userRepo.Update(ts.ctx, &ent.User{
+			ID:       ts.testUpdateData.id,
+			PhoneNum: &phoneNum,
+		})
+		ts.NoError(err)
+		ts.False(user.IsPhoneVerified)
+	})
 }
 
 func (ts *UserRepositoryTestSuite) TestGet() {
@@ -290,66 +322,117 @@ func (ts *UserRepositoryTestSuite) TestFindByEmail() {
 	})
 }
 
-func (ts *UserRepositoryTestSuite) TestGetByEmaillPassword() {
-	ts.Run("이메일 비밀번호 모두 일치할 때", func() {
-		user, err := ts.userRepo.GetByEmailPassword(ts.ctx, &ent.User{
-			Email:    &ts.testGetByEmailPassword[0].email,
-			Password: &ts.testGetByEmailPassword[0].password,
-		})
+func (ts *UserRepositoryTestSuite) TestUpdatePassword() {
+	ts.Run("비밀번호 해시 교체", func() {
+		err := ts.userRepo.UpdatePassword(ts.ctx, ts.testUpdatePasswordData.id, "$argon2id$newHash")
 		ts.NoError(err)
-		ts.Equal(*user.Email, ts.testGetByEmailPassword[0].email)
+
+		user, err := ts.userRepo.GetByEmail(ts.ctx, ts.testUpdatePasswordData.email)
+		ts.NoError(err)
+		ts.Equal("$argon2id$newHash", *user.Password)
 	})
 
-	ts.Run("임시 비밀번호가 있는 경우", func() {
-		ts.Run("임시 비밀번호로 조회", func() {
-			user, err := ts.userRepo.GetByEmailPassword(ts.ctx, &ent.User{
-				Email:    &ts.testGetByEmailPassword[1].email,
-				Password: &ts.testGetByEmailPassword[1].tempPassword,
-			})
-			ts.NoError(err)
-			ts.Equal(*user.Email, ts.testGetByEmailPassword[1].email)
-		})
+	ts.Run("존재하지 않는 유저", func() {
+		err := ts.userRepo.UpdatePassword(ts.ctx, -1, "$argon2id$newHash")
+		ts.Equal(ent.IsNotFound(err), true)
+	})
+}
 
-		ts.Run("일반 비밀번호로 조회", func() {
-			user, err := ts.userRepo.GetByEmailPassword(ts.ctx, &ent.User{
-				Email:    &ts.testGetByEmailPassword[1].email,
-				Password: &ts.testGetByEmailPassword[1].password,
-			})
-			ts.NoError(err)
-			ts.Equal(*user.Email, ts.testGetByEmailPassword[1].email)
-		})
+func (ts *UserRepositoryTestSuite) TestWithdraw() {
+	deletedAt := time.Now().Add(-31 * 24 * time.Hour)
+
+	ts.Run("탈퇴", func() {
+		err := ts.userRepo.Withdraw(ts.ctx, ts.testWithdrawData.id, deletedAt)
+		ts.NoError(err)
+
+		err = ts.userRepo.Withdraw(ts.ctx, ts.testWithdrawData.id, deletedAt)
+		ts.Equal(ent.IsNotFound(err), true)
 	})
 
-	ts.Run("이메일 혹은 비밀번호가 일치하지 않을 때", func() {
-		noEmail := "noEmail@no.com"
-		inCorrectPassword := "incorrectPassword"
+	ts.Run("복구 후 다시 탈퇴", func() {
+		err := ts.userRepo.Restore(ts.ctx, ts.testWithdrawData.id)
+		ts.NoError(err)
 
-		ts.Run("이메일 불일치", func() {
-			user, err := ts.userRepo.GetByEmailPassword(ts.ctx, &ent.User{
-				Email:    &noEmail,
-				Password: &ts.testGetByEmailPassword[0].password,
-			})
-			ts.Nil(user)
-			ts.Equal(ent.IsNotFound(err), true)
-		})
+		user, err := ts.userRepo.Get(ts.ctx, ts.testWithdrawData.id)
+		ts.NoError(err)
+		ts.Nil(user.DeletedAt)
 
-		ts.Run("패스워드 불일치", func() {
-			user, err := ts.userRepo.GetByEmailPassword(ts.ctx, &ent.User{
-				Email:    &ts.testGetByEmailPassword[0].email,
-				Password: &inCorrectPassword,
-			})
-			ts.Nil(user)
-			ts.Equal(ent.IsNotFound(err), true)
-		})
+		err = ts.userRepo.Withdraw(ts.ctx, ts.testWithdrawData.id, deletedAt)
+		ts.NoError(err)
+	})
 
-		ts.Run("둘 다 불일치", func() {
-			user, err := ts.userRepo.GetByEmailPassword(ts.ctx, &ent.User{
-				Email:    &noEmail,
-				Password: &inCorrectPassword,
-			})
-			ts.Nil(user)
-			ts.Equal(ent.IsNotFound(err), true)
+	ts.Run("유예 기간이 지나지 않았으면 익명화하지 않음", func() {
+		count, err := ts.userRepo.AnonymizeWithdrawn(ts.ctx, deletedAt.Add(-time.Hour), time.Now())
+		ts.NoError(err)
+		ts.Equal(0, count)
+	})
+
+	ts.Run("익명화", func() {
+		count, err := ts.userRepo.AnonymizeWithdrawn(ts.ctx, time.Now().Add(-30*24*time.Hour), time.Now())
+		ts.NoError(err)
+		ts.Equal(1, count)
+
+		user, err := ts.userRepo.Get(ts.ctx, ts.testWithdrawData.id)
+		ts.NoError(err)
+		ts.Nil(user.Email)
+		ts.Nil(user.PhoneNum)
+		ts.Equal(withdrawnNickname, *user.Nickname)
+		ts.NotNil(user.AnonymizedAt)
+
+		// 같은 이메일, 소셜 계정으로 다시 가입할 수 있다.
+		_, err = ts.userRepo.CreateWithIdentity(ts.ctx, &ent.User{Email: &ts.testWithdrawData.email}, &ent.UserIdentity{
+			Provider:    model.KakaoSocialType,
+			ProviderKey: ts.testWithdrawData.socialKey,
 		})
+		ts.NoError(err)
+	})
+
+	ts.Run("익명화된 유저는 복구할 수 없음", func() {
+		err := ts.userRepo.Restore(ts.ctx, ts.testWithdrawData.id)
+		ts.Equal(ent.IsNotFound(err), true)
+	})
+}
+
+func (ts *UserRepositoryTestSuite) TestTotp() {
+	id := ts.testTotpData.id
+
+	ts.Run("켜기", func() {
+		err := ts.userRepo.EnableTotp(ts.ctx, id, "SECRET", []string{"hash1", "hash2"}, time.Now())
+		ts.NoError(err)
+
+		user, err := ts.userRepo.Get(ts.ctx, id)
+		ts.NoError(err)
+		ts.Equal("SECRET", *user.TotpSecret)
+		ts.Equal([]string{"hash1", "hash2"}, user.TotpRecoveryCodes)
+		ts.NotNil(user.TotpEnabledAt)
+
+		err = ts.userRepo.EnableTotp(ts.ctx, id, "OTHER", nil, time.Now())
+		ts.Equal(ent.IsNotFound(err), true)
+	})
+
+	ts.Run("복구 코드는 한 번만 사용", func() {
+		used, err := ts.userRepo.UseTotpRecoveryCode(ts.ctx, id, "hash1")
+		ts.NoError(err)
+		ts.True(used)
+
+		used, err = ts.userRepo.UseTotpRecoveryCode(ts.ctx, id, "hash1")
+		ts.NoError(err)
+		ts.False(used)
+
+		user, err := ts.userRepo.Get(ts.ctx, id)
+		ts.NoError(err)
+		ts.Equal([]string{"hash2"}, user.TotpRecoveryCodes)
+	})
+
+	ts.Run("끄기", func() {
+		err := ts.userRepo.DisableTotp(ts.ctx, id)
+		ts.NoError(err)
+
+		user, err := ts.userRepo.Get(ts.ctx, id)
+		ts.NoError(err)
+		ts.Nil(user.TotpSecret)
+		ts.Nil(user.TotpRecoveryCodes)
+		ts.Nil(user.TotpEnabledAt)
 	})
 }
 
@@ -4,20 +4,28 @@ import (
 	"fmt"
 	"testing"
 
+	"onthemat/pkg/auth/password"
+
 	"github.com/stretchr/testify/assert"
 )
 
 func TestHashPassword(t *testing.T) {
-	authService := NewAuthService(nil, nil, nil, nil)
-	hashString := authService.HashPassword("password", "secret")
-	hashString2 := authService.HashPassword("password", "secret")
-	diffHahsString := authService.HashPassword("diffpassword", "secret")
-	assert.NotEqual(t, diffHahsString, hashString)
-	assert.Equal(t, hashString, hashString2)
+	authService := NewAuthService(password.NewHasher(password.DefaultParams, "secret"))
+	hashString, err := authService.HashPassword("password")
+	assert.NoError(t, err)
+	hashString2, _ := authService.HashPassword("password")
+	assert.NotEqual(t, hashString, hashString2)
+
+	ok, needsRehash := authService.VerifyPassword(hashString, "password")
+	assert.True(t, ok)
+	assert.False(t, needsRehash)
+
+	ok, _ = authService.VerifyPassword(hashString, "diffpassword")
+	assert.False(t, ok)
 }
 
 func TestIsExpiredEmailForVerify(t *testing.T) {
-	as := NewAuthService(nil, nil, nil, nil)
+	as := NewAuthService(nil)
 	b := as.IsExpiredEmailForVerify("2022-12-26T11:18:26+09:00")
 	fmt.Println(b)
 }
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+
+	"onthemat/pkg/email"
+)
+
+//go:embed templates/mail
+var mailTemplateFS embed.FS
+
+// 메일 템플릿 이름. templates/mail/<이름>.html, <이름>.txt 를 사용한다.
+const (
+	MailVerifyEmail   = "verify_email"
+	MailPasswordReset = "password_reset"
+)
+
+type MailService interface {
+	// 템플릿으로 제목과 본문(HTML, 텍스트)을 만들어 보낸다.
+	Send(to string, template string, data map[string]string) error
+}
+
+type mailTemplate struct {
+	html *htmlTemplate.Template
+	text *textTemplate.Template
+}
+
+type mailService struct {
+	sender    email.Sender
+	templates map[string]*mailTemplate
+}
+
+func NewMailService(sender email.Sender) MailService {
+	templates := make(map[string]*mailTemplate)
+	for _, name := range []string{MailVerifyEmail, MailPasswordReset} {
+		templates[name] = &mailTemplate{
+			html: htmlTemplate.Must(htmlTemplate.ParseFS(mailTemplateFS, "templates/mail/"+name+".html")),
+			text: textTemplate.Must(textTemplate.ParseFS(mailTemplateFS, "templates/mail/"+name+".txt")),
+		}
+	}
+
+	return &mailService{
+		sender:    sender,
+		templates: templates,
+	}
+}
+
+func (m *mailService) Send(to string, template string, data map[string]string) error {
+	msg, err := m.render(template, data)
+	if err != nil {
+		return err
+	}
+	msg.To = []string{to}
+	return m.sender.Send(msg)
+}
+
+// 템플릿이 없거나 data 에 없는 값을 쓰면 에러를 반환한다.
+func (m *mailService) render(template string, data map[string]string) (*email.Message, error) {
+	t, ok := m.templates[template]
+	if !ok {
+		return nil, fmt.Errorf("mail: 알 수 없는 템플릿입니다 (%s)", template)
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := t.text.Option("missingkey=error").ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := t.html.Option("missingkey=error").Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := t.text.Option("missingkey=error").Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	return &email.Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    strings.TrimSpace(html.String()),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"onthemat/pkg/email"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailServiceSend(t *testing.T) {
+	assert := assert.New(t)
+	recorder := email.NewRecorder()
+	mailSvc := NewMailService(recorder)
+
+	resetUrl := "https://onthemat.com/password/reset?token=abc&x=<y>"
+	err := mailSvc.Send("user@onthemat.com", MailPasswordReset, map[string]string{"resetUrl": resetUrl})
+	assert.NoError(err)
+
+	messages := recorder.Messages()
+	assert.Len(messages, 1)
+	msg := messages[0]
+	assert.Equal([]string{"user@onthemat.com"}, msg.To)
+	assert.Equal("[온더맷] 비밀번호 재설정 안내", msg.Subject)
+	assert.Contains(msg.Text, resetUrl)
+	assert.NotContains(msg.Text, "subject")
+	// HTML 본문은 이스케이프된다.
+	assert.Contains(msg.HTML, `href="https://onthemat.com/password/reset?token=abc&amp;x=%3cy%3e"`)
+}
+
+func TestMailServiceSendInvalid(t *testing.T) {
+	assert := assert.New(t)
+	recorder := email.NewRecorder()
+	mailSvc := NewMailService(recorder)
+
+	assert.Error(mailSvc.Send("user@onthemat.com", "unknown", nil))
+	// 템플릿에 필요한 값이 없음
+	assert.Error(mailSvc.Send("user@onthemat.com", MailVerifyEmail, map[string]string{}))
+	assert.Empty(recorder.Messages())
+}
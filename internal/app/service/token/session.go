@@ -0,0 +1,145 @@
+package token
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"onthemat/pkg/auth/store"
+)
+
+// 엑세스 토큰의 세션(uuid)이 아직 살아있는지 확인한다.
+// 세션은 로그인 시 유저별 해시(key: 유저 아이디, field: uuid)에 저장되고 로그아웃/폐기 시 지워진다.
+type SessionChecker interface {
+	IsActive(ctx context.Context, userId int, uuid string) (bool, error)
+	// 이 인스턴스의 캐시에서 지운다. 세션을 폐기한 직후 호출하면 즉시 반영된다.
+	Forget(userId int, uuid string)
+	ForgetUser(userId int)
+}
+
+// 캐시가 이 크기를 넘으면 만료된 항목을 정리하고, 그래도 넘으면 비운다.
+const maxSessionCacheSize = 10000
+
+type sessionEntry struct {
+	active    bool
+	expiredAt time.Time
+}
+
+type sessionChecker struct {
+	store store.Store
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	cache map[int]map[string]sessionEntry
+	size  int
+}
+
+// ttl 동안 조회 결과를 메모리에 보관한다. 다른 인스턴스에서 폐기한 세션은 최대 ttl 만큼 늦게 반영된다.
+func NewSessionChecker(store store.Store, ttl time.Duration) SessionChecker {
+	return &sessionChecker{
+		store: store,
+		ttl:   ttl,
+		cache: make(map[int]map[string]sessionEntry),
+	}
+}
+
+func (s *sessionChecker) IsActive(ctx context.Context, userId int, uuid string) (bool, error) {
+	if uuid == "" {
+		return false, nil
+	}
+
+	now := time.Now()
+	if active, ok := s.get(userId, uuid, now); ok {
+		return active, nil
+	}
+
+	isExist, err := s.store.HExists(ctx, strconv.Itoa(userId), uuid)
+	if err != nil {
+		return false, err
+	}
+
+	s.set(userId, uuid, isExist, now)
+	return isExist, nil
+}
+
+func (s *sessionChecker) Forget(userId int, uuid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sessions, ok := s.cache[userId]; ok {
+		if _, ok := sessions[uuid]; ok {
+			delete(sessions, uuid)
+			s.size--
+		}
+	}
+}
+
+func (s *sessionChecker) ForgetUser(userId int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size -= len(s.cache[userId])
+	delete(s.cache, userId)
+}
+
+func (s *sessionChecker) get(userId int, uuid string, now time.Time) (active bool, ok bool) {
+	if s.ttl <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[userId][uuid]
+	if !ok || now.After(entry.expiredAt) {
+		return false, false
+	}
+	return entry.active, true
+}
+
+func (s *sessionChecker) set(userId int, uuid string, active bool, now time.Time) {
+	if s.ttl <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size >= maxSessionCacheSize {
+		s.sweep(now)
+	}
+
+	sessions, ok := s.cache[userId]
+	if !ok {
+		sessions = make(map[string]sessionEntry)
+		s.cache[userId] = sessions
+	}
+	if _, ok := sessions[uuid]; !ok {
+		s.size++
+	}
+	sessions[uuid] = sessionEntry{
+		active:    active,
+		expiredAt: now.Add(s.ttl),
+	}
+}
+
+// mu 를 잡은 상태에서 호출한다.
+func (s *sessionChecker) sweep(now time.Time) {
+	for userId, sessions := range s.cache {
+		for uuid, entry := range sessions {
+			if now.After(entry.expiredAt) {
+				delete(sessions, uuid)
+				s.size--
+			}
+		}
+		if len(sessions) == 0 {
+			delete(s.cache, userId)
+		}
+	}
+
+	if s.size >= maxSessionCacheSize {
+		s.cache = make(map[int]map[string]sessionEntry)
+		s.size = 0
+	}
+}
@@ -0,0 +1,64 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"onthemat/pkg/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSessionChecker(t *testing.T) {
+	t.Run("조회 결과는 ttl 동안 캐시된다", func(t *testing.T) {
+		store := new(mocks.Store)
+		store.On("HExists", mock.Anything, "1", "session").Return(true, nil).Once()
+
+		checker := NewSessionChecker(store, time.Minute)
+		for i := 0; i < 3; i++ {
+			isActive, err := checker.IsActive(context.Background(), 1, "session")
+			assert.NoError(t, err)
+			assert.True(t, isActive)
+		}
+		store.AssertNumberOfCalls(t, "HExists", 1)
+	})
+
+	t.Run("Forget 이후에는 store 를 다시 조회한다", func(t *testing.T) {
+		store := new(mocks.Store)
+		store.On("HExists", mock.Anything, "1", "session").Return(true, nil).Once()
+		store.On("HExists", mock.Anything, "1", "session").Return(false, nil).Once()
+
+		checker := NewSessionChecker(store, time.Minute)
+		isActive, _ := checker.IsActive(context.Background(), 1, "session")
+		assert.True(t, isActive)
+
+		checker.Forget(1, "session")
+		isActive, _ = checker.IsActive(context.Background(), 1, "session")
+		assert.False(t, isActive)
+	})
+
+	t.Run("ForgetUser 는 유저의 모든 세션을 지운다", func(t *testing.T) {
+		store := new(mocks.Store)
+		store.On("HExists", mock.Anything, "1", mock.AnythingOfType("string")).Return(true, nil).Twice()
+		store.On("HExists", mock.Anything, "1", mock.AnythingOfType("string")).Return(false, nil).Twice()
+
+		checker := NewSessionChecker(store, time.Minute)
+		checker.IsActive(context.Background(), 1, "a")
+		checker.IsActive(context.Background(), 1, "b")
+
+		checker.ForgetUser(1)
+		isActive, _ := checker.IsActive(context.Background(), 1, "a")
+		assert.False(t, isActive)
+		isActive, _ = checker.IsActive(context.Background(), 1, "b")
+		assert.False(t, isActive)
+	})
+
+	t.Run("uuid 가 없는 토큰", func(t *testing.T) {
+		checker := NewSessionChecker(new(mocks.Store), time.Minute)
+		isActive, err := checker.IsActive(context.Background(), 1, "")
+		assert.NoError(t, err)
+		assert.False(t, isActive)
+	})
+}
@@ -9,9 +9,13 @@ import (
 )
 
 type TokenService interface {
-	GenerateToken(uuid string, userId int, loginType string, userType string, expired int) (string, error)
+	// twoFactor 는 2단계 인증을 거쳐 로그인한 세션인지 여부다.
+	GenerateToken(uuid string, userId int, loginType string, userType string, twoFactor bool, expired int) (string, error)
+	// 리프레쉬 토큰은 회전(rotation) 시 재사용 여부를 판단하기 위해 토큰마다 고유 아이디(jti)를 가진다.
+	GenerateRefreshToken(uuid string, tokenId string, userId int, loginType string, userType string, expired int) (string, error)
 	GetExpiredAt(expired int) time.Time
 	ParseToken(tokenString string, result jwtLib.Claims) error
+	JWKS() jwt.JWKSet
 }
 
 // ------------------- default -------------------
@@ -35,17 +39,20 @@ type TokenClaim struct {
 	UserId    int
 	LoginType string
 	UserType  string
+	// 2단계 인증을 거친 세션에서 발급된 토큰
+	TwoFactor bool `json:",omitempty"`
 	jwtLib.RegisteredClaims
 }
 
 // ------------------- Service -------------------
 
-func (t *tokenService) GenerateToken(uuid string, userId int, loginType string, userType string, expired int) (string, error) {
+func (t *tokenService) GenerateToken(uuid string, userId int, loginType string, userType string, twoFactor bool, expired int) (string, error) {
 	claim := TokenClaim{
 		Uuid:      uuid,
 		UserId:    userId,
 		LoginType: loginType,
 		UserType:  userType,
+		TwoFactor: twoFactor,
 		RegisteredClaims: jwtLib.RegisteredClaims{
 			Issuer:    "oneTheMat",
 			IssuedAt:  jwtLib.NewNumericDate(time.Now()),
@@ -55,10 +62,30 @@ func (t *tokenService) GenerateToken(uuid string, userId int, loginType string,
 	return t.jwtPackage.GenerateToken(claim)
 }
 
+func (t *tokenService) GenerateRefreshToken(uuid string, tokenId string, userId int, loginType string, userType string, expired int) (string, error) {
+	claim := TokenClaim{
+		Uuid:      uuid,
+		UserId:    userId,
+		LoginType: loginType,
+		UserType:  userType,
+		RegisteredClaims: jwtLib.RegisteredClaims{
+			ID:        tokenId,
+			Issuer:    "oneTheMat",
+			IssuedAt:  jwtLib.NewNumericDate(time.Now()),
+			ExpiresAt: jwtLib.NewNumericDate(time.Now().Add(time.Duration(expired) * time.Minute)),
+		},
+	}
+	return t.jwtPackage.GenerateToken(claim)
+}
+
 func (t *tokenService) GetExpiredAt(expired int) time.Time {
 	return time.Now().Add(time.Minute * time.Duration(expired)).Local()
 }
 
+func (t *tokenService) JWKS() jwt.JWKSet {
+	return t.jwtPackage.JWKS()
+}
+
 func (t *tokenService) ParseToken(tokenString string, result jwtLib.Claims) error {
 	return t.jwtPackage.ParseToken(tokenString, result)
 }
@@ -12,9 +12,35 @@ func TestLogic(t *testing.T) {
 	jwt := jwt.NewJwt().WithSignKey("asd").Init()
 	tokenModule := NewToken(jwt)
 
-	to, _ := tokenModule.GenerateToken("uuid", 1, "kakao", "teacher", 10)
+	to, _ := tokenModule.GenerateToken("uuid", 1, "kakao", "teacher", false, 10)
 	cl := &TokenClaim{}
 	tokenModule.ParseToken(to, cl)
 	assert.Equal(t, cl.Uuid, "uuid")
 	assert.Equal(t, cl.UserId, 1)
 }
+
+func TestRefreshToken(t *testing.T) {
+	jwt := jwt.NewJwt().WithSignKey("asd").Init()
+	tokenModule := NewToken(jwt)
+
+	to, _ := tokenModule.GenerateRefreshToken("family", "tokenId", 1, "normal", "teacher", 10)
+	cl := &TokenClaim{}
+	tokenModule.ParseToken(to, cl)
+	assert.Equal(t, "family", cl.Uuid)
+	assert.Equal(t, "tokenId", cl.ID)
+}
+
+func TestTwoFactorClaim(t *testing.T) {
+	jwt := jwt.NewJwt().WithSignKey("asd").Init()
+	tokenModule := NewToken(jwt)
+
+	to, _ := tokenModule.GenerateToken("uuid", 1, "normal", "superAdmin", true, 10)
+	cl := &TokenClaim{}
+	tokenModule.ParseToken(to, cl)
+	assert.True(t, cl.TwoFactor)
+
+	to, _ = tokenModule.GenerateToken("uuid", 1, "normal", "superAdmin", false, 10)
+	cl = &TokenClaim{}
+	tokenModule.ParseToken(to, cl)
+	assert.False(t, cl.TwoFactor)
+}
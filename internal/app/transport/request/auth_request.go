@@ -3,13 +3,13 @@ package request
 // ------------------- SocialUrl -------------------
 
 type AuthSocialUrlParam struct {
-	SocialName string `param:"socialName" validate:"required,oneof=kakao naver google"`
+	SocialName string `param:"socialName" validate:"required,oneof=kakao naver google apple"`
 }
 
 // ------------------- SocialCallback -------------------
 
 type AuthSocialCallbackParam struct {
-	SocialName string `param:"socialName" validate:"required,oneof=kakao naver google"`
+	SocialName string `param:"socialName" validate:"required,oneof=kakao naver google apple"`
 }
 
 // ------------------- SignUp -------------------
@@ -40,10 +40,20 @@ type AuthCheckDuplicatedEmailQuery struct {
 	Email string `query:"email,required" validate:"required,email"`
 }
 
-// ------------------- SendTempPassword -------------------
+// ------------------- Password -------------------
 
-type AuthTempPasswordQuery struct {
-	Email string `query:"email,required" validate:"required,email"`
+type AuthPasswordResetRequestBody struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type AuthPasswordResetBody struct {
+	Token    string `json:"token" validate:"required,hexadecimal,len=64"`
+	Password string `json:"password" validate:"required,min=10,max=20"`
+}
+
+type AuthPasswordChangeBody struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=10,max=20,nefield=CurrentPassword"`
 }
 
 // ------------------- VerifyEmail -------------------
@@ -58,3 +68,46 @@ type AuthVerifyEmailQueries struct {
 type AuthLogoutQuery struct {
 	UserId int `query:"userId,required"`
 }
+
+// ------------------- Session -------------------
+
+type AuthSessionParam struct {
+	Uuid string `param:"uuid" validate:"required,uuid"`
+}
+
+// ------------------- Social Identity -------------------
+
+type AuthSocialParam struct {
+	SocialName string `param:"socialName" validate:"required,oneof=kakao naver google apple"`
+}
+
+type AuthSocialLinkBody struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
+
+type AuthSocialLinkConfirmBody struct {
+	LinkToken string `json:"linkToken" validate:"required,hexadecimal,len=64"`
+}
+
+// ------------------- Withdrawal -------------------
+
+type AuthRestoreBody struct {
+	Token string `json:"token" validate:"required,hexadecimal,len=64"`
+}
+
+// ------------------- Two Factor -------------------
+
+type AuthTwoFactorConfirmBody struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// 인증 앱의 6자리 코드 혹은 복구 코드(xxxxx-xxxxx)
+type AuthTwoFactorCodeBody struct {
+	Code string `json:"code" validate:"required,min=6,max=20"`
+}
+
+type AuthTwoFactorLoginBody struct {
+	ChallengeToken string `json:"challengeToken" validate:"required,hexadecimal,len=64"`
+	Code           string `json:"code" validate:"required,min=6,max=20"`
+}
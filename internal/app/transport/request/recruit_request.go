@@ -2,6 +2,7 @@ package request
 
 import (
 	"onthemat/internal/app/transport"
+	"onthemat/internal/app/utils"
 )
 
 // ------------------- Create -------------------
@@ -18,15 +19,30 @@ type (
 		IsOpen bool `json:"isOpen"`
 	}
 	RecruitmentInsteadForCreate struct {
-		MinCareer string     `json:"minCareer" validate:"required"`
-		Pay       string     `json:"pay" validate:"required"`
-		Schedules []Schedule `json:"schedules" validate:"required,dive"`
+		MinCareerMonth int         `json:"minCareerMonth" validate:"min=0,max=600"`
+		PayAmount      int         `json:"payAmount" validate:"required,min=1"`
+		PayCurrency    string      `json:"payCurrency" validate:"omitempty,iso4217"`
+		PayUnit        string      `json:"payUnit" validate:"required,oneof=class hour day"`
+		Schedules      []Schedule  `json:"schedules" validate:"required_without=Recurrence,dive"`
+		Recurrence     *Recurrence `json:"recurrence"`
 	}
 
 	Schedule struct {
 		StartDateTime transport.TimeString `json:"startDateTime" valiate:"required"`
 		EndDateTime   transport.TimeString `json:"endDateTime" valiate:"required"`
 	}
+
+	// 반복 규칙, 서버에서 일정들로 펼쳐서 저장한다.
+	Recurrence struct {
+		Frequency     string                 `json:"frequency" validate:"required,oneof=DAILY WEEKLY"`
+		Interval      int                    `json:"interval" validate:"omitempty,min=1,max=52"`
+		Weekdays      []string               `json:"weekdays" validate:"omitempty,dive,oneof=MO TU WE TH FR SA SU"`
+		Count         *int                   `json:"count" validate:"required_without=Until,omitempty,min=1,max=100"`
+		Until         *transport.TimeString  `json:"until"`
+		StartDateTime transport.TimeString   `json:"startDateTime"`
+		EndDateTime   transport.TimeString   `json:"endDateTime"`
+		ExDates       []transport.TimeString `json:"exDates"`
+	}
 )
 
 // ------------------- Update -------------------
@@ -44,10 +60,13 @@ type (
 		IsOpen   bool `json:"isOpen"`
 	}
 	RecruitmentInsteadForUpdate struct {
-		Id        int        `json:"id"`
-		MinCareer string     `json:"minCareer" validate:"required"`
-		Pay       string     `json:"pay" validate:"required"`
-		Schedules []Schedule `json:"schedules" validate:"required,dive"`
+		Id             int         `json:"id"`
+		MinCareerMonth int         `json:"minCareerMonth" validate:"min=0,max=600"`
+		PayAmount      int         `json:"payAmount" validate:"required,min=1"`
+		PayCurrency    string      `json:"payCurrency" validate:"omitempty,iso4217"`
+		PayUnit        string      `json:"payUnit" validate:"required,oneof=class hour day"`
+		Schedules      []Schedule  `json:"schedules" validate:"required_without=Recurrence,dive"`
+		Recurrence     *Recurrence `json:"recurrence"`
 	}
 )
 
@@ -73,12 +92,13 @@ type (
 		IsFinish *bool `json:"isFinish"`
 	}
 	RecruitmentInsteadForPatch struct {
-		ID            *int                  `json:"id"`
-		MinCareer     *string               `json:"minCareer" validate:"must"`
-		Pay           *string               `json:"pay" validate:"must"`
-		StartDateTime *transport.TimeString `json:"startDateTime" validate:"must"`
-		EndDateTime   *transport.TimeString `json:"endDateTime" validate:"must"`
-		PasserId      *int                  `json:"passerId"`
+		ID             *int    `json:"id"`
+		MinCareerMonth *int    `json:"minCareerMonth" validate:"omitempty,min=0,max=600"`
+		PayAmount      *int    `json:"payAmount" validate:"must,omitempty,min=1"`
+		PayCurrency    *string `json:"payCurrency" validate:"omitempty,iso4217"`
+		PayUnit        *string `json:"payUnit" validate:"must,omitempty,oneof=class hour day"`
+		// 주면 일정 전체를 교체한다. (반복 규칙은 해제된다)
+		Schedules []Schedule `json:"schedules" validate:"omitempty,dive"`
 	}
 )
 
@@ -97,6 +117,14 @@ type RecruitmentListQueries struct {
 	EndDateTime   *transport.TimeString `query:"endDateTime"`
 	YogaIDs       *[]int                `query:"yogaIds"`
 	SigunguIds    *[]int                `query:"sigunguIds"`
+	MinPay        *int                  `query:"minPay" validate:"omitempty,min=0"`
+	PayUnit       *string               `query:"payUnit" validate:"required_with=MinPay,omitempty,oneof=class hour day"`
+	MaxCareer     *int                  `query:"maxCareer" validate:"omitempty,min=0"`
+	IsFinish      *bool                 `query:"isFinish"`
+	HasOpenSlot   *bool                 `query:"hasOpenSlot"`
+	SidoIds       *[]int                `query:"sidoIds"`
+	OrderType     *string               `query:"orderType" validate:"omitempty,oneof=DESC ASC"`
+	OrderCol      *string               `query:"orderCol" validate:"omitempty,oneof=START NEWEST PAY UPDATED"`
 }
 
 func NewRecruitmentListQueries() *RecruitmentListQueries {
@@ -107,6 +135,14 @@ func NewRecruitmentListQueries() *RecruitmentListQueries {
 		EndDateTime:   nil,
 		YogaIDs:       nil,
 		SigunguIds:    nil,
+		MinPay:        nil,
+		PayUnit:       nil,
+		MaxCareer:     nil,
+		IsFinish:      nil,
+		HasOpenSlot:   nil,
+		SidoIds:       nil,
+		OrderType:     utils.String("DESC"),
+		OrderCol:      utils.String("UPDATED"),
 	}
 }
 
@@ -125,3 +161,39 @@ type RecruitmentGetParam struct {
 type RecruitmentDeleteParam struct {
 	Id int `param:"id" validate:"required"`
 }
+
+// ------------------- Apply -------------------
+
+// ___________ Param ___________
+
+type RecruitmentInsteadParam struct {
+	Id        int `params:"id" validate:"required"`
+	InsteadId int `params:"insteadId" validate:"required"`
+}
+
+// ------------------- Applicant -------------------
+
+// ___________ Param ___________
+
+type RecruitmentApplicantParam struct {
+	Id        int `params:"id" validate:"required"`
+	InsteadId int `params:"insteadId" validate:"required"`
+	TeacherId int `params:"teacherId" validate:"required"`
+}
+
+// ------------------- Schedule -------------------
+
+// ___________ body ___________
+
+type RecruitmentScheduleBody struct {
+	StartDateTime transport.TimeString `json:"startDateTime"`
+	EndDateTime   transport.TimeString `json:"endDateTime"`
+}
+
+// ___________ Param ___________
+
+type RecruitmentScheduleParam struct {
+	Id         int `params:"id" validate:"required"`
+	InsteadId  int `params:"insteadId" validate:"required"`
+	ScheduleId int `params:"scheduleId" validate:"required"`
+}
@@ -1,6 +1,9 @@
 package request
 
-import "onthemat/internal/app/transport"
+import (
+	"onthemat/internal/app/transport"
+	"onthemat/internal/app/utils"
+)
 
 // ------------------- Create -------------------
 type (
@@ -129,3 +132,29 @@ type (
 type TeacherPatchParam struct {
 	Id int `params:"id" validate:"required"`
 }
+
+// ------------------- List -------------------
+
+type TeacherListQueries struct {
+	PageNo        int     `query:"pageNo"`
+	PageSize      int     `query:"pageSize"`
+	YogaIDs       *[]int  `query:"yogaIds"`
+	SigunguIds    *[]int  `query:"sigunguIds"`
+	MinCareerYear *int    `query:"minCareerYear" validate:"omitempty,min=0"`
+	AgencyName    *string `query:"agencyName"`
+	OrderType     *string `query:"orderType" validate:"omitempty,oneof=DESC ASC"`
+	OrderCol      *string `query:"orderCol" validate:"omitempty,oneof=NAME ID"`
+}
+
+func NewTeacherListQueries() *TeacherListQueries {
+	return &TeacherListQueries{
+		PageNo:        1,
+		PageSize:      10,
+		YogaIDs:       nil,
+		SigunguIds:    nil,
+		MinCareerYear: nil,
+		AgencyName:    nil,
+		OrderType:     utils.String("DESC"),
+		OrderCol:      utils.String("ID"),
+	}
+}
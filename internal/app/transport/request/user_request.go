@@ -10,3 +10,13 @@ type UserUpdateBody struct {
 type UserUpdateParam struct {
 	Id int `params:"id" validate:"required"`
 }
+
+// ------------------- Phone Verify -------------------
+
+type UserPhoneVerifyRequestBody struct {
+	PhoneNum string `json:"phone_num" validate:"required,phoneNumNoDash"`
+}
+
+type UserPhoneVerifyConfirmBody struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
@@ -1,6 +1,8 @@
 package response
 
 import (
+	"time"
+
 	"onthemat/internal/app/transport"
 	"onthemat/internal/app/usecase"
 
@@ -8,8 +10,10 @@ import (
 )
 
 type RefreshResponse struct {
-	AccessToken          string               `json:"accessToken"`
-	AccessTokenExpiredAt transport.TimeString `json:"accessTokenExpiredAt"`
+	AccessToken           string               `json:"accessToken"`
+	AccessTokenExpiredAt  transport.TimeString `json:"accessTokenExpiredAt"`
+	RefreshToken          string               `json:"refreshToken"`
+	RefreshTokenExpiredAt transport.TimeString `json:"refreshTokenExpiredAt"`
 }
 
 func NewRefreshResponse(result *usecase.RefreshResult) *RefreshResponse {
@@ -17,3 +21,61 @@ func NewRefreshResponse(result *usecase.RefreshResult) *RefreshResponse {
 	copier.Copy(&resp, result)
 	return resp
 }
+
+// ------------------- Session -------------------
+
+type SessionResponse struct {
+	Uuid       string                `json:"uuid"`
+	UserAgent  string                `json:"userAgent"`
+	Ip         string                `json:"ip"`
+	LoginType  string                `json:"loginType"`
+	IsCurrent  bool                  `json:"isCurrent"`
+	CreatedAt  *transport.TimeString `json:"createdAt"`
+	LastUsedAt *transport.TimeString `json:"lastUsedAt"`
+}
+
+// 기기 정보가 없던 기존 세션은 시간이 비어 있으므로 null 로 내려준다.
+func NewSessionListResponse(sessions []*usecase.Session, currentUuid string) []*SessionResponse {
+	response := make([]*SessionResponse, 0, len(sessions))
+	for _, v := range sessions {
+		response = append(response, &SessionResponse{
+			Uuid:       v.Uuid,
+			UserAgent:  v.UserAgent,
+			Ip:         v.Ip,
+			LoginType:  v.LoginType,
+			IsCurrent:  v.Uuid == currentUuid,
+			CreatedAt:  optionalTime(v.CreatedAt),
+			LastUsedAt: optionalTime(v.LastUsedAt),
+		})
+	}
+	return response
+}
+
+func optionalTime(t time.Time) *transport.TimeString {
+	if t.IsZero() {
+		return nil
+	}
+	v := transport.TimeString(t)
+	return &v
+}
+
+// ------------------- Two Factor -------------------
+
+// 2단계 인증을 사용하는 유저의 로그인 응답. 토큰 대신 받은 challengeToken 을 인증 코드와 함께 /auth/login/two-factor 로 보낸다.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool                 `json:"twoFactorRequired"`
+	ChallengeToken    string               `json:"challengeToken"`
+	ExpiredAt         transport.TimeString `json:"expiredAt"`
+}
+
+func NewTwoFactorChallengeResponse(challenge *usecase.TwoFactorChallenge) *TwoFactorChallengeResponse {
+	return &TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge.ChallengeToken,
+		ExpiredAt:         transport.TimeString(challenge.ExpiredAt),
+	}
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -14,21 +14,40 @@ type RecruitmentListReponse struct {
 	Yogas          []string               `json:"yogas"`
 	Sigungu        string                 `json:"sigungu"`
 	StartDateTimes []transport.TimeString `json:"startDateTimes"`
+	Pays           []*payInfo             `json:"pays"`
+	IsFinish       bool                   `json:"isFinish"`
+	OpenSlotCount  int                    `json:"openSlotCount"`
 	CreatedAt      transport.TimeString   `json:"createdAt"`
 	UpdatedAt      transport.TimeString   `json:"updatedAt"`
 }
 
+type payInfo struct {
+	Amount   int     `json:"amount"`
+	Currency string  `json:"currency"`
+	Unit     *string `json:"unit"`
+}
+
 func NewRecruitmentListResponse(model []*ent.Recruitment) []*RecruitmentListReponse {
 	response := make([]*RecruitmentListReponse, 0)
 
 	for _, v := range model {
 		yogas := make([]string, 0)
 		startDateTimes := make([]transport.TimeString, 0)
+		pays := make([]*payInfo, 0)
+		openSlotCount := 0
 		for _, j := range v.Edges.RecruitmentInstead {
+			if j.TeacherID == nil {
+				openSlotCount++
+			}
+			pays = append(pays, &payInfo{
+				Amount:   j.PayAmount,
+				Currency: j.PayCurrency,
+				Unit:     j.PayUnit.ToString(),
+			})
 			for _, y := range j.Edges.Yoga {
 				yogas = append(yogas, y.NameKor)
 			}
-			for _, s := range j.Schedule {
+			for _, s := range j.Edges.Schedules {
 				startDateTimes = append(startDateTimes, s.StartDateTime)
 			}
 
@@ -40,6 +59,9 @@ func NewRecruitmentListResponse(model []*ent.Recruitment) []*RecruitmentListRepo
 			Yogas:          yogas,
 			Sigungu:        v.Edges.Writer.Edges.AreaSigungu.Name,
 			StartDateTimes: startDateTimes,
+			Pays:           pays,
+			IsFinish:       v.IsFinish,
+			OpenSlotCount:  openSlotCount,
 			CreatedAt:      v.CreatedAt,
 			UpdatedAt:      v.UpdatedAt,
 		})
@@ -61,11 +83,14 @@ type RecruitmentReponse struct {
 
 type insteadInfo struct {
 	ID             int                  `json:"id"`
-	MinCareer      string               `json:"minCareer"`
-	Pay            string               `json:"pay"`
+	MinCareerMonth int                  `json:"minCareerMonth"`
+	PayAmount      int                  `json:"payAmount"`
+	PayCurrency    string               `json:"payCurrency"`
+	PayUnit        *string              `json:"payUnit"`
 	PasserId       *int                 `json:"passerId"`
 	ApplicantCount int                  `json:"applicantCount"`
-	Schedules      []*model.Schedule    `json:"schedules"`
+	Recurrence     *model.Recurrence    `json:"recurrence"`
+	Schedules      []*scheduleInfo      `json:"schedules"`
 	Yogas          []*yogaInfo          `json:"yogas"`
 	CreatedAt      transport.TimeString `json:"createdAt"`
 	UpdatedAt      transport.TimeString `json:"updatedAt"`
@@ -97,11 +122,14 @@ func NewRecruitmentResponse(v *ent.Recruitment) *RecruitmentReponse {
 
 		riInfo = append(riInfo, &insteadInfo{
 			ID:             v.ID,
-			MinCareer:      v.MinCareer,
+			MinCareerMonth: v.MinCareerMonth,
 			PasserId:       v.TeacherID,
 			ApplicantCount: len(v.Edges.Applicant),
-			Pay:            v.Pay,
-			Schedules:      v.Schedule,
+			PayAmount:      v.PayAmount,
+			PayCurrency:    v.PayCurrency,
+			PayUnit:        v.PayUnit.ToString(),
+			Recurrence:     v.Recurrence,
+			Schedules:      newSchedules(v.Edges.Schedules),
 			Yogas:          yogas,
 			CreatedAt:      v.CreatedAt,
 			UpdatedAt:      v.UpdatedAt,
@@ -125,3 +153,78 @@ func NewRecruitmentResponse(v *ent.Recruitment) *RecruitmentReponse {
 
 	return resp
 }
+
+// ------------------- Applicant -------------------
+
+type RecruitmentApplicantResponse struct {
+	ID              int                  `json:"id"`
+	Name            string               `json:"name"`
+	Age             *int                 `json:"age"`
+	ProfileImageUrl *string              `json:"profileImageUrl"`
+	Introduce       *string              `json:"introduce"`
+	Status          *string              `json:"status"`
+	AppliedAt       transport.TimeString `json:"appliedAt"`
+	UpdatedAt       transport.TimeString `json:"updatedAt"`
+}
+
+func NewRecruitmentApplicantListResponse(model []*ent.RecruitmentApplication) []*RecruitmentApplicantResponse {
+	response := make([]*RecruitmentApplicantResponse, 0)
+
+	for _, v := range model {
+		t := v.Edges.Teacher
+		response = append(response, &RecruitmentApplicantResponse{
+			ID:              t.ID,
+			Name:            t.Name,
+			Age:             t.Age,
+			ProfileImageUrl: t.ProfileImageUrl,
+			Introduce:       t.Introduce,
+			Status:          v.Status.ToString(),
+			AppliedAt:       v.CreatedAt,
+			UpdatedAt:       v.UpdatedAt,
+		})
+	}
+
+	return response
+}
+
+// ------------------- Schedule Conflict -------------------
+
+type RecruitmentScheduleConflictResponse struct {
+	RecruitmentId int             `json:"recruitmentId"`
+	InsteadId     int             `json:"insteadId"`
+	Schedules     []*scheduleInfo `json:"schedules"`
+}
+
+func NewRecruitmentScheduleConflictResponse(model []*ent.RecruitmentInstead) []*RecruitmentScheduleConflictResponse {
+	response := make([]*RecruitmentScheduleConflictResponse, 0)
+
+	for _, v := range model {
+		response = append(response, &RecruitmentScheduleConflictResponse{
+			RecruitmentId: v.RecruitmentID,
+			InsteadId:     v.ID,
+			Schedules:     newSchedules(v.Edges.Schedules),
+		})
+	}
+
+	return response
+}
+
+type scheduleInfo struct {
+	Id                    int                   `json:"id"`
+	StartDateTime         transport.TimeString  `json:"startDateTime"`
+	EndDateTime           transport.TimeString  `json:"endDateTime"`
+	OriginalStartDateTime *transport.TimeString `json:"originalStartDateTime"`
+}
+
+func newSchedules(vals []*ent.RecruitmentInsteadSchedule) []*scheduleInfo {
+	result := make([]*scheduleInfo, 0)
+	for _, v := range vals {
+		result = append(result, &scheduleInfo{
+			Id:                    v.ID,
+			StartDateTime:         v.StartDateTime,
+			EndDateTime:           v.EndDateTime,
+			OriginalStartDateTime: v.OriginalStartDateTime,
+		})
+	}
+	return result
+}
@@ -121,3 +121,55 @@ func NewTeacherResponse(d *ent.Teacher) *TeacherResponse {
 
 	return resp
 }
+
+// ------------------- List -------------------
+
+type TeacherListResponse struct {
+	Id                  int                   `json:"id"`
+	Name                string                `json:"name"`
+	Age                 *int                  `json:"age"`
+	Introduce           *string               `json:"introduce"`
+	ProfileImageUrl     *string               `json:"profileImageUrl"`
+	Yoga                []yoga                `json:"yoga"`
+	PossibleWorkSigungu []possibleWorkSigungu `json:"possibleWorkSigungu"`
+	CreatedAt           transport.TimeString  `json:"createdAt"`
+	UpdatedAt           transport.TimeString  `json:"updatedAt"`
+}
+
+func NewTeacherListResponse(model []*ent.Teacher) []*TeacherListResponse {
+	response := make([]*TeacherListResponse, 0)
+
+	for _, d := range model {
+		resp := &TeacherListResponse{
+			Id:              d.ID,
+			Name:            d.Name,
+			Age:             d.Age,
+			Introduce:       d.Introduce,
+			ProfileImageUrl: d.ProfileImageUrl,
+			CreatedAt:       d.CreatedAt,
+			UpdatedAt:       d.UpdatedAt,
+		}
+
+		resp.Yoga = make([]yoga, 0)
+		for i, v := range d.Edges.Yoga {
+			resp.Yoga = append(resp.Yoga, yoga{
+				Index:       i,
+				ID:          v.ID,
+				NameKor:     v.NameKor,
+				IsReference: true,
+			})
+		}
+
+		resp.PossibleWorkSigungu = make([]possibleWorkSigungu, 0)
+		for _, v := range d.Edges.Sigungu {
+			resp.PossibleWorkSigungu = append(resp.PossibleWorkSigungu, possibleWorkSigungu{
+				Id:   v.ID,
+				Name: v.Name,
+			})
+		}
+
+		response = append(response, resp)
+	}
+
+	return response
+}
@@ -10,24 +10,71 @@ import (
 // ------------------- Get -------------------
 
 type UserMeResponse struct {
-	ID          int                  `json:"id"`
-	Email       *string              `json:"email"`
-	LogoUrl     string               `json:"logo_url"`
-	Nickname    *string              `json:"nickname"`
-	SocialName  *string              `json:"social_name"`
-	SocialKey   *string              `json:"social_key"`
-	Type        *string              `json:"type"`
-	PhoneNum    *string              `json:"phone_num"`
-	CreatedAt   transport.TimeString `json:"created_at"`
-	LastLoginAt transport.TimeString `json:"last_login_at"`
+	ID         int                       `json:"id"`
+	Email      *string                   `json:"email"`
+	LogoUrl    string                    `json:"logo_url"`
+	Nickname   *string                   `json:"nickname"`
+	SocialName *string                   `json:"social_name"` // 처음 연결한 소셜 계정
+	SocialKey  *string                   `json:"social_key"`
+	Identities []*SocialIdentityResponse `json:"identities"`
+	Type       *string                   `json:"type"`
+	PhoneNum   *string                   `json:"phone_num"`
+	// 휴대폰 번호 인증 여부
+	IsPhoneVerified bool `json:"is_phone_verified"`
+	// 2단계 인증 사용 여부
+	IsTwoFactorEnabled bool                 `json:"is_two_factor_enabled"`
+	CreatedAt          transport.TimeString `json:"created_at"`
+	LastLoginAt        transport.TimeString `json:"last_login_at"`
 }
 
 func NewUserMeResponse(model *ent.User) *UserMeResponse {
 	resp := new(UserMeResponse)
 	copier.Copy(&resp, model)
 
-	resp.SocialName = model.SocialName.ToString()
 	resp.Type = model.Type.ToString()
+	resp.IsTwoFactorEnabled = model.TotpEnabledAt != nil
+
+	resp.Identities = NewSocialIdentityListResponse(model.Edges.Identities)
+	if len(model.Edges.Identities) > 0 {
+		resp.SocialName = model.Edges.Identities[0].Provider.ToString()
+		resp.SocialKey = &model.Edges.Identities[0].ProviderKey
+	}
 
 	return resp
 }
+
+// ------------------- Social Identity -------------------
+
+type SocialIdentityResponse struct {
+	Provider *string              `json:"provider"`
+	Email    *string              `json:"email"`
+	LinkedAt transport.TimeString `json:"linkedAt"`
+}
+
+func NewSocialIdentityListResponse(identities []*ent.UserIdentity) []*SocialIdentityResponse {
+	result := make([]*SocialIdentityResponse, len(identities))
+	for i, identity := range identities {
+		result[i] = &SocialIdentityResponse{
+			Provider: identity.Provider.ToString(),
+			Email:    identity.Email,
+			LinkedAt: identity.CreatedAt,
+		}
+	}
+	return result
+}
+
+// ------------------- Calendar -------------------
+
+type CalendarTokenResponse struct {
+	Token       string `json:"token"`
+	TeacherPath string `json:"teacherPath"`
+	AcademyPath string `json:"academyPath"`
+}
+
+func NewCalendarTokenResponse(token string) *CalendarTokenResponse {
+	return &CalendarTokenResponse{
+		Token:       token,
+		TeacherPath: "/teacher/me/calendar.ics?token=" + token,
+		AcademyPath: "/academy/me/calendar.ics?token=" + token,
+	}
+}
@@ -2,8 +2,16 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	ex "onthemat/internal/app/common"
@@ -14,73 +22,175 @@ import (
 	"onthemat/internal/app/service/token"
 	"onthemat/internal/app/transport/request"
 	"onthemat/pkg/auth/jwt"
+	"onthemat/pkg/auth/secretbox"
 	"onthemat/pkg/auth/store"
+	"onthemat/pkg/auth/totp"
 	"onthemat/pkg/ent"
+	"onthemat/pkg/oauth"
 
 	"github.com/google/uuid"
 )
 
 type AuthUseCase interface {
 	SignUp(ctx context.Context, body *request.AuthSignUpBody) error
-	Login(ctx context.Context, body *request.AuthLoginBody) (*LoginResult, error)
+	// 2단계 인증을 사용하는 유저는 토큰 대신 challenge 를 받는다. (VerifyTwoFactor)
+	Login(ctx context.Context, body *request.AuthLoginBody, device *SessionDevice) (*LoginResult, *TwoFactorChallenge, error)
 	SocialSignUp(ctx context.Context, body *request.AuthSocialSignUpBody) error
-	SocialLogin(ctx context.Context, socialName string, code string) (result *LoginResult, err error)
-	SocialLoginRedirectUrl(ctx context.Context, socialName string) (url string, err error)
+	// state 는 SocialLoginRedirectUrl 에서 발급한 값으로, 콜백에서 그대로 돌려받아야 한다.
+	SocialLogin(ctx context.Context, socialName string, code string, state string, device *SessionDevice) (result *LoginResult, challenge *TwoFactorChallenge, err error)
+	// state 는 브라우저 쿠키에 함께 심어 콜백을 보낸 브라우저가 로그인을 시작한 브라우저인지 확인한다.
+	SocialLoginRedirectUrl(ctx context.Context, socialName string) (url string, state string, err error)
 
 	Logout(ctx context.Context, userId int, authorizationHeader []byte) (err error)
-	// PasswordChange
-	// 탈퇴
-
-	// 입력받은 이메일로 임시 비밀번호 전송하는 모듈
-	SendEmailResetPassword(ctx context.Context, email string) error
+	// 비밀번호 변경
+	ChangePassword(ctx context.Context, userId int, sessionId string, body *request.AuthPasswordChangeBody) error
+	// 소셜 계정 연결 관리. 업체별로 하나씩 연결할 수 있다.
+	SocialIdentityList(ctx context.Context, userId int) ([]*ent.UserIdentity, error)
+	LinkSocial(ctx context.Context, userId int, socialName string, code string, state string) error
+	// 소셜 로그인 시 같은 이메일의 계정이 있어 받은 연결 토큰으로, 기존 계정에 로그인한 상태에서 연결을 확정한다.
+	ConfirmSocialLink(ctx context.Context, userId int, body *request.AuthSocialLinkConfirmBody) error
+	UnlinkSocial(ctx context.Context, userId int, socialName string) error
+
+	// 2단계 인증(TOTP). 비밀키를 발급받아 인증 앱에 등록한 뒤 코드로 확인하면 켜지고 복구 코드를 받는다.
+	SetupTwoFactor(ctx context.Context, userId int) (*TwoFactorSetupResult, error)
+	// 켜면 현재 세션(sessionId)을 제외한 모든 세션을 폐기한다.
+	ConfirmTwoFactor(ctx context.Context, userId int, sessionId string, body *request.AuthTwoFactorConfirmBody) (recoveryCodes []string, err error)
+	DisableTwoFactor(ctx context.Context, userId int, body *request.AuthTwoFactorCodeBody) error
+	// 로그인에서 받은 challenge 와 인증 앱의 코드(혹은 복구 코드)로 토큰을 발급한다.
+	VerifyTwoFactor(ctx context.Context, body *request.AuthTwoFactorLoginBody, device *SessionDevice) (*LoginResult, error)
+
+	// 탈퇴. 유예 기간 안에 로그인하면 복구 토큰을 받아 계정을 복구할 수 있다.
+	Withdraw(ctx context.Context, userId int) (*WithdrawResult, error)
+	Restore(ctx context.Context, body *request.AuthRestoreBody) error
+	// 유예 기간이 지난 탈퇴 유저의 개인정보를 익명화한다.
+	AnonymizeWithdrawn(ctx context.Context, now time.Time) (int, error)
+
+	// 입력받은 이메일로 비밀번호 재설정 링크 전송
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, body *request.AuthPasswordResetBody) error
 	CheckDuplicatedEmail(ctx context.Context, email string) error
 	VerifiyEmail(ctx context.Context, email string, authKey string, issuedAt string) error
-	Refresh(ctx context.Context, authorizationHeader []byte) (*RefreshResult, error)
+	Refresh(ctx context.Context, authorizationHeader []byte, device *SessionDevice) (*RefreshResult, error)
+	// 다른 서비스가 토큰을 검증할 수 있도록 공개키 목록을 반환한다.
+	JWKS() jwt.JWKSet
+
+	// 로그인된 기기(세션) 관리
+	SessionList(ctx context.Context, userId int) ([]*Session, error)
+	RevokeSession(ctx context.Context, userId int, uuid string) error
+	RevokeAllSessions(ctx context.Context, userId int) error
 }
 
 type authUseCase struct {
-	tokenSvc token.TokenService
-	authSvc  service.AuthService
-	userRepo repository.UserRepository
-	store    store.Store
-	config   *config.Config
+	tokenSvc         token.TokenService
+	authSvc          service.AuthService
+	socials          *oauth.Registry
+	userRepo         repository.UserRepository
+	userIdentityRepo repository.UserIdentityRepository
+	emailOutboxRepo  repository.EmailOutboxRepository
+	store            store.Store
+	sessions         token.SessionChecker
+	totpBox          secretbox.Box
+	config           *config.Config
 }
 
 func NewAuthUseCase(
 	tokenSvc token.TokenService,
 	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	emailOutboxRepo repository.EmailOutboxRepository,
 	authsvc service.AuthService,
+	socials *oauth.Registry,
 	store store.Store,
+	sessions token.SessionChecker,
+	totpBox secretbox.Box,
 	config *config.Config,
 ) AuthUseCase {
 	return &authUseCase{
-		tokenSvc: tokenSvc,
-		authSvc:  authsvc,
-		userRepo: userRepo,
-		store:    store,
-		config:   config,
+		tokenSvc:         tokenSvc,
+		authSvc:          authsvc,
+		socials:          socials,
+		userRepo:         userRepo,
+		userIdentityRepo: userIdentityRepo,
+		emailOutboxRepo:  emailOutboxRepo,
+		store:            store,
+		sessions:         sessions,
+		totpBox:          totpBox,
+		config:           config,
+	}
+}
+
+// 소셜 로그인 요청(state) 유효 시간
+const oauthStateExpired = 10 * time.Minute
+
+// 로그인 페이지로 보내기 전에 저장해두고 콜백에서 한 번만 꺼내 쓴다. (CSRF, 인가 코드 가로채기 방지)
+type oauthState struct {
+	Provider string `json:"provider"`
+	// PKCE code_verifier. 지원하는 업체만 사용한다.
+	CodeVerifier string `json:"codeVerifier,omitempty"`
+}
+
+func oauthStateKey(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return "oauth-state:" + hex.EncodeToString(sum[:])
+}
+
+func (a *authUseCase) socialProvider(socialName string) (oauth.SocialProvider, error) {
+	provider, ok := a.socials.Get(socialName)
+	if !ok {
+		return nil, errors.New("socialName을 확인해주세요")
 	}
+	return provider, nil
 }
 
-func (a *authUseCase) SocialLoginRedirectUrl(ctx context.Context, socialName string) (url string, err error) {
-	if socialName == model.KakaoString {
-		url = a.authSvc.GetKakaoRedirectUrl()
+func (a *authUseCase) SocialLoginRedirectUrl(ctx context.Context, socialName string) (url string, state string, err error) {
+	provider, err := a.socialProvider(socialName)
+	if err != nil {
 		return
 	}
 
-	if socialName == model.GoogleString {
-		url = a.authSvc.GetGoogleRedirectUrl()
+	state, err = newRandomToken()
+	if err != nil {
 		return
 	}
+	saved := &oauthState{Provider: provider.Name()}
+
+	codeChallenge := ""
+	if provider.SupportsPKCE() {
+		if saved.CodeVerifier, codeChallenge, err = oauth.NewCodeVerifier(); err != nil {
+			return
+		}
+	}
 
-	if socialName == model.NaverString {
-		url = a.authSvc.GetNaverRedirectUrl()
+	value, err := json.Marshal(saved)
+	if err != nil {
 		return
 	}
-	err = errors.New("socialName을 확인해주세요")
+	if err = a.store.Set(ctx, oauthStateKey(state), string(value), oauthStateExpired); err != nil {
+		return
+	}
+
+	url = provider.AuthorizeURL(state, codeChallenge)
 	return
 }
 
+// state 는 한 번 사용하면 지워진다. 다른 업체로 발급된 state 는 사용할 수 없다.
+func (a *authUseCase) consumeOAuthState(ctx context.Context, socialName string, state string) (*oauthState, error) {
+	if state == "" {
+		return nil, ex.NewBadRequestError(ex.ErrOAuthStateInvalid, nil)
+	}
+
+	value, err := a.store.GetDel(ctx, oauthStateKey(state))
+	if err != nil {
+		return nil, err
+	}
+
+	saved := new(oauthState)
+	if value == "" || json.Unmarshal([]byte(value), saved) != nil || saved.Provider != socialName {
+		return nil, ex.NewBadRequestError(ex.ErrOAuthStateInvalid, nil)
+	}
+	return saved, nil
+}
+
 type LoginResult struct {
 	AccessToken           string    `json:"accessToken"`
 	AccessTokenExpiredAt  time.Time `json:"accessTokenExpiredAt"`
@@ -88,164 +198,244 @@ type LoginResult struct {
 	RefreshTokenExpiredAt time.Time `json:"refreshTokenExpiredAt"`
 }
 
-func (a *authUseCase) Login(ctx context.Context, body *request.AuthLoginBody) (result *LoginResult, err error) {
-	hashPassword := a.authSvc.HashPassword(body.Password, a.config.Secret.Password)
-	user, err := a.userRepo.GetByEmailPassword(ctx, &ent.User{
-		Email:    &body.Email,
-		Password: &hashPassword,
-	})
+// 없는 이메일일 때도 비밀번호 검증과 비슷한 시간이 걸리도록 사용하는 해시. (응답 시간으로 가입 여부를 알 수 없게)
+const dummyPasswordHash = "$argon2id$v=19$m=65536,t=3,p=4$kv5mdAl2XA9HXaLZO30XWw$jk6JRdAAskQ/RoVuqqAXQtYRpW0MtfiHSGuwnd4L3Mw"
+
+func (a *authUseCase) Login(ctx context.Context, body *request.AuthLoginBody, device *SessionDevice) (result *LoginResult, challenge *TwoFactorChallenge, err error) {
+	targets := a.loginLockTargets(body.Email, device)
+	if err = a.checkLoginLock(ctx, targets); err != nil {
+		return
+	}
+
+	user, err := a.userRepo.GetByEmail(ctx, body.Email)
 	if err != nil {
 		if ent.IsNotFound(err) {
+			a.authSvc.VerifyPassword(dummyPasswordHash, body.Password)
+			a.recordLoginFailure(ctx, targets)
 			err = ex.NewNotFoundError(ex.ErrUserNotFound, "이메일 혹은 비밀번호를 다시 확인해주세요.")
 			return
 		}
 		return
 	}
 
-	if !user.IsEmailVerified {
-		err = ex.NewUnauthorizedError(ex.ErrUserEmailUnauthorization, nil)
+	isMatched, needsRehash := false, false
+	if user.Password != nil {
+		isMatched, needsRehash = a.authSvc.VerifyPassword(*user.Password, body.Password)
+	}
+	if !isMatched {
+		a.recordLoginFailure(ctx, targets)
+		err = ex.NewNotFoundError(ex.ErrUserNotFound, "이메일 혹은 비밀번호를 다시 확인해주세요.")
 		return
 	}
+	a.clearLoginFailure(ctx, targets)
 
-	userType := ""
-
-	if user.Type != nil {
-		userType = *user.Type.ToString()
+	// 기존 SHA-256 해시는 로그인에 성공했을 때 새 방식으로 바꿔 저장한다. 실패해도 다음 로그인에서 다시 시도하므로 로그인은 막지 않는다.
+	if needsRehash {
+		if hashPassword, errH := a.authSvc.HashPassword(body.Password); errH == nil {
+			a.userRepo.UpdatePassword(ctx, user.ID, hashPassword)
+		}
 	}
 
-	// 토큰 발행
-	uid := uuid.New().String()
-	refresh, err := a.tokenSvc.GenerateToken(uid, user.ID, "normal", userType, a.config.JWT.RefreshTokenExpired)
-	if err != nil {
+	if user.DeletedAt != nil {
+		err = a.withdrawnError(ctx, user)
 		return
 	}
 
-	if err = a.store.HSet(ctx, strconv.Itoa(user.ID), uid, strconv.Itoa(user.ID), time.Duration(a.config.JWT.RefreshTokenExpired)*time.Minute); err != nil {
+	if !user.IsEmailVerified {
+		err = ex.NewUnauthorizedError(ex.ErrUserEmailUnauthorization, nil)
 		return
 	}
 
-	access, err := a.tokenSvc.GenerateToken(uid, user.ID, "normal", userType, a.config.JWT.AccessTokenExpired)
-	if err != nil {
-		return
-	}
+	userType := ""
 
-	result = &LoginResult{
-		AccessToken:           access,
-		AccessTokenExpiredAt:  a.tokenSvc.GetExpiredAt(a.config.JWT.AccessTokenExpired),
-		RefreshToken:          refresh,
-		RefreshTokenExpiredAt: a.tokenSvc.GetExpiredAt(a.config.JWT.RefreshTokenExpired),
+	if user.Type != nil {
+		userType = *user.Type.ToString()
 	}
 
-	return
-}
-
-func (a *authUseCase) SocialLogin(ctx context.Context, socialName string, code string) (result *LoginResult, err error) {
-	if socialName != model.KakaoString && socialName != model.GoogleString && socialName != model.NaverString {
-		err = errors.New("socialName을 확인해주세요")
+	if user.TotpEnabledAt != nil {
+		challenge, err = a.newTwoFactorChallenge(ctx, user.ID, userType, "normal")
 		return
 	}
 
-	user := new(ent.User)
+	// 토큰 발행
+	result, err = a.issueTokens(ctx, user.ID, userType, newSession(uuid.New().String(), "normal", device), "")
+	return
+}
 
-	if socialName == model.KakaoString {
-		kakaoInfo, errA := a.authSvc.GetKakaoInfo(code)
-		if errA != nil {
-			err = errA
-			return
-		}
+// 로그인 실패를 세는 대상. 이메일을 바꿔가며 시도하는 경우도 막도록 IP 별로도 센다.
+type loginLockTarget struct {
+	key   string
+	limit int
+}
 
-		kakaoId := strconv.FormatUint(uint64(kakaoInfo.Id), 10)
+func (a *authUseCase) loginLockTargets(email string, device *SessionDevice) []loginLockTarget {
+	rl := a.config.RateLimit
 
-		user.SocialKey = &kakaoId
-		user.Email = kakaoInfo.KakaoAccount.Email
-		user.Nickname = &kakaoInfo.KakaoAccount.Profile.NickName
-		user.SocialName = &model.KakaoSocialType
+	var targets []loginLockTarget
+	if rl.LoginFailEmailLimit > 0 {
+		targets = append(targets, loginLockTarget{"email:" + strings.ToLower(strings.TrimSpace(email)), rl.LoginFailEmailLimit})
+	}
+	if rl.LoginFailIpLimit > 0 && device != nil && device.Ip != "" {
+		targets = append(targets, loginLockTarget{"ip:" + device.Ip, rl.LoginFailIpLimit})
+	}
+	return targets
+}
 
-	} else if socialName == model.GoogleString {
-		googleInfo, errA := a.authSvc.GetGoogleInfo(code)
-		if errA != nil {
-			err = errA
-			return
+// 잠겨 있으면 풀릴 때까지 남은 시간(초)을 담아 429 를 반환한다.
+func (a *authUseCase) checkLoginLock(ctx context.Context, targets []loginLockTarget) error {
+	now := time.Now().Unix()
+	for _, target := range targets {
+		// 잠금이 풀리는 시각(unix)이 저장되어 있다.
+		unlockAt, _ := strconv.ParseInt(a.store.Get(ctx, "login-lock:"+target.key), 10, 64)
+		if unlockAt > now {
+			return ex.NewTooManyRequestsError(ex.ErrLoginLocked, map[string]int64{"retryAfter": unlockAt - now})
 		}
+	}
+	return nil
+}
 
-		googleId := googleInfo.Sub
-		user.SocialKey = &googleId
-		user.Email = &googleInfo.Email
-		user.Nickname = &googleInfo.Nickname
-		user.SocialName = &model.GoogleSocialType
+// 실패 횟수가 기준에 닿으면 잠그고 횟수는 처음부터 다시 센다.
+// 저장에 실패해도 로그인 결과는 바꾸지 않는다.
+func (a *authUseCase) recordLoginFailure(ctx context.Context, targets []loginLockTarget) {
+	rl := a.config.RateLimit
+	for _, target := range targets {
+		failKey := "login-fail:" + target.key
+		count, err := a.store.Incr(ctx, failKey, time.Duration(rl.LoginFailWindow)*time.Minute)
+		if err != nil || count < int64(target.limit) {
+			continue
+		}
 
-	} else if socialName == model.NaverString {
+		lockDuration := time.Duration(rl.LoginLockDuration) * time.Minute
+		unlockAt := time.Now().Add(lockDuration).Unix()
+		a.store.Set(ctx, "login-lock:"+target.key, strconv.FormatInt(unlockAt, 10), lockDuration)
+		a.store.Del(ctx, failKey)
+	}
+}
 
-		naverInfo, errA := a.authSvc.GetNaverInfo(code)
-		if errA != nil {
-			err = errA
-			return
+// 비밀번호가 맞으면 이메일의 실패 횟수만 지운다. IP 는 다른 계정으로 시도한 실패가 섞여 있으므로 기간이 지나야 없어진다.
+func (a *authUseCase) clearLoginFailure(ctx context.Context, targets []loginLockTarget) {
+	for _, target := range targets {
+		if strings.HasPrefix(target.key, "email:") {
+			a.store.Del(ctx, "login-fail:"+target.key)
 		}
-		user.Nickname = &naverInfo.NickName
-		user.SocialKey = &naverInfo.Id
-		user.Email = &naverInfo.Email
-		user.SocialName = &model.NaverSocialType
+	}
+}
+
+func (a *authUseCase) SocialLogin(ctx context.Context, socialName string, code string, state string, device *SessionDevice) (result *LoginResult, challenge *TwoFactorChallenge, err error) {
+	profile, err := a.getSocialProfile(ctx, socialName, code, state)
+	if err != nil {
+		return
 	}
 
-	// 이미 존재하는 회원인지 확인.
-	checkedUser, err := a.userRepo.GetBySocialKey(ctx, user)
+	// 이미 연결된 소셜 계정인지 확인.
+	checkedUser, err := a.userIdentityRepo.GetUser(ctx, profile.Provider, profile.ProviderKey)
 	if err != nil && !ent.IsNotFound(err) {
 		return
 	}
 
 	// 유저가 없으면 회원 정보 생성
 	if checkedUser == nil {
-
-		user.TermAgreeAt = time.Now()
-		checkedUser, err = a.userRepo.Create(ctx, user)
-		if ent.IsConstraintError(err) {
-
-			checkedUser, err = a.userRepo.GetByEmail(ctx, *user.Email)
-			if err != nil {
+		// 같은 이메일로 가입된 계정이 있어도 자동으로 연결하지 않는다. (이메일만으로 계정을 가져가는 것을 막기 위해)
+		// 기존 계정으로 로그인한 뒤 연결 토큰으로 직접 확인해야 연결된다.
+		if profile.Email != nil {
+			existing, errE := a.userRepo.GetByEmail(ctx, *profile.Email)
+			if errE != nil && !ent.IsNotFound(errE) {
+				err = errE
 				return
 			}
-
-			user.ID = checkedUser.ID
-			err = a.userRepo.UpdateSocialKey(ctx, user)
-			if err != nil {
+			if existing != nil {
+				err = a.socialLinkRequiredError(ctx, existing.ID, profile)
 				return
 			}
-			err = nil
 		}
+
+		checkedUser, err = a.userRepo.CreateWithIdentity(ctx, &ent.User{
+			Email:       profile.Email,
+			Nickname:    profile.Nickname,
+			TermAgreeAt: time.Now(),
+		}, profile.identity(0))
 		if err != nil {
+			if ent.IsConstraintError(err) {
+				err = ex.NewConflictError(ex.ErrUserEmailAlreadyExist, nil)
+			}
 			return
 		}
 	}
-	user.ID = checkedUser.ID
+
+	if checkedUser.DeletedAt != nil {
+		err = a.withdrawnError(ctx, checkedUser)
+		return
+	}
 
 	userType := ""
 	if checkedUser.Type != nil {
 		userType = *checkedUser.Type.ToString()
 	}
 
+	if checkedUser.TotpEnabledAt != nil {
+		challenge, err = a.newTwoFactorChallenge(ctx, checkedUser.ID, userType, socialName)
+		return
+	}
+
 	// 토큰 발행
-	uid := uuid.New().String()
-	refresh, err := a.tokenSvc.GenerateToken(uid, user.ID, socialName, userType, a.config.JWT.RefreshTokenExpired)
+	result, err = a.issueTokens(ctx, checkedUser.ID, userType, newSession(uuid.New().String(), socialName, device), "")
+	return
+}
+
+// 소셜 로그인 업체에서 받은 유저 정보
+type socialProfile struct {
+	Provider    model.SocialType `json:"provider"`
+	ProviderKey string           `json:"providerKey"`
+	Email       *string          `json:"email"`
+	// 가입할 때만 사용한다.
+	Nickname *string `json:"-"`
+}
+
+func (p *socialProfile) identity(userId int) *ent.UserIdentity {
+	return &ent.UserIdentity{
+		UserID:      userId,
+		Provider:    p.Provider,
+		ProviderKey: p.ProviderKey,
+		Email:       p.Email,
+	}
+}
+
+// state 를 확인한 뒤 인가 코드로 소셜 로그인 업체의 유저 정보를 조회한다.
+func (a *authUseCase) getSocialProfile(ctx context.Context, socialName string, code string, state string) (*socialProfile, error) {
+	provider, err := a.socialProvider(socialName)
 	if err != nil {
-		return
+		return nil, err
+	}
+	socialType, ok := model.SocialTypeFromString(provider.Name())
+	if !ok {
+		return nil, errors.New("socialName을 확인해주세요")
 	}
 
-	if err = a.store.HSet(ctx, strconv.Itoa(user.ID), uid, strconv.Itoa(user.ID), time.Duration(a.config.JWT.RefreshTokenExpired)*time.Minute); err != nil {
-		return
+	saved, err := a.consumeOAuthState(ctx, socialName, state)
+	if err != nil {
+		return nil, err
 	}
 
-	access, err := a.tokenSvc.GenerateToken(uid, user.ID, socialName, userType, a.config.JWT.AccessTokenExpired)
+	token, err := provider.ExchangeCode(code, state, saved.CodeVerifier)
 	if err != nil {
-		return
+		return nil, err
+	}
+	info, err := provider.FetchProfile(token)
+	if err != nil {
+		return nil, err
 	}
 
-	result = &LoginResult{
-		AccessToken:           access,
-		AccessTokenExpiredAt:  a.tokenSvc.GetExpiredAt(a.config.JWT.AccessTokenExpired),
-		RefreshToken:          refresh,
-		RefreshTokenExpiredAt: a.tokenSvc.GetExpiredAt(a.config.JWT.RefreshTokenExpired),
+	profile := &socialProfile{
+		Provider:    socialType,
+		ProviderKey: info.Key,
 	}
-	return
+	if info.Email != "" {
+		profile.Email = &info.Email
+	}
+	if info.Nickname != "" {
+		profile.Nickname = &info.Nickname
+	}
+	return profile, nil
 }
 
 func (a *authUseCase) SocialSignUp(ctx context.Context, body *request.AuthSocialSignUpBody) (err error) {
@@ -273,15 +463,37 @@ func (a *authUseCase) SocialSignUp(ctx context.Context, body *request.AuthSocial
 }
 
 func (a *authUseCase) SignUp(ctx context.Context, body *request.AuthSignUpBody) (err error) {
-	hashPassword := a.authSvc.HashPassword(body.Password, a.config.Secret.Password)
+	hashPassword, err := a.authSvc.HashPassword(body.Password)
+	if err != nil {
+		return
+	}
+
+	// 인증 메일은 유저와 같은 트랜잭션으로 outbox 에 쌓고, 스케줄러가 보낸다.
+	key := a.authSvc.GenerateRandomString()
+	verifyUrl := a.config.Onthemat.HOST + "/api/v1/auth/verify-email?" + url.Values{
+		"key":      {key},
+		"email":    {body.Email},
+		"issuedAt": {time.Now().Format(time.RFC3339)},
+	}.Encode()
+
+	// 메일이 커밋된 뒤에 키 저장이 실패하면 열 수 없는 인증 메일이 나가므로 커밋 전에 먼저 저장한다.
+	// 키마다 따로 저장하므로 가입에 실패해도 다른 유저의 인증키를 덮어쓰지 않는다.
+	if err = a.store.Set(ctx, emailVerifyKey(key), body.Email, emailVerifyKeyExpired); err != nil {
+		return
+	}
 
-	_, err = a.userRepo.Create(ctx, &ent.User{
+	_, err = a.userRepo.CreateWithEmail(ctx, &ent.User{
 		Email:       &body.Email,
 		Password:    &hashPassword,
 		Nickname:    &body.NickName,
 		TermAgreeAt: time.Now(),
+	}, &ent.EmailOutbox{
+		Recipient: body.Email,
+		Template:  service.MailVerifyEmail,
+		Data:      map[string]string{"verifyUrl": verifyUrl},
 	})
 	if err != nil {
+		a.store.Del(ctx, emailVerifyKey(key))
 		if ent.IsConstraintError(err) {
 			err = ex.NewConflictError(ex.ErrUserEmailAlreadyExist, nil)
 			return
@@ -289,15 +501,16 @@ func (a *authUseCase) SignUp(ctx context.Context, body *request.AuthSignUpBody)
 		return
 	}
 
-	// 이메일 인증을 위한 키 store에 저장.
-	key := a.authSvc.GenerateRandomString()
-	if err = a.store.Set(ctx, body.Email, key, time.Duration(time.Hour*24)); err != nil {
-		return
-	}
+	return
+}
 
-	go a.authSvc.SendEmailVerifiedUser(body.Email, key, time.Now().Format(time.RFC3339), a.config.Onthemat.HOST)
+// 이메일 인증키 유효 시간
+const emailVerifyKeyExpired = 24 * time.Hour
 
-	return
+// store 에는 인증키 원문이 아닌 해시를 key 로, 인증할 이메일을 값으로 저장한다.
+func emailVerifyKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "email-verify:" + hex.EncodeToString(sum[:])
 }
 
 func (a *authUseCase) CheckDuplicatedEmail(ctx context.Context, email string) (err error) {
@@ -319,9 +532,7 @@ func (a *authUseCase) VerifiyEmail(ctx context.Context, email string, authKey st
 		return
 	}
 
-	key := a.store.Get(ctx, email)
-
-	if key != authKey {
+	if authKey == "" || a.store.Get(ctx, emailVerifyKey(authKey)) != email {
 		err = ex.NewBadRequestError(ex.ErrRandomKeyForEmailVerfiyUnavailable, nil)
 		return
 	}
@@ -345,7 +556,7 @@ func (a *authUseCase) VerifiyEmail(ctx context.Context, email string, authKey st
 		return
 	}
 
-	if err = a.store.Del(ctx, email); err != nil {
+	if err = a.store.Del(ctx, emailVerifyKey(authKey)); err != nil {
 		err = ex.NewConflictError(ex.ErrUserEmailAlreadyVerfied, nil)
 		return
 	}
@@ -353,38 +564,133 @@ func (a *authUseCase) VerifiyEmail(ctx context.Context, email string, authKey st
 	return
 }
 
-func (a *authUseCase) SendEmailResetPassword(ctx context.Context, email string) (err error) {
-	isExist, err := a.userRepo.FindByEmail(ctx, email)
+// 비밀번호 재설정 토큰 유효 시간
+const passwordResetTokenExpired = 30 * time.Minute
+
+// store 에는 토큰 원문이 아닌 해시를 key 로 저장한다.
+func passwordResetKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "password-reset:" + hex.EncodeToString(sum[:])
+}
+
+func newRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// 일회용 재설정 토큰을 발급해 링크를 메일로 보낸다.
+// 가입 여부를 알 수 없도록 없는 이메일, 탈퇴한 유저에도 메일을 보내지 않고 성공으로 응답한다.
+func (a *authUseCase) RequestPasswordReset(ctx context.Context, email string) (err error) {
+	u, err := a.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+	if u.DeletedAt != nil {
+		return
+	}
+
+	resetToken, err := newRandomToken()
+	if err != nil {
+		return
+	}
+
+	if err = a.store.Set(ctx, passwordResetKey(resetToken), strconv.Itoa(u.ID), passwordResetTokenExpired); err != nil {
+		return
+	}
+
+	resetUrl := a.config.Onthemat.PasswordResetUrl
+	if resetUrl == "" {
+		resetUrl = a.config.Onthemat.HOST + "/password/reset"
+	}
+	_, err = a.emailOutboxRepo.Create(ctx, &ent.EmailOutbox{
+		Recipient: email,
+		Template:  service.MailPasswordReset,
+		Data:      map[string]string{"resetUrl": resetUrl + "?token=" + resetToken},
+	})
+
+	return
+}
+
+// 토큰은 한 번 사용하면 지워진다. 비밀번호가 바뀌면 모든 기기에서 로그아웃된다.
+func (a *authUseCase) ResetPassword(ctx context.Context, body *request.AuthPasswordResetBody) (err error) {
+	userIdString, err := a.store.GetDel(ctx, passwordResetKey(body.Token))
 	if err != nil {
 		return
 	}
+	userId, errA := strconv.Atoi(userIdString)
+	if userIdString == "" || errA != nil {
+		err = ex.NewBadRequestError(ex.ErrPasswordResetTokenInvalid, nil)
+		return
+	}
 
-	if !isExist {
-		err = ex.NewNotFoundError(ex.ErrUserNotFound, "존재하지 않는 이메일입니다.")
+	if err = a.updatePassword(ctx, userId, body.Password); err != nil {
 		return
 	}
 
-	hashPassword := a.authSvc.HashPassword(a.authSvc.GenerateRandomPassword(), a.config.Secret.Password)
+	return a.RevokeAllSessions(ctx, userId)
+}
+
+// 현재 비밀번호를 확인하고 바꾼다. 요청한 기기를 제외한 모든 기기에서 로그아웃된다.
+func (a *authUseCase) ChangePassword(ctx context.Context, userId int, sessionId string, body *request.AuthPasswordChangeBody) (err error) {
+	u, err := a.userRepo.Get(ctx, userId)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+			return
+		}
+		return
+	}
 
-	u := &ent.User{
-		Email:        &email,
-		TempPassword: &hashPassword,
+	if u.Password == nil {
+		err = ex.NewBadRequestError(ex.ErrPasswordMismatch, nil)
+		return
+	}
+	if isMatched, _ := a.authSvc.VerifyPassword(*u.Password, body.CurrentPassword); !isMatched {
+		err = ex.NewBadRequestError(ex.ErrPasswordMismatch, nil)
+		return
 	}
-	if err = a.userRepo.UpdateTempPassword(ctx, u); err != nil {
+
+	if err = a.updatePassword(ctx, userId, body.NewPassword); err != nil {
 		return
 	}
 
-	go a.authSvc.SendEmailResetPassword(u)
+	return a.revokeOtherSessions(ctx, userId, sessionId)
+}
+
+func (a *authUseCase) updatePassword(ctx context.Context, userId int, password string) (err error) {
+	hashPassword, err := a.authSvc.HashPassword(password)
+	if err != nil {
+		return
+	}
 
+	if err = a.userRepo.UpdatePassword(ctx, userId, hashPassword); err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+			return
+		}
+		return
+	}
 	return
 }
 
 type RefreshResult struct {
-	AccessToken          string    `json:"accessToken"`
-	AccessTokenExpiredAt time.Time `json:"accessTokenExpiredAt"`
+	AccessToken           string    `json:"accessToken"`
+	AccessTokenExpiredAt  time.Time `json:"accessTokenExpiredAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiredAt time.Time `json:"refreshTokenExpiredAt"`
 }
 
-func (a *authUseCase) Refresh(ctx context.Context, authorizationHeader []byte) (result *RefreshResult, err error) {
+// 리프레쉬 토큰은 사용할 때마다 새로 발급(rotation)한다.
+// 토큰의 uuid 는 로그인 한 번으로 시작되는 토큰 패밀리(세션)이고, store 에는 패밀리별로 마지막으로 발급한 토큰 아이디만 남긴다.
+// 이미 교체된 토큰이 다시 사용되면 탈취된 것으로 보고 패밀리 전체를 폐기한다.
+
+func (a *authUseCase) Refresh(ctx context.Context, authorizationHeader []byte, device *SessionDevice) (result *RefreshResult, err error) {
 	refreshToken, err := a.authSvc.ExtractTokenFromHeader(string(authorizationHeader))
 	if err != nil {
 		err = ex.NewBadRequestError(ex.ErrAuthorizationHeaderFormatUnavailable, "Bearer")
@@ -406,12 +712,21 @@ func (a *authUseCase) Refresh(ctx context.Context, authorizationHeader []byte) (
 	}
 
 	userIdString := strconv.Itoa(claim.UserId)
-	val, err := a.store.HGet(ctx, userIdString, claim.Uuid)
-	if err != nil {
+	value, _ := a.store.HGet(ctx, userIdString, claim.Uuid)
+	if value == "" {
+		// 로그아웃, 폐기된 패밀리
+		err = ex.NewBadRequestError(ex.ErrTokenInvalid, nil)
 		return
 	}
-	if val != userIdString {
-		err = ex.NewBadRequestError(ex.ErrTokenInvalid, nil)
+	session := parseSession(claim.Uuid, value)
+
+	// 토큰 아이디가 없는 기존 토큰은 store 에 유저 아이디가 저장되어 있다.
+	isLegacy := claim.ID == "" && session.TokenId == userIdString
+	if !isLegacy && session.TokenId != claim.ID {
+		if err = a.revokeSession(ctx, claim.UserId, claim.Uuid); err != nil {
+			return
+		}
+		err = ex.NewUnauthorizedError(ex.ErrRefreshTokenReused, nil)
 		return
 	}
 
@@ -425,30 +740,89 @@ func (a *authUseCase) Refresh(ctx context.Context, authorizationHeader []byte) (
 
 	}
 
-	uid := uuid.New().String()
-
 	userType := ""
 	if u.Type != nil {
 		userType = *u.Type.ToString()
 	}
 
-	loginType := "normal"
-	if u.SocialName != nil {
-		loginType = *u.SocialName.ToString()
+	if session.LoginType == "" {
+		session.LoginType = "normal"
+		if u.Password == nil {
+			if identities, errI := a.userIdentityRepo.List(ctx, u.ID); errI == nil && len(identities) > 0 {
+				session.LoginType = *identities[0].Provider.ToString()
+			}
+		}
 	}
+	session.touch(device)
 
-	access, err := a.tokenSvc.GenerateToken(uid, u.ID, loginType, userType, a.config.JWT.AccessTokenExpired)
+	// 동시에 같은 토큰으로 갱신하면 store 의 값이 그대로인 요청 하나만 교체에 성공한다.
+	tokens, err := a.issueTokens(ctx, u.ID, userType, session, value)
 	if err != nil {
 		return
 	}
 
 	result = &RefreshResult{
-		AccessToken:          access,
-		AccessTokenExpiredAt: a.tokenSvc.GetExpiredAt(a.config.JWT.AccessTokenExpired),
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiredAt:  tokens.AccessTokenExpiredAt,
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiredAt: tokens.RefreshTokenExpiredAt,
+	}
+	return
+}
+
+// 세션(토큰 패밀리)에 새 리프레쉬 토큰을 발급하고 store 의 마지막 토큰 아이디와 기기 정보를 교체한다.
+// previous 가 있으면 store 의 값이 previous 와 같을 때만 교체한다. (갱신)
+func (a *authUseCase) issueTokens(ctx context.Context, userId int, userType string, session *Session, previous string) (result *LoginResult, err error) {
+	session.TokenId = uuid.New().String()
+	refresh, err := a.tokenSvc.GenerateRefreshToken(session.Uuid, session.TokenId, userId, session.LoginType, userType, a.config.JWT.RefreshTokenExpired)
+	if err != nil {
+		return
+	}
+
+	value, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+
+	expiration := time.Duration(a.config.JWT.RefreshTokenExpired) * time.Minute
+	if previous == "" {
+		if err = a.store.HSet(ctx, strconv.Itoa(userId), session.Uuid, string(value), expiration); err != nil {
+			return
+		}
+	} else {
+		swapped, errS := a.store.HSetIfEqual(ctx, strconv.Itoa(userId), session.Uuid, previous, string(value), expiration)
+		if errS != nil {
+			err = errS
+			return
+		}
+		if !swapped {
+			// 그 사이 다른 요청이 먼저 교체했으면 같은 토큰을 두 번 쓴 것으로 본다.
+			if err = a.revokeSession(ctx, userId, session.Uuid); err != nil {
+				return
+			}
+			err = ex.NewUnauthorizedError(ex.ErrRefreshTokenReused, nil)
+			return
+		}
+	}
+
+	access, err := a.tokenSvc.GenerateToken(session.Uuid, userId, session.LoginType, userType, session.TwoFactor, a.config.JWT.AccessTokenExpired)
+	if err != nil {
+		return
+	}
+
+	result = &LoginResult{
+		AccessToken:           access,
+		AccessTokenExpiredAt:  a.tokenSvc.GetExpiredAt(a.config.JWT.AccessTokenExpired),
+		RefreshToken:          refresh,
+		RefreshTokenExpiredAt: a.tokenSvc.GetExpiredAt(a.config.JWT.RefreshTokenExpired),
 	}
 	return
 }
 
+func (a *authUseCase) JWKS() jwt.JWKSet {
+	return a.tokenSvc.JWKS()
+}
+
 func (a *authUseCase) Logout(ctx context.Context, userId int, authorizationHeader []byte) (err error) {
 	refreshToken, err := a.authSvc.ExtractTokenFromHeader(string(authorizationHeader))
 	if err != nil {
@@ -458,10 +832,625 @@ func (a *authUseCase) Logout(ctx context.Context, userId int, authorizationHeade
 	claim := new(token.TokenClaim)
 	a.tokenSvc.ParseToken(refreshToken, claim)
 
-	err = a.store.HDel(ctx, strconv.Itoa(userId), claim.Uuid)
+	err = a.revokeSession(ctx, userId, claim.Uuid)
 	if err != nil {
 		return
 	}
 
 	return
 }
+
+// 로그인 요청을 보낸 기기 정보
+type SessionDevice struct {
+	UserAgent string
+	Ip        string
+}
+
+// 유저 agent 는 클라이언트가 임의로 보낼 수 있으므로 저장 길이를 제한한다.
+const maxUserAgentLength = 255
+
+// 로그인 한 번으로 시작되는 세션(토큰 패밀리). store 의 유저별 해시에 uuid 를 필드로 JSON 으로 저장된다.
+type Session struct {
+	Uuid       string    `json:"-"`
+	TokenId    string    `json:"tokenId"`
+	UserAgent  string    `json:"userAgent"`
+	Ip         string    `json:"ip"`
+	LoginType  string    `json:"loginType"`
+	TwoFactor  bool      `json:"twoFactor,omitempty"` // 2단계 인증을 거쳐 로그인한 세션. 리프레쉬해도 유지된다.
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+func newSession(uuid string, loginType string, device *SessionDevice) *Session {
+	now := time.Now()
+	s := &Session{
+		Uuid:      uuid,
+		LoginType: loginType,
+		CreatedAt: now,
+	}
+	s.touch(device)
+	return s
+}
+
+// 마지막 사용 시각과 기기 정보를 갱신한다.
+func (s *Session) touch(device *SessionDevice) {
+	s.LastUsedAt = time.Now()
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = s.LastUsedAt
+	}
+	if device == nil {
+		return
+	}
+	if device.UserAgent != "" {
+		s.UserAgent = device.UserAgent
+		if len(s.UserAgent) > maxUserAgentLength {
+			s.UserAgent = strings.ToValidUTF8(s.UserAgent[:maxUserAgentLength], "")
+		}
+	}
+	if device.Ip != "" {
+		s.Ip = device.Ip
+	}
+}
+
+// 기기 정보가 없던 기존 세션은 토큰 아이디(혹은 유저 아이디)만 문자열로 저장되어 있다.
+func parseSession(uuid string, value string) *Session {
+	s := new(Session)
+	if !strings.HasPrefix(value, "{") || json.Unmarshal([]byte(value), s) != nil {
+		s = &Session{TokenId: value}
+	}
+	s.Uuid = uuid
+	return s
+}
+
+// 리프레쉬 토큰 만료 시간이 지난 세션은 목록에서 제외하고 store 에서도 지운다.
+func (a *authUseCase) SessionList(ctx context.Context, userId int) (result []*Session, err error) {
+	userIdString := strconv.Itoa(userId)
+	values, err := a.store.HGetAll(ctx, userIdString)
+	if err != nil {
+		return
+	}
+
+	expired := time.Duration(a.config.JWT.RefreshTokenExpired) * time.Minute
+	result = make([]*Session, 0, len(values))
+	for uuid, value := range values {
+		s := parseSession(uuid, value)
+		if !s.LastUsedAt.IsZero() && time.Since(s.LastUsedAt) > expired {
+			if err = a.revokeSession(ctx, userId, uuid); err != nil {
+				return
+			}
+			continue
+		}
+		result = append(result, s)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastUsedAt.After(result[j].LastUsedAt)
+	})
+	return
+}
+
+func (a *authUseCase) RevokeSession(ctx context.Context, userId int, uuid string) (err error) {
+	userIdString := strconv.Itoa(userId)
+	value, _ := a.store.HGet(ctx, userIdString, uuid)
+	if value == "" {
+		err = ex.NewNotFoundError(ex.ErrSessionNotFound, nil)
+		return
+	}
+	return a.revokeSession(ctx, userId, uuid)
+}
+
+// 모든 기기에서 로그아웃
+func (a *authUseCase) RevokeAllSessions(ctx context.Context, userId int) error {
+	if err := a.store.Del(ctx, strconv.Itoa(userId)); err != nil {
+		return err
+	}
+	a.sessions.ForgetUser(userId)
+	return nil
+}
+
+// 현재 세션(keepUuid)을 제외한 모든 세션을 폐기한다.
+func (a *authUseCase) revokeOtherSessions(ctx context.Context, userId int, keepUuid string) error {
+	values, err := a.store.HGetAll(ctx, strconv.Itoa(userId))
+	if err != nil {
+		return err
+	}
+	for uuid := range values {
+		if uuid == keepUuid {
+			continue
+		}
+		if err := a.revokeSession(ctx, userId, uuid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 세션을 지우고 이 인스턴스의 세션 캐시에도 바로 반영한다. 이후 해당 세션의 엑세스 토큰은 거부된다.
+func (a *authUseCase) revokeSession(ctx context.Context, userId int, uuid string) error {
+	if err := a.store.HDel(ctx, strconv.Itoa(userId), uuid); err != nil {
+		return err
+	}
+	a.sessions.Forget(userId, uuid)
+	return nil
+}
+
+const restoreTokenExpired = 10 * time.Minute
+
+type WithdrawResult struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	// 이 일시까지 복구할 수 있다. 이후 개인정보가 익명화된다.
+	RestorableUntil time.Time `json:"restorableUntil"`
+}
+
+// 탈퇴한 유저가 로그인하면 에러 details 로 내려준다.
+type WithdrawnDetail struct {
+	RestoreToken    string    `json:"restoreToken"`
+	RestorableUntil time.Time `json:"restorableUntil"`
+}
+
+func restoreKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "user-restore:" + hex.EncodeToString(sum[:])
+}
+
+func (a *authUseCase) withdrawalGracePeriod() time.Duration {
+	return time.Duration(a.config.Onthemat.WithdrawalGraceDays) * 24 * time.Hour
+}
+
+// 유저 행은 지우지 않고 탈퇴 일시만 기록한다. 학원의 지난 공고와 선생님의 합격 이력은 그대로 남는다.
+// 모든 기기에서 로그아웃되며, 개인정보는 유예 기간이 지난 뒤 익명화된다.
+func (a *authUseCase) Withdraw(ctx context.Context, userId int) (result *WithdrawResult, err error) {
+	now := time.Now()
+	if err = a.userRepo.Withdraw(ctx, userId, now); err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+			return
+		}
+		return
+	}
+
+	if err = a.RevokeAllSessions(ctx, userId); err != nil {
+		return
+	}
+
+	result = &WithdrawResult{
+		DeletedAt:       now,
+		RestorableUntil: now.Add(a.withdrawalGracePeriod()),
+	}
+	return
+}
+
+// 로그인은 성공했지만 탈퇴한 유저이면 복구 토큰을 발급해 403 에러로 내려준다.
+func (a *authUseCase) withdrawnError(ctx context.Context, u *ent.User) error {
+	restoreToken, err := newRandomToken()
+	if err != nil {
+		return err
+	}
+
+	if err := a.store.Set(ctx, restoreKey(restoreToken), strconv.Itoa(u.ID), restoreTokenExpired); err != nil {
+		return err
+	}
+
+	return ex.NewForbiddenError(ex.ErrUserWithdrawn, &WithdrawnDetail{
+		RestoreToken:    restoreToken,
+		RestorableUntil: u.DeletedAt.Add(a.withdrawalGracePeriod()),
+	})
+}
+
+// 복구 토큰은 한 번 사용하면 지워진다. 복구 후 다시 로그인해야 한다.
+func (a *authUseCase) Restore(ctx context.Context, body *request.AuthRestoreBody) (err error) {
+	userIdString, err := a.store.GetDel(ctx, restoreKey(body.Token))
+	if err != nil {
+		return
+	}
+	userId, errA := strconv.Atoi(userIdString)
+	if userIdString == "" || errA != nil {
+		err = ex.NewBadRequestError(ex.ErrRestoreTokenInvalid, nil)
+		return
+	}
+
+	if err = a.userRepo.Restore(ctx, userId); err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+			return
+		}
+		return
+	}
+	return
+}
+
+func (a *authUseCase) AnonymizeWithdrawn(ctx context.Context, now time.Time) (int, error) {
+	return a.userRepo.AnonymizeWithdrawn(ctx, now.Add(-a.withdrawalGracePeriod()), now)
+}
+
+const socialLinkTokenExpired = 10 * time.Minute
+
+// 소셜 로그인 시 같은 이메일로 가입된 계정이 있으면 에러 details 로 내려준다.
+type SocialLinkDetail struct {
+	LinkToken string  `json:"linkToken"`
+	Provider  string  `json:"provider"`
+	Email     *string `json:"email"`
+}
+
+// 연결 확인을 기다리는 소셜 계정
+type pendingSocialLink struct {
+	UserId  int            `json:"userId"`
+	Profile *socialProfile `json:"profile"`
+}
+
+func socialLinkKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "social-link:" + hex.EncodeToString(sum[:])
+}
+
+func (a *authUseCase) socialLinkRequiredError(ctx context.Context, userId int, profile *socialProfile) error {
+	linkToken, err := newRandomToken()
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(&pendingSocialLink{UserId: userId, Profile: profile})
+	if err != nil {
+		return err
+	}
+
+	if err := a.store.Set(ctx, socialLinkKey(linkToken), string(value), socialLinkTokenExpired); err != nil {
+		return err
+	}
+
+	return ex.NewConflictError(ex.ErrSocialLinkRequired, &SocialLinkDetail{
+		LinkToken: linkToken,
+		Provider:  *profile.Provider.ToString(),
+		Email:     profile.Email,
+	})
+}
+
+func (a *authUseCase) SocialIdentityList(ctx context.Context, userId int) ([]*ent.UserIdentity, error) {
+	return a.userIdentityRepo.List(ctx, userId)
+}
+
+// 로그인한 상태에서 인가 코드로 소셜 계정을 연결한다.
+func (a *authUseCase) LinkSocial(ctx context.Context, userId int, socialName string, code string, state string) (err error) {
+	profile, err := a.getSocialProfile(ctx, socialName, code, state)
+	if err != nil {
+		return
+	}
+	return a.linkIdentity(ctx, userId, profile)
+}
+
+// 연결 토큰은 한 번 사용하면 지워진다. 토큰을 받은 계정으로 로그인한 상태에서만 연결할 수 있다.
+func (a *authUseCase) ConfirmSocialLink(ctx context.Context, userId int, body *request.AuthSocialLinkConfirmBody) (err error) {
+	value, err := a.store.GetDel(ctx, socialLinkKey(body.LinkToken))
+	if err != nil {
+		return
+	}
+
+	pending := new(pendingSocialLink)
+	if value == "" || json.Unmarshal([]byte(value), pending) != nil || pending.Profile == nil {
+		err = ex.NewBadRequestError(ex.ErrSocialLinkTokenInvalid, nil)
+		return
+	}
+
+	if pending.UserId != userId {
+		err = ex.NewForbiddenError(ex.ErrOnlyOwnUser, nil)
+		return
+	}
+
+	return a.linkIdentity(ctx, userId, pending.Profile)
+}
+
+func (a *authUseCase) linkIdentity(ctx context.Context, userId int, profile *socialProfile) (err error) {
+	linkedUser, err := a.userIdentityRepo.GetUser(ctx, profile.Provider, profile.ProviderKey)
+	if err == nil {
+		// 이미 이 계정에 연결되어 있다.
+		if linkedUser.ID == userId {
+			return nil
+		}
+		return ex.NewConflictError(ex.ErrSocialAlreadyLinked, nil)
+	}
+	if !ent.IsNotFound(err) {
+		return
+	}
+
+	if _, err = a.userIdentityRepo.Create(ctx, profile.identity(userId)); err != nil {
+		// 같은 업체의 다른 소셜 계정이 이미 연결되어 있다.
+		if ent.IsConstraintError(err) {
+			err = ex.NewConflictError(ex.ErrSocialProviderAlreadyLinked, nil)
+			return
+		}
+		return
+	}
+	return
+}
+
+// 비밀번호가 없는 계정의 마지막 소셜 계정은 연결 해제할 수 없다.
+func (a *authUseCase) UnlinkSocial(ctx context.Context, userId int, socialName string) (err error) {
+	provider, ok := model.SocialTypeFromString(socialName)
+	if !ok {
+		err = ex.NewNotFoundError(ex.ErrSocialIdentityNotFound, nil)
+		return
+	}
+
+	if err = a.userIdentityRepo.Delete(ctx, userId, provider); err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrSocialIdentityNotFound, nil)
+			return
+		}
+		if err.Error() == repository.ErrLastLoginMethod {
+			err = ex.NewConflictError(ex.ErrLastLoginMethod, nil)
+			return
+		}
+		return
+	}
+	return
+}
+
+const (
+	// 로그인 후 2단계 인증 코드를 입력할 수 있는 시간
+	twoFactorChallengeExpired = 5 * time.Minute
+	// 비밀키를 발급받고 인증 앱에 등록해 확인할 때까지의 시간
+	twoFactorSetupExpired = 10 * time.Minute
+	// challenge 하나로 틀릴 수 있는 횟수. 넘으면 다시 로그인해야 한다.
+	twoFactorMaxAttempts = 5
+	totpIssuer           = "onthemat"
+	recoveryCodeCount    = 10
+)
+
+// 2단계 인증을 사용하는 유저가 로그인하면 토큰 대신 받는다.
+type TwoFactorChallenge struct {
+	ChallengeToken string    `json:"challengeToken"`
+	ExpiredAt      time.Time `json:"expiredAt"`
+}
+
+// 비밀번호(소셜) 확인까지 마친 로그인. 코드를 확인하면 이 정보로 세션을 만든다.
+type pendingTwoFactorLogin struct {
+	UserId    int    `json:"userId"`
+	UserType  string `json:"userType"`
+	LoginType string `json:"loginType"`
+}
+
+type TwoFactorSetupResult struct {
+	Secret string `json:"secret"`
+	// 인증 앱이 QR 코드로 읽는 otpauth:// URI
+	ProvisioningUri string `json:"provisioningUri"`
+}
+
+func twoFactorChallengeHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func twoFactorSetupKey(userId int) string {
+	return "two-factor-setup:" + strconv.Itoa(userId)
+}
+
+func (a *authUseCase) newTwoFactorChallenge(ctx context.Context, userId int, userType string, loginType string) (*TwoFactorChallenge, error) {
+	challengeToken, err := newRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(&pendingTwoFactorLogin{
+		UserId:    userId,
+		UserType:  userType,
+		LoginType: loginType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.store.Set(ctx, "two-factor-challenge:"+twoFactorChallengeHash(challengeToken), string(value), twoFactorChallengeExpired); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorChallenge{
+		ChallengeToken: challengeToken,
+		ExpiredAt:      time.Now().Add(twoFactorChallengeExpired),
+	}, nil
+}
+
+func (a *authUseCase) VerifyTwoFactor(ctx context.Context, body *request.AuthTwoFactorLoginBody, device *SessionDevice) (result *LoginResult, err error) {
+	hash := twoFactorChallengeHash(body.ChallengeToken)
+	challengeKey := "two-factor-challenge:" + hash
+
+	value := a.store.Get(ctx, challengeKey)
+	pending := new(pendingTwoFactorLogin)
+	if value == "" || json.Unmarshal([]byte(value), pending) != nil {
+		err = ex.NewBadRequestError(ex.ErrTwoFactorChallengeInvalid, nil)
+		return
+	}
+
+	attempts, err := a.store.Incr(ctx, "two-factor-attempts:"+hash, twoFactorChallengeExpired)
+	if err != nil {
+		return
+	}
+	if attempts > twoFactorMaxAttempts {
+		a.store.Del(ctx, challengeKey)
+		err = ex.NewTooManyRequestsError(ex.ErrTwoFactorAttemptsExceeded, nil)
+		return
+	}
+
+	user, err := a.userRepo.Get(ctx, pending.UserId)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewBadRequestError(ex.ErrTwoFactorChallengeInvalid, nil)
+		}
+		return
+	}
+
+	if err = a.verifyTwoFactorCode(ctx, user, body.Code); err != nil {
+		return
+	}
+
+	// 같은 challenge 로 동시에 두 번 토큰을 받지 않도록 한 번만 꺼낸다.
+	if value, err = a.store.GetDel(ctx, challengeKey); err != nil {
+		return
+	}
+	if value == "" {
+		err = ex.NewBadRequestError(ex.ErrTwoFactorChallengeInvalid, nil)
+		return
+	}
+
+	session := newSession(uuid.New().String(), pending.LoginType, device)
+	session.TwoFactor = true
+	result, err = a.issueTokens(ctx, user.ID, pending.UserType, session, "")
+	return
+}
+
+// 6자리면 인증 앱의 코드로, 아니면 복구 코드로 확인한다.
+func (a *authUseCase) verifyTwoFactorCode(ctx context.Context, user *ent.User, code string) error {
+	if user.TotpEnabledAt == nil || user.TotpSecret == nil {
+		return ex.NewConflictError(ex.ErrTwoFactorNotEnabled, nil)
+	}
+
+	if len(code) == totp.Digits {
+		secret, err := a.totpBox.Open(*user.TotpSecret)
+		if err != nil {
+			return err
+		}
+		return a.useTotpCode(ctx, user.ID, secret, code)
+	}
+
+	used, err := a.userRepo.UseTotpRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ex.NewBadRequestError(ex.ErrTotpCodeInvalid, nil)
+	}
+	return nil
+}
+
+// 시계 오차로 앞뒤 한 구간(30초)까지 허용하고, 맞은 코드는 유효한 동안 다시 사용할 수 없다.
+func (a *authUseCase) useTotpCode(ctx context.Context, userId int, secret string, code string) error {
+	counter, ok := totp.Validate(secret, code, time.Now(), 1)
+	if !ok {
+		return ex.NewBadRequestError(ex.ErrTotpCodeInvalid, nil)
+	}
+
+	usedKey := "totp-used:" + strconv.Itoa(userId) + ":" + strconv.FormatInt(counter, 10)
+	isFirst, err := a.store.SetNX(ctx, usedKey, "1", 3*totp.Period)
+	if err != nil {
+		return err
+	}
+	if !isFirst {
+		return ex.NewBadRequestError(ex.ErrTotpCodeInvalid, nil)
+	}
+	return nil
+}
+
+// 비밀키는 확인을 마칠 때까지 store 에만 저장한다. 다시 요청하면 새 비밀키로 바뀐다.
+func (a *authUseCase) SetupTwoFactor(ctx context.Context, userId int) (result *TwoFactorSetupResult, err error) {
+	user, err := a.userRepo.Get(ctx, userId)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+		}
+		return
+	}
+	if user.TotpEnabledAt != nil {
+		err = ex.NewConflictError(ex.ErrTwoFactorAlreadyEnabled, nil)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return
+	}
+	if err = a.store.Set(ctx, twoFactorSetupKey(userId), secret, twoFactorSetupExpired); err != nil {
+		return
+	}
+
+	account := strconv.Itoa(userId)
+	if user.Email != nil {
+		account = *user.Email
+	}
+
+	result = &TwoFactorSetupResult{
+		Secret:          secret,
+		ProvisioningUri: totp.URI(totpIssuer, account, secret),
+	}
+	return
+}
+
+// 복구 코드는 해시만 저장하므로 이때 한 번만 보여줄 수 있다.
+// 비밀번호만으로 로그인한 다른 세션은 모두 폐기하고, 관리 기능은 2단계 인증으로 다시 로그인한 세션에서만 사용할 수 있다.
+func (a *authUseCase) ConfirmTwoFactor(ctx context.Context, userId int, sessionId string, body *request.AuthTwoFactorConfirmBody) (recoveryCodes []string, err error) {
+	secret := a.store.Get(ctx, twoFactorSetupKey(userId))
+	if secret == "" {
+		err = ex.NewBadRequestError(ex.ErrTwoFactorSetupExpired, nil)
+		return
+	}
+
+	if err = a.useTotpCode(ctx, userId, secret, body.Code); err != nil {
+		return
+	}
+
+	recoveryCodes = make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range recoveryCodes {
+		if recoveryCodes[i], err = newRecoveryCode(); err != nil {
+			return nil, err
+		}
+		hashes[i] = hashRecoveryCode(recoveryCodes[i])
+	}
+
+	// 비밀키는 암호화해서 저장한다.
+	sealedSecret, err := a.totpBox.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = a.userRepo.EnableTotp(ctx, userId, sealedSecret, hashes, time.Now()); err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewConflictError(ex.ErrTwoFactorAlreadyEnabled, nil)
+		}
+		return nil, err
+	}
+
+	a.store.Del(ctx, twoFactorSetupKey(userId))
+
+	if err = a.revokeOtherSessions(ctx, userId, sessionId); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// superAdmin 은 끌 수 없다.
+func (a *authUseCase) DisableTwoFactor(ctx context.Context, userId int, body *request.AuthTwoFactorCodeBody) (err error) {
+	user, err := a.userRepo.Get(ctx, userId)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+		}
+		return
+	}
+
+	if user.Type != nil && *user.Type == model.SuperAdminType {
+		err = ex.NewForbiddenError(ex.ErrTwoFactorRequired, nil)
+		return
+	}
+
+	if err = a.verifyTwoFactorCode(ctx, user, body.Code); err != nil {
+		return
+	}
+
+	return a.userRepo.DisableTotp(ctx, userId)
+}
+
+// 입력하기 쉽도록 소문자와 숫자 10자리를 5자리씩 나눈다. (xxxxx-xxxxx)
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// 대소문자와 구분 기호(-, 공백) 없이 비교한다.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,8 +2,14 @@ package usecase_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,12 +17,16 @@ import (
 	"onthemat/internal/app/config"
 	"onthemat/internal/app/mocks"
 	"onthemat/internal/app/model"
+	"onthemat/internal/app/repository"
+	"onthemat/internal/app/service"
 	"onthemat/internal/app/service/token"
 	"onthemat/internal/app/transport/request"
 	"onthemat/internal/app/usecase"
+	"onthemat/pkg/auth/secretbox"
+	"onthemat/pkg/auth/totp"
 	"onthemat/pkg/ent"
-	"onthemat/pkg/kakao"
 	pkgMock "onthemat/pkg/mocks"
+	"onthemat/pkg/oauth"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -26,23 +36,49 @@ type AuthUCTestSuite struct {
 	suite.Suite
 	authUC usecase.AuthUseCase
 
-	mockTokenService *mocks.TokenService
-	mockUserRepo     *mocks.UserRepository
-	mockAuthService  *mocks.AuthService
-	mockStore        *pkgMock.Store
-	count            int
+	mockTokenService     *mocks.TokenService
+	mockUserRepo         *mocks.UserRepository
+	mockUserIdentityRepo *mocks.UserIdentityRepository
+	mockEmailOutboxRepo  *mocks.EmailOutboxRepository
+	mockAuthService      *mocks.AuthService
+	mockStore            *pkgMock.Store
+	mockKakao            *pkgMock.SocialProvider
+	mockGoogle           *pkgMock.SocialProvider
+	mockNaver            *pkgMock.SocialProvider
+	totpBox              secretbox.Box
+	count                int
+}
+
+func newMockSocialProvider(name string, supportsPKCE bool) *pkgMock.SocialProvider {
+	provider := new(pkgMock.SocialProvider)
+	provider.On("Name").Return(name)
+	provider.On("SupportsPKCE").Return(supportsPKCE)
+	return provider
 }
 
 // 모든 테스트 시작 전 1회
 func (ts *AuthUCTestSuite) SetupSuite() {
 	c := config.NewConfig()
+	c.JWT.RefreshTokenExpired = 20160
+	c.Onthemat.WithdrawalGraceDays = 30
+	c.RateLimit.LoginFailEmailLimit = 5
+	c.RateLimit.LoginFailIpLimit = 20
+	c.RateLimit.LoginFailWindow = 15
+	c.RateLimit.LoginLockDuration = 15
 
 	ts.mockTokenService = new(mocks.TokenService)
 
 	ts.mockUserRepo = new(mocks.UserRepository)
+	ts.mockUserIdentityRepo = new(mocks.UserIdentityRepository)
+	ts.mockEmailOutboxRepo = new(mocks.EmailOutboxRepository)
 	ts.mockAuthService = new(mocks.AuthService)
 	ts.mockStore = new(pkgMock.Store)
-	ts.authUC = usecase.NewAuthUseCase(ts.mockTokenService, ts.mockUserRepo, ts.mockAuthService, ts.mockStore, c)
+	ts.mockKakao = newMockSocialProvider(model.KakaoString, false)
+	ts.mockGoogle = newMockSocialProvider(model.GoogleString, true)
+	ts.mockNaver = newMockSocialProvider(model.NaverString, false)
+	socials := oauth.NewRegistry(ts.mockKakao, ts.mockGoogle, ts.mockNaver)
+	ts.totpBox, _ = secretbox.NewBox("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	ts.authUC = usecase.NewAuthUseCase(ts.mockTokenService, ts.mockUserRepo, ts.mockUserIdentityRepo, ts.mockEmailOutboxRepo, ts.mockAuthService, socials, ts.mockStore, token.NewSessionChecker(ts.mockStore, 0), ts.totpBox, c)
 }
 
 func (ts *AuthUCTestSuite) TearDownTest() {
@@ -72,8 +108,11 @@ func (ts *AuthUCTestSuite) TestCheckDuplicateEmail() {
 
 func (ts *AuthUCTestSuite) TestSignUp() {
 	ts.Run("이미 존재하는 이메일", func() {
-		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return("hashedPassword")
-		ts.mockUserRepo.On("Create", mock.Anything, mock.AnythingOfType("*ent.User")).Return(nil, &ent.ConstraintError{}).Once()
+		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string")).Return("hashedPassword", nil)
+		ts.mockAuthService.On("GenerateRandomString").Return("randomasdqwd")
+		ts.mockStore.On("Set", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd"), "alreadyExisit@naver.com", 24*time.Hour).Return(nil).Once()
+		ts.mockUserRepo.On("CreateWithEmail", mock.Anything, mock.AnythingOfType("*ent.User"), mock.AnythingOfType("*ent.EmailOutbox")).Return(nil, &ent.ConstraintError{}).Once()
+		ts.mockStore.On("Del", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd")).Return(nil).Once()
 		err := ts.authUC.SignUp(context.TODO(), &request.AuthSignUpBody{
 			Email:     "alreadyExisit@naver.com",
 			Password:  "password",
@@ -82,14 +121,34 @@ func (ts *AuthUCTestSuite) TestSignUp() {
 		})
 		errorStruct := err.(common.HttpError)
 		ts.Equal(409, errorStruct.ErrHttpCode)
+		ts.mockStore.AssertNotCalled(ts.T(), "Set", mock.Anything, "alreadyExisit@naver.com", mock.Anything, mock.Anything)
+	})
+
+	ts.Run("인증키 저장에 실패하면 메일을 쌓지 않음", func() {
+		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string")).Return("hashedPassword", nil)
+		ts.mockAuthService.On("GenerateRandomString").Return("randomasdqwd")
+		ts.mockStore.On("Set", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd"), "fail@naver.com", 24*time.Hour).Return(errors.New("redis down")).Once()
+		err := ts.authUC.SignUp(context.TODO(), &request.AuthSignUpBody{
+			Email:     "fail@naver.com",
+			Password:  "password",
+			NickName:  "nick",
+			TermAgree: true,
+		})
+		ts.Error(err)
+		ts.mockUserRepo.AssertNotCalled(ts.T(), "CreateWithEmail", mock.Anything, mock.MatchedBy(func(u *ent.User) bool {
+			return *u.Email == "fail@naver.com"
+		}), mock.Anything)
 	})
 
 	ts.Run("회원가입 성공", func() {
-		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return("hashedPassword")
-		ts.mockUserRepo.On("Create", mock.Anything, mock.AnythingOfType("*ent.User")).Return(nil, nil).Once()
+		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string")).Return("hashedPassword", nil)
 		ts.mockAuthService.On("GenerateRandomString").Return("randomasdqwd")
-		ts.mockStore.On("Set", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(nil).Once()
-		ts.mockAuthService.On("SendEmailVerifiedUser", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+		ts.mockUserRepo.On("CreateWithEmail", mock.Anything, mock.AnythingOfType("*ent.User"), mock.MatchedBy(func(mail *ent.EmailOutbox) bool {
+			return mail.Recipient == "email@naver.com" && mail.Template == service.MailVerifyEmail &&
+				strings.Contains(mail.Data["verifyUrl"], "key=randomasdqwd") &&
+				strings.Contains(mail.Data["verifyUrl"], "email=email%40naver.com")
+		})).Return(&ent.User{ID: 1}, nil).Once()
+		ts.mockStore.On("Set", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd"), "email@naver.com", 24*time.Hour).Return(nil).Once()
 		err := ts.authUC.SignUp(context.TODO(), &request.AuthSignUpBody{
 			Email:     "email@naver.com",
 			Password:  "password",
@@ -112,21 +171,32 @@ func (ts *AuthUCTestSuite) TestVerifiyEmail() {
 	ts.Run("인증키가 잘못됐을 때", func() {
 		ts.mockAuthService.On("IsExpiredEmailForVerify", mock.AnythingOfType("string")).
 			Return(false).Once()
-		ts.mockStore.On("Get", mock.Anything, mock.AnythingOfType("string")).
-			Return("randomasdqwd").
+		ts.mockStore.On("Get", mock.Anything, "email-verify:"+sha256Hex("HackingRandomKey")).
+			Return("").
 			Once()
 		err := ts.authUC.VerifiyEmail(context.TODO(), "email@naver.com", "HackingRandomKey", "issuedAt")
 		errorStruct := err.(common.HttpError)
 		ts.Equal(400, errorStruct.ErrHttpCode)
 	})
 
+	ts.Run("다른 이메일로 발급된 인증키", func() {
+		ts.mockAuthService.On("IsExpiredEmailForVerify", mock.AnythingOfType("string")).
+			Return(false).Once()
+		ts.mockStore.On("Get", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd")).
+			Return("other@naver.com").
+			Once()
+		err := ts.authUC.VerifiyEmail(context.TODO(), "email@naver.com", "randomasdqwd", "issuedAt")
+		errorStruct := err.(common.HttpError)
+		ts.Equal(400, errorStruct.ErrHttpCode)
+	})
+
 	ts.Run("이미 인증된 유저", func() {
 		email := "email@naver.com"
 
 		ts.mockAuthService.On("IsExpiredEmailForVerify", mock.AnythingOfType("string")).
 			Return(false).Once()
-		ts.mockStore.On("Get", mock.Anything, mock.AnythingOfType("string")).
-			Return("randomasdqwd").
+		ts.mockStore.On("Get", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd")).
+			Return(email).
 			Once()
 
 		ts.mockUserRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("string")).
@@ -146,8 +216,8 @@ func (ts *AuthUCTestSuite) TestVerifiyEmail() {
 		ts.mockAuthService.On("IsExpiredEmailForVerify", mock.AnythingOfType("string")).
 			Return(false).Once()
 
-		ts.mockStore.On("Get", mock.Anything, mock.AnythingOfType("string")).
-			Return("randomasdqwd").
+		ts.mockStore.On("Get", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd")).
+			Return(email2).
 			Once()
 
 		ts.mockUserRepo.On("GetByEmail", mock.Anything, mock.AnythingOfType("string")).
@@ -160,7 +230,7 @@ func (ts *AuthUCTestSuite) TestVerifiyEmail() {
 		ts.mockUserRepo.On("UpdateEmailVerifeid", mock.Anything, mock.AnythingOfType("int")).
 			Return(nil).Once()
 
-		ts.mockStore.On("Del", mock.Anything, mock.AnythingOfType("string")).
+		ts.mockStore.On("Del", mock.Anything, "email-verify:"+sha256Hex("randomasdqwd")).
 			Return(nil).
 			Once()
 
@@ -169,30 +239,211 @@ func (ts *AuthUCTestSuite) TestVerifiyEmail() {
 	})
 }
 
-func (ts *AuthUCTestSuite) TestSendEmailResetPassword() {
+func (ts *AuthUCTestSuite) TestRequestPasswordReset() {
 	userEmail := "asd@naver.com"
 
-	ts.Run("존재하지 않는 이메일", func() {
-		ts.mockUserRepo.On("FindByEmail", mock.Anything, mock.AnythingOfType("string")).Return(false, nil).Once()
+	ts.Run("존재하지 않는 이메일도 성공으로 응답", func() {
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(nil, &ent.NotFoundError{}).Once()
+
+		err := ts.authUC.RequestPasswordReset(context.TODO(), userEmail)
+		ts.NoError(err)
+		ts.mockEmailOutboxRepo.AssertNotCalled(ts.T(), "Create", mock.Anything, mock.Anything)
+	})
 
-		err := ts.authUC.SendEmailResetPassword(context.TODO(), userEmail)
+	ts.Run("탈퇴한 유저는 메일을 보내지 않음", func() {
+		deletedAt := time.Now()
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{ID: 7, Email: &userEmail, DeletedAt: &deletedAt}, nil).Once()
 
-		errorStruct := err.(common.HttpError)
-		ts.Equal(404, errorStruct.ErrHttpCode)
+		err := ts.authUC.RequestPasswordReset(context.TODO(), userEmail)
+		ts.NoError(err)
+		ts.mockStore.AssertNotCalled(ts.T(), "Set", mock.Anything, mock.Anything, "7", mock.Anything)
+		ts.mockEmailOutboxRepo.AssertNotCalled(ts.T(), "Create", mock.Anything, mock.Anything)
 	})
 
 	ts.Run("전송 성공", func() {
-		ts.mockUserRepo.On("FindByEmail", mock.Anything, mock.AnythingOfType("string")).Return(true, nil).Once()
-		ts.mockAuthService.On("GenerateRandomPassword").Return("randomPassword").Once()
-		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return("hashedPassword")
-		ts.mockUserRepo.On("UpdateTempPassword", mock.Anything, mock.AnythingOfType("*ent.User")).Return(nil).Once()
-		ts.mockAuthService.On("SendEmailResetPassword", mock.AnythingOfType("*ent.User")).Return(nil).Once()
-		err := ts.authUC.SendEmailResetPassword(context.TODO(), userEmail)
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{ID: 7, Email: &userEmail}, nil).Once()
+		ts.mockStore.On("Set", mock.Anything,
+			mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "password-reset:") }),
+			"7",
+			30*time.Minute,
+		).Return(nil).Once()
+		ts.mockEmailOutboxRepo.On("Create", mock.Anything, mock.MatchedBy(func(mail *ent.EmailOutbox) bool {
+			return mail.Recipient == userEmail && mail.Template == service.MailPasswordReset &&
+				strings.Contains(mail.Data["resetUrl"], "/password/reset?token=")
+		})).Return(&ent.EmailOutbox{ID: 1}, nil).Once()
+
+		err := ts.authUC.RequestPasswordReset(context.TODO(), userEmail)
+		ts.NoError(err)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestResetPassword() {
+	token := strings.Repeat("ab", 32)
+
+	ts.Run("만료되었거나 사용된 토큰", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.AnythingOfType("string")).Return("", nil).Once()
+
+		err := ts.authUC.ResetPassword(context.TODO(), &request.AuthPasswordResetBody{
+			Token:    token,
+			Password: "newPassword1",
+		})
+		ts.Equal(common.ErrPasswordResetTokenInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("성공하면 모든 세션 폐기", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.AnythingOfType("string")).Return("7", nil).Once()
+		ts.mockAuthService.On("HashPassword", "newPassword1").Return("$argon2id$newHash", nil).Once()
+		ts.mockUserRepo.On("UpdatePassword", mock.Anything, 7, mock.AnythingOfType("string")).Return(nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "7").Return(nil).Once()
+
+		err := ts.authUC.ResetPassword(context.TODO(), &request.AuthPasswordResetBody{
+			Token:    token,
+			Password: "newPassword1",
+		})
+		ts.NoError(err)
+		ts.mockStore.AssertCalled(ts.T(), "Del", mock.Anything, "7")
+	})
+}
 
+func (ts *AuthUCTestSuite) TestChangePassword() {
+	hashedPassword := "$argon2id$current"
+
+	ts.Run("현재 비밀번호 불일치", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 8).Return(&ent.User{ID: 8, Password: &hashedPassword}, nil).Once()
+		ts.mockAuthService.On("VerifyPassword", hashedPassword, "wrongPassword").Return(false, false).Once()
+
+		err := ts.authUC.ChangePassword(context.TODO(), 8, "current", &request.AuthPasswordChangeBody{
+			CurrentPassword: "wrongPassword",
+			NewPassword:     "newPassword1",
+		})
+		ts.Equal(common.ErrPasswordMismatch, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("비밀번호가 없는 소셜 유저", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 9).Return(&ent.User{ID: 9}, nil).Once()
+
+		err := ts.authUC.ChangePassword(context.TODO(), 9, "current", &request.AuthPasswordChangeBody{
+			CurrentPassword: "password1234",
+			NewPassword:     "newPassword1",
+		})
+		ts.Equal(common.ErrPasswordMismatch, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("성공하면 현재 세션을 제외하고 폐기", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 8).Return(&ent.User{ID: 8, Password: &hashedPassword}, nil).Once()
+		ts.mockAuthService.On("VerifyPassword", hashedPassword, "password1234").Return(true, false).Once()
+		ts.mockAuthService.On("HashPassword", "newPassword1").Return("$argon2id$newHash", nil).Once()
+		ts.mockUserRepo.On("UpdatePassword", mock.Anything, 8, mock.AnythingOfType("string")).Return(nil).Once()
+		ts.mockStore.On("HGetAll", mock.Anything, "8").Return(map[string]string{
+			"current": "{}",
+			"other":   "{}",
+		}, nil).Once()
+		ts.mockStore.On("HDel", mock.Anything, "8", "other").Return(nil).Once()
+
+		err := ts.authUC.ChangePassword(context.TODO(), 8, "current", &request.AuthPasswordChangeBody{
+			CurrentPassword: "password1234",
+			NewPassword:     "newPassword1",
+		})
+		ts.NoError(err)
+		ts.mockStore.AssertCalled(ts.T(), "HDel", mock.Anything, "8", "other")
+		ts.mockStore.AssertNotCalled(ts.T(), "HDel", mock.Anything, "8", "current")
+	})
+}
+
+func (ts *AuthUCTestSuite) TestWithdraw() {
+	ts.Run("이미 탈퇴한 유저", func() {
+		ts.mockUserRepo.On("Withdraw", mock.Anything, 11, mock.AnythingOfType("time.Time")).Return(&ent.NotFoundError{}).Once()
+
+		_, err := ts.authUC.Withdraw(context.TODO(), 11)
+		ts.Equal(404, err.(common.HttpError).ErrHttpCode)
+	})
+
+	ts.Run("성공하면 모든 세션 폐기", func() {
+		ts.mockUserRepo.On("Withdraw", mock.Anything, 12, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "12").Return(nil).Once()
+
+		result, err := ts.authUC.Withdraw(context.TODO(), 12)
+		ts.NoError(err)
+		ts.Equal(30*24*time.Hour, result.RestorableUntil.Sub(result.DeletedAt))
+		ts.mockStore.AssertCalled(ts.T(), "Del", mock.Anything, "12")
+	})
+}
+
+func (ts *AuthUCTestSuite) TestWithdrawnLogin() {
+	userEmail := "withdrawn@naver.com"
+	userPassword := "password"
+	hashedPassword := "$argon2id$withdrawn"
+	deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{
+		ID:              13,
+		Email:           &userEmail,
+		Password:        &hashedPassword,
+		IsEmailVerified: true,
+		DeletedAt:       &deletedAt,
+	}, nil).Once()
+	ts.mockAuthService.On("VerifyPassword", hashedPassword, userPassword).Return(true, false).Once()
+	ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+	ts.mockStore.On("Del", mock.Anything, "login-fail:email:"+userEmail).Return(nil).Once()
+	ts.mockStore.On("Set", mock.Anything,
+		mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "user-restore:") }),
+		"13",
+		10*time.Minute,
+	).Return(nil).Once()
+
+	_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+		Email:    userEmail,
+		Password: userPassword,
+	}, nil)
+
+	httpErr := err.(common.HttpError)
+	ts.Equal(403, httpErr.ErrHttpCode)
+	ts.Equal(common.ErrUserWithdrawn, httpErr.ErrCode)
+
+	detail := httpErr.ErrDetails.(*usecase.WithdrawnDetail)
+	ts.Len(detail.RestoreToken, 64)
+	ts.Equal(deletedAt.Add(30*24*time.Hour), detail.RestorableUntil)
+	ts.mockTokenService.AssertNotCalled(ts.T(), "GenerateToken", mock.Anything, 13, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *AuthUCTestSuite) TestRestore() {
+	token := strings.Repeat("cd", 32)
+
+	ts.Run("만료되었거나 사용된 토큰", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "user-restore:")
+		})).Return("", nil).Once()
+
+		err := ts.authUC.Restore(context.TODO(), &request.AuthRestoreBody{Token: token})
+		ts.Equal(common.ErrRestoreTokenInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("이미 익명화된 유저", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.AnythingOfType("string")).Return("13", nil).Once()
+		ts.mockUserRepo.On("Restore", mock.Anything, 13).Return(&ent.NotFoundError{}).Once()
+
+		err := ts.authUC.Restore(context.TODO(), &request.AuthRestoreBody{Token: token})
+		ts.Equal(404, err.(common.HttpError).ErrHttpCode)
+	})
+
+	ts.Run("성공", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.AnythingOfType("string")).Return("13", nil).Once()
+		ts.mockUserRepo.On("Restore", mock.Anything, 13).Return(nil).Once()
+
+		err := ts.authUC.Restore(context.TODO(), &request.AuthRestoreBody{Token: token})
 		ts.NoError(err)
 	})
 }
 
+func (ts *AuthUCTestSuite) TestAnonymizeWithdrawn() {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	ts.mockUserRepo.On("AnonymizeWithdrawn", mock.Anything, now.Add(-30*24*time.Hour), now).Return(4, nil).Once()
+
+	count, err := ts.authUC.AnonymizeWithdrawn(context.TODO(), now)
+	ts.NoError(err)
+	ts.Equal(4, count)
+}
+
 func (ts *AuthUCTestSuite) TestRefresh() {
 	userEmail := "asd@naver.com"
 	socialKey := "asadasd"
@@ -215,22 +466,35 @@ func (ts *AuthUCTestSuite) TestRefresh() {
 
 		ts.mockUserRepo.On("Get", mock.Anything, mock.AnythingOfType("int")).
 			Return(&ent.User{
-				ID:         1,
-				Email:      &userEmail,
-				SocialKey:  &socialKey,
-				SocialName: &model.KakaoSocialType,
+				ID:    1,
+				Email: &userEmail,
 			}, nil).Once()
+		ts.mockUserIdentityRepo.On("List", mock.Anything, 1).
+			Return([]*ent.UserIdentity{{Provider: model.KakaoSocialType, ProviderKey: socialKey}}, nil).Once()
+
+		ts.mockTokenService.On("GenerateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+			Return("newRefreshToken", nil).
+			Once()
+
+		ts.mockStore.On("HSetIfEqual", mock.Anything,
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"),
+			"1",
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("time.Duration"),
+		).Return(true, nil).Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("int")).
 			Return("AccessToken", nil).
 			Once()
 
 		ts.mockTokenService.On("GetExpiredAt", mock.AnythingOfType("int")).
 			Return(time.Now()).
-			Once()
+			Twice()
 
-		_, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"))
+		r, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"), nil)
 		ts.NoError(err)
+		ts.Equal("newRefreshToken", r.RefreshToken)
 	})
 
 	ts.Run("성공 유저타입 [o] 소셜로그인[o]", func() {
@@ -252,70 +516,284 @@ func (ts *AuthUCTestSuite) TestRefresh() {
 
 		ts.mockUserRepo.On("Get", mock.Anything, mock.AnythingOfType("int")).
 			Return(&ent.User{
-				ID:         1,
-				Email:      &userEmail,
-				SocialKey:  &socialKey,
-				SocialName: &model.KakaoSocialType,
-				Type:       &model.AcademyType,
+				ID:    1,
+				Email: &userEmail,
+				Type:  &model.AcademyType,
 			}, nil).Once()
+		ts.mockUserIdentityRepo.On("List", mock.Anything, 1).
+			Return([]*ent.UserIdentity{{Provider: model.KakaoSocialType, ProviderKey: socialKey}}, nil).Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+			Return("newRefreshToken", nil).
+			Once()
+
+		ts.mockStore.On("HSetIfEqual", mock.Anything,
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"),
+			"1",
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("time.Duration"),
+		).Return(true, nil).Once()
+
+		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("int")).
 			Return("AccessToken", nil).
 			Once()
 
 		ts.mockTokenService.On("GetExpiredAt", mock.AnythingOfType("int")).
 			Return(time.Now()).
-			Once()
+			Twice()
+
+		r, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"), nil)
+		ts.NoError(err)
+		ts.Equal("newRefreshToken", r.RefreshToken)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestRefreshRotation() {
+	ts.Run("마지막으로 발급된 토큰이면 새 토큰으로 교체", func() {
+		ts.mockAuthService.On("ExtractTokenFromHeader", mock.AnythingOfType("string")).
+			Return("refreshToken", nil).Once()
+
+		var claim token.TokenClaim
+		ts.mockTokenService.On("ParseToken", mock.AnythingOfType("string"), &claim).
+			Return(nil).Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*token.TokenClaim)
+			arg.UserId = 2
+			arg.Uuid = "family"
+			arg.ID = "current"
+		}).Once()
+
+		stored := `{"tokenId":"current","userAgent":"old","ip":"1.1.1.1","loginType":"normal","twoFactor":true,"createdAt":"2022-12-01T00:00:00Z"}`
+		ts.mockStore.On("HGet", mock.Anything, "2", "family").
+			Return(stored, nil).Once()
+
+		ts.mockUserRepo.On("Get", mock.Anything, 2).
+			Return(&ent.User{ID: 2}, nil).Once()
+
+		ts.mockTokenService.On("GenerateRefreshToken", "family", mock.AnythingOfType("string"), 2, "normal", "", mock.AnythingOfType("int")).
+			Return("newRefreshToken", nil).Once()
+
+		ts.mockStore.On("HSetIfEqual", mock.Anything, "2", "family", stored,
+			mock.MatchedBy(func(value string) bool {
+				var session usecase.Session
+				json.Unmarshal([]byte(value), &session)
+				return session.TokenId != "current" && session.TokenId != "" &&
+					session.UserAgent == "new" && session.Ip == "2.2.2.2" && session.TwoFactor &&
+					session.CreatedAt.Equal(time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC))
+			}),
+			mock.AnythingOfType("time.Duration"),
+		).Return(true, nil).Once()
+
+		// 2단계 인증으로 로그인한 세션은 리프레쉬해도 유지된다.
+		ts.mockTokenService.On("GenerateToken", "family", 2, "normal", "", true, mock.AnythingOfType("int")).
+			Return("AccessToken", nil).Once()
+
+		ts.mockTokenService.On("GetExpiredAt", mock.AnythingOfType("int")).
+			Return(time.Now()).Twice()
+
+		r, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"), &usecase.SessionDevice{UserAgent: "new", Ip: "2.2.2.2"})
+		ts.NoError(err)
+		ts.Equal("AccessToken", r.AccessToken)
+		ts.Equal("newRefreshToken", r.RefreshToken)
+	})
+
+	ts.Run("이미 교체된 토큰을 재사용하면 패밀리 전체 폐기", func() {
+		ts.mockAuthService.On("ExtractTokenFromHeader", mock.AnythingOfType("string")).
+			Return("refreshToken", nil).Once()
+
+		var claim token.TokenClaim
+		ts.mockTokenService.On("ParseToken", mock.AnythingOfType("string"), &claim).
+			Return(nil).Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*token.TokenClaim)
+			arg.UserId = 2
+			arg.Uuid = "family"
+			arg.ID = "rotated"
+		}).Once()
+
+		ts.mockStore.On("HGet", mock.Anything, "2", "family").
+			Return("current", nil).Once()
+
+		ts.mockStore.On("HDel", mock.Anything, "2", "family").
+			Return(nil).Once()
+
+		_, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"), nil)
+		ts.Equal(common.ErrRefreshTokenReused, err.(common.HttpError).ErrCode)
+		ts.mockStore.AssertCalled(ts.T(), "HDel", mock.Anything, "2", "family")
+	})
+
+	ts.Run("같은 토큰으로 동시에 갱신하면 먼저 교체한 요청만 성공", func() {
+		ts.mockAuthService.On("ExtractTokenFromHeader", mock.AnythingOfType("string")).
+			Return("refreshToken", nil).Once()
+
+		var claim token.TokenClaim
+		ts.mockTokenService.On("ParseToken", mock.AnythingOfType("string"), &claim).
+			Return(nil).Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*token.TokenClaim)
+			arg.UserId = 2
+			arg.Uuid = "raced"
+			arg.ID = "current"
+		}).Once()
+
+		stored := `{"tokenId":"current","loginType":"normal"}`
+		ts.mockStore.On("HGet", mock.Anything, "2", "raced").
+			Return(stored, nil).Once()
+		ts.mockUserRepo.On("Get", mock.Anything, 2).
+			Return(&ent.User{ID: 2}, nil).Once()
+		ts.mockTokenService.On("GenerateRefreshToken", "raced", mock.AnythingOfType("string"), 2, "normal", "", mock.AnythingOfType("int")).
+			Return("newRefreshToken", nil).Once()
+
+		// 다른 요청이 먼저 교체했다.
+		ts.mockStore.On("HSetIfEqual", mock.Anything, "2", "raced", stored, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).
+			Return(false, nil).Once()
+		ts.mockStore.On("HDel", mock.Anything, "2", "raced").
+			Return(nil).Once()
+
+		_, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"), nil)
+		ts.Equal(common.ErrRefreshTokenReused, err.(common.HttpError).ErrCode)
+		ts.mockStore.AssertCalled(ts.T(), "HDel", mock.Anything, "2", "raced")
+		ts.mockTokenService.AssertNotCalled(ts.T(), "GenerateToken", "raced", 2, "normal", "", mock.Anything, mock.Anything)
+	})
+
+	ts.Run("폐기된 패밀리", func() {
+		ts.mockAuthService.On("ExtractTokenFromHeader", mock.AnythingOfType("string")).
+			Return("refreshToken", nil).Once()
+
+		var claim token.TokenClaim
+		ts.mockTokenService.On("ParseToken", mock.AnythingOfType("string"), &claim).
+			Return(nil).Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*token.TokenClaim)
+			arg.UserId = 2
+			arg.Uuid = "revoked"
+			arg.ID = "current"
+		}).Once()
+
+		ts.mockStore.On("HGet", mock.Anything, "2", "revoked").
+			Return("", errors.New("redis: nil")).Once()
+
+		_, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"), nil)
+		ts.Equal(common.ErrTokenInvalid, err.(common.HttpError).ErrCode)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestSessionList() {
+	ts.Run("만료된 세션은 제외하고 최근 사용 순으로 반환", func() {
+		now := time.Now()
+		value := func(lastUsedAt time.Time) string {
+			b, _ := json.Marshal(&usecase.Session{TokenId: "t", LastUsedAt: lastUsedAt, CreatedAt: lastUsedAt})
+			return string(b)
+		}
+
+		ts.mockStore.On("HGetAll", mock.Anything, "3").
+			Return(map[string]string{
+				"old":     value(now.Add(-time.Hour)),
+				"recent":  value(now),
+				"expired": value(now.AddDate(0, -1, 0)),
+				"legacy":  "3",
+			}, nil).Once()
+
+		ts.mockStore.On("HDel", mock.Anything, "3", "expired").
+			Return(nil).Once()
+
+		sessions, err := ts.authUC.SessionList(context.TODO(), 3)
+		ts.NoError(err)
+		ts.Len(sessions, 3)
+		ts.Equal("recent", sessions[0].Uuid)
+		ts.Equal("old", sessions[1].Uuid)
+		ts.Equal("legacy", sessions[2].Uuid)
+		ts.mockStore.AssertCalled(ts.T(), "HDel", mock.Anything, "3", "expired")
+	})
+}
+
+func (ts *AuthUCTestSuite) TestRevokeSession() {
+	ts.Run("성공", func() {
+		ts.mockStore.On("HGet", mock.Anything, "3", "session").
+			Return("tokenId", nil).Once()
+		ts.mockStore.On("HDel", mock.Anything, "3", "session").
+			Return(nil).Once()
 
-		_, err := ts.authUC.Refresh(context.TODO(), []byte("Bearer refreshToken"))
+		err := ts.authUC.RevokeSession(context.TODO(), 3, "session")
 		ts.NoError(err)
 	})
+
+	ts.Run("없는 세션", func() {
+		ts.mockStore.On("HGet", mock.Anything, "3", "unknown").
+			Return("", errors.New("redis: nil")).Once()
+
+		err := ts.authUC.RevokeSession(context.TODO(), 3, "unknown")
+		ts.Equal(common.ErrSessionNotFound, err.(common.HttpError).ErrCode)
+	})
 }
 
 func (ts *AuthUCTestSuite) TestLogin() {
 	userEmail := "asd@naver.com"
 	userPassword := "password"
+	hashedPassword := "$argon2id$hashedPassword"
 
 	ts.Run("유저 정보가 없을 경우", func() {
-		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return("hashedPassword")
-		ts.mockUserRepo.On("GetByEmailPassword", mock.Anything, mock.AnythingOfType("*ent.User")).Return(nil, &ent.NotFoundError{}).Once()
+		ts.expectLoginNotLocked("login-lock:email:asd@naver.com")
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, "asd@naver.com").Return(nil, &ent.NotFoundError{}).Once()
+		ts.mockAuthService.On("VerifyPassword", mock.AnythingOfType("string"), "password").Return(false, false).Once()
+		ts.mockStore.On("Incr", mock.Anything, "login-fail:email:asd@naver.com", 15*time.Minute).Return(int64(1), nil).Once()
 
-		_, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+		_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
 			Email:    "asd@naver.com",
 			Password: "password",
-		})
+		}, nil)
+
+		errorStruct := err.(common.HttpError)
+		ts.Equal(404, errorStruct.ErrHttpCode)
+	})
+
+	ts.Run("비밀번호가 일치하지 않는 경우", func() {
+		ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{
+			Email:           &userEmail,
+			Password:        &hashedPassword,
+			IsEmailVerified: true,
+		}, nil).Once()
+		ts.mockAuthService.On("VerifyPassword", hashedPassword, "wrongPassword").Return(false, false).Once()
+		ts.mockStore.On("Incr", mock.Anything, "login-fail:email:"+userEmail, 15*time.Minute).Return(int64(2), nil).Once()
+
+		_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+			Email:    userEmail,
+			Password: "wrongPassword",
+		}, nil)
 
 		errorStruct := err.(common.HttpError)
 		ts.Equal(404, errorStruct.ErrHttpCode)
 	})
 
 	ts.Run("이메일 인증이 되지 않은 경우", func() {
-		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return("hashedPassword")
-		ts.mockUserRepo.On("GetByEmailPassword", mock.Anything, mock.AnythingOfType("*ent.User")).Return(&ent.User{
+		ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+		ts.mockStore.On("Del", mock.Anything, "login-fail:email:"+userEmail).Return(nil).Once()
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{
 			Email:           &userEmail,
-			Password:        &userPassword,
+			Password:        &hashedPassword,
 			IsEmailVerified: false,
 		}, nil).Once()
+		ts.mockAuthService.On("VerifyPassword", hashedPassword, userPassword).Return(true, false).Once()
 
-		_, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+		_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
 			Email:    userEmail,
 			Password: userPassword,
-		})
+		}, nil)
 
 		errorStruct := err.(common.HttpError)
 		ts.Equal(401, errorStruct.ErrHttpCode)
 	})
 
 	ts.Run("성공", func() {
-		ts.mockAuthService.On("HashPassword", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return("hashedPassword")
-		ts.mockUserRepo.On("GetByEmailPassword", mock.Anything, mock.AnythingOfType("*ent.User")).Return(&ent.User{
+		ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+		ts.mockStore.On("Del", mock.Anything, "login-fail:email:"+userEmail).Return(nil).Once()
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{
 			Email:           &userEmail,
-			Password:        &userPassword,
+			Password:        &hashedPassword,
 			Type:            &model.AcademyType,
 			IsEmailVerified: true,
 		}, nil).Once()
+		ts.mockAuthService.On("VerifyPassword", hashedPassword, userPassword).Return(true, false).Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
 			Return("refreshToken", nil).
 			Once()
 
@@ -326,7 +804,7 @@ func (ts *AuthUCTestSuite) TestLogin() {
 			mock.AnythingOfType("time.Duration"),
 		).Return(nil).Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("int")).
 			Return("AccessToken", nil).
 			Once()
 
@@ -338,15 +816,189 @@ func (ts *AuthUCTestSuite) TestLogin() {
 			Return(time.Now()).
 			Once()
 
-		l, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+		l, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
 			Email:    userEmail,
 			Password: userPassword,
-		})
+		}, nil)
 		ts.NoError(err)
 		ts.Equal(l.AccessToken, "AccessToken")
 	})
 }
 
+func (ts *AuthUCTestSuite) TestLoginRehash() {
+	userEmail := "legacy@naver.com"
+	userPassword := "password"
+	legacyPassword := "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"
+
+	ts.Run("기존 해시로 로그인하면 새 해시로 교체", func() {
+		ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+		ts.mockStore.On("Del", mock.Anything, "login-fail:email:"+userEmail).Return(nil).Once()
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{
+			ID:       10,
+			Email:    &userEmail,
+			Password: &legacyPassword,
+		}, nil).Once()
+		ts.mockAuthService.On("VerifyPassword", legacyPassword, userPassword).Return(true, true).Once()
+		ts.mockAuthService.On("HashPassword", userPassword).Return("$argon2id$newHash", nil).Once()
+		ts.mockUserRepo.On("UpdatePassword", mock.Anything, 10, "$argon2id$newHash").Return(nil).Once()
+
+		// 이메일 인증 전이라 토큰 발급 전에 끝난다.
+		_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+			Email:    userEmail,
+			Password: userPassword,
+		}, nil)
+		ts.Equal(401, err.(common.HttpError).ErrHttpCode)
+		ts.mockUserRepo.AssertCalled(ts.T(), "UpdatePassword", mock.Anything, 10, "$argon2id$newHash")
+	})
+}
+
+func (ts *AuthUCTestSuite) expectLoginNotLocked(key string) {
+	ts.mockStore.On("Get", mock.Anything, key).Return("").Once()
+}
+
+func (ts *AuthUCTestSuite) TestLoginLock() {
+	userEmail := "lock@naver.com"
+	device := &usecase.SessionDevice{Ip: "1.2.3.4"}
+
+	ts.Run("잠긴 이메일은 비밀번호를 확인하지 않는다", func() {
+		unlockAt := time.Now().Add(10 * time.Minute).Unix()
+		ts.mockStore.On("Get", mock.Anything, "login-lock:email:"+userEmail).Return(strconv.FormatInt(unlockAt, 10)).Once()
+
+		_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+			Email:    "Lock@naver.com",
+			Password: "password",
+		}, device)
+
+		httpErr := err.(common.HttpError)
+		ts.Equal(429, httpErr.ErrHttpCode)
+		ts.Equal(common.ErrLoginLocked, httpErr.ErrCode)
+		ts.InDelta(600, httpErr.ErrDetails.(map[string]int64)["retryAfter"], 1)
+		ts.mockUserRepo.AssertNotCalled(ts.T(), "GetByEmail", mock.Anything, "Lock@naver.com")
+	})
+
+	ts.Run("잠긴 IP", func() {
+		unlockAt := time.Now().Add(time.Minute).Unix()
+		ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+		ts.mockStore.On("Get", mock.Anything, "login-lock:ip:1.2.3.4").Return(strconv.FormatInt(unlockAt, 10)).Once()
+
+		_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+			Email:    userEmail,
+			Password: "password",
+		}, device)
+
+		ts.Equal(common.ErrLoginLocked, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("실패 횟수가 기준에 닿으면 잠근다", func() {
+		ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+		ts.expectLoginNotLocked("login-lock:ip:1.2.3.4")
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(nil, &ent.NotFoundError{}).Once()
+		ts.mockAuthService.On("VerifyPassword", mock.AnythingOfType("string"), "password").Return(false, false).Once()
+		ts.mockStore.On("Incr", mock.Anything, "login-fail:email:"+userEmail, 15*time.Minute).Return(int64(5), nil).Once()
+		ts.mockStore.On("Incr", mock.Anything, "login-fail:ip:1.2.3.4", 15*time.Minute).Return(int64(3), nil).Once()
+		ts.mockStore.On("Set", mock.Anything, "login-lock:email:"+userEmail, mock.AnythingOfType("string"), 15*time.Minute).Return(nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "login-fail:email:"+userEmail).Return(nil).Once()
+
+		_, _, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+			Email:    userEmail,
+			Password: "password",
+		}, device)
+
+		ts.Equal(404, err.(common.HttpError).ErrHttpCode)
+		ts.mockStore.AssertNotCalled(ts.T(), "Set", mock.Anything, "login-lock:ip:1.2.3.4", mock.Anything, mock.Anything)
+	})
+}
+
+// SocialLoginRedirectUrl 에서 저장한 state 를 콜백에서 한 번 꺼낸다.
+func (ts *AuthUCTestSuite) expectOAuthState(provider string, codeVerifier string) {
+	value, _ := json.Marshal(map[string]string{"provider": provider, "codeVerifier": codeVerifier})
+	ts.mockStore.On("GetDel", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "oauth-state:")
+	})).Return(string(value), nil).Once()
+}
+
+func (ts *AuthUCTestSuite) TestSocialLoginRedirectUrl() {
+	ts.Run("구글은 state 와 PKCE code_verifier 를 저장한다", func() {
+		var state, challenge, stored string
+		ts.mockGoogle.On("AuthorizeURL", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				state = args.String(0)
+				challenge = args.String(1)
+			}).Return("https://accounts.google.com/o/oauth2/v2/auth").Once()
+		ts.mockStore.On("Set", mock.Anything,
+			mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "oauth-state:") }),
+			mock.AnythingOfType("string"),
+			10*time.Minute,
+		).Run(func(args mock.Arguments) {
+			stored = args.String(2)
+		}).Return(nil).Once()
+
+		url, issued, err := ts.authUC.SocialLoginRedirectUrl(context.TODO(), model.GoogleString)
+		ts.NoError(err)
+		ts.Equal("https://accounts.google.com/o/oauth2/v2/auth", url)
+		ts.Len(state, 64)
+		ts.Equal(state, issued)
+
+		saved := struct {
+			Provider     string `json:"provider"`
+			CodeVerifier string `json:"codeVerifier"`
+		}{}
+		ts.NoError(json.Unmarshal([]byte(stored), &saved))
+		ts.Equal(model.GoogleString, saved.Provider)
+		ts.Len(saved.CodeVerifier, 43)
+		sum := sha256.Sum256([]byte(saved.CodeVerifier))
+		ts.Equal(base64.RawURLEncoding.EncodeToString(sum[:]), challenge)
+	})
+
+	ts.Run("네이버는 state 만 저장한다", func() {
+		var stored string
+		ts.mockNaver.On("AuthorizeURL", mock.AnythingOfType("string"), "").
+			Return("https://nid.naver.com/oauth2.0/authorize").Once()
+		ts.mockStore.On("Set", mock.Anything,
+			mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "oauth-state:") }),
+			mock.AnythingOfType("string"),
+			10*time.Minute,
+		).Run(func(args mock.Arguments) {
+			stored = args.String(2)
+		}).Return(nil).Once()
+
+		_, _, err := ts.authUC.SocialLoginRedirectUrl(context.TODO(), model.NaverString)
+		ts.NoError(err)
+		ts.JSONEq(`{"provider":"naver"}`, stored)
+	})
+
+	ts.Run("지원하지 않는 업체", func() {
+		_, _, err := ts.authUC.SocialLoginRedirectUrl(context.TODO(), "facebook")
+		ts.Error(err)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestSocialLoginStateInvalid() {
+	ts.Run("state 없음", func() {
+		_, _, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, "code", "", nil)
+		ts.Equal(common.ErrOAuthStateInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("만료되었거나 이미 사용된 state", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "oauth-state:")
+		})).Return("", nil).Once()
+
+		_, _, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, "code", "state", nil)
+		ts.Equal(400, err.(common.HttpError).ErrHttpCode)
+		ts.Equal(common.ErrOAuthStateInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("다른 업체로 발급된 state", func() {
+		ts.expectOAuthState(model.GoogleString, "verifier")
+
+		_, _, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, "code", "state", nil)
+		ts.Equal(common.ErrOAuthStateInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.mockKakao.AssertNotCalled(ts.T(), "ExchangeCode", "code", mock.Anything, mock.Anything)
+}
+
 func (ts *AuthUCTestSuite) TestSocialLogin() {
 	redirectCode := "examplecode"
 	sociaKey := "123123123"
@@ -354,42 +1006,36 @@ func (ts *AuthUCTestSuite) TestSocialLogin() {
 	nickname := "nickname"
 
 	ts.Run("카카오 로그인 (최초 접근)", func() {
-		keyInt, _ := strconv.Atoi(sociaKey)
-		ts.mockAuthService.On("GetKakaoInfo", mock.AnythingOfType("string")).
-			Return(&kakao.GetUserInfoSuccessBody{
-				Id: uint(keyInt),
-				KakaoAccount: struct {
-					Email   *string "json:\"email\""
-					Profile struct {
-						NickName string "json:\"nickname\""
-					} "json:\"profile\""
-				}{
-					Email: &email,
-					Profile: struct {
-						NickName string "json:\"nickname\""
-					}{
-						NickName: nickname,
-					},
-				},
-			}, nil).
+		ts.expectOAuthState(model.KakaoString, "")
+		ts.mockKakao.On("ExchangeCode", redirectCode, "state", "").
+			Return(&oauth.Token{AccessToken: "kakaoAccessToken"}, nil).
+			Once()
+		ts.mockKakao.On("FetchProfile", &oauth.Token{AccessToken: "kakaoAccessToken"}).
+			Return(&oauth.Profile{Key: sociaKey, Email: email, Nickname: nickname}, nil).
 			Once()
 
-		ts.mockUserRepo.On("GetBySocialKey", mock.Anything, mock.Anything).
-			Return(nil, nil).
+		ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.KakaoSocialType, sociaKey).
+			Return(nil, &ent.NotFoundError{}).
 			Once()
 
-		ts.mockUserRepo.On("Create", mock.Anything, mock.AnythingOfType("*ent.User")).
+		ts.mockUserRepo.On("GetByEmail", mock.Anything, email).
+			Return(nil, &ent.NotFoundError{}).
+			Once()
+
+		ts.mockUserRepo.On("CreateWithIdentity", mock.Anything, mock.AnythingOfType("*ent.User"), &ent.UserIdentity{
+			Provider:    model.KakaoSocialType,
+			ProviderKey: sociaKey,
+			Email:       &email,
+		}).
 			Return(&ent.User{
 				ID:          1,
 				TermAgreeAt: time.Now(),
-				SocialName:  &model.KakaoSocialType,
-				SocialKey:   &sociaKey,
 				Email:       &email,
 				Nickname:    &nickname,
 			}, nil).
 			Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
 			Return("refreshToken", nil).
 			Once()
 
@@ -399,7 +1045,7 @@ func (ts *AuthUCTestSuite) TestSocialLogin() {
 			mock.AnythingOfType("string"),
 			mock.AnythingOfType("time.Duration")).Return(nil).Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("int")).
 			Return("AccessToken", nil).
 			Once()
 
@@ -411,25 +1057,26 @@ func (ts *AuthUCTestSuite) TestSocialLogin() {
 			Return(time.Now()).Once()
 
 		// 검증
-		l, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, redirectCode)
+		l, _, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, redirectCode, "state", nil)
 
 		ts.Equal(l.AccessToken, "AccessToken")
 		ts.NoError(err, nil)
 	})
 
 	ts.Run("카카오 로그인 이미 가입한 유저", func() {
-		keyInt, _ := strconv.Atoi(sociaKey)
-		ts.mockAuthService.On("GetKakaoInfo", mock.AnythingOfType("string")).
-			Return(&kakao.GetUserInfoSuccessBody{
-				Id: uint(keyInt),
-			}, nil).
+		ts.expectOAuthState(model.KakaoString, "")
+		ts.mockKakao.On("ExchangeCode", redirectCode, "state", "").
+			Return(&oauth.Token{AccessToken: "kakaoAccessToken"}, nil).
+			Once()
+		ts.mockKakao.On("FetchProfile", mock.Anything).
+			Return(&oauth.Profile{Key: sociaKey}, nil).
 			Once()
 
-		ts.mockUserRepo.On("GetBySocialKey", mock.Anything, mock.Anything).
-			Return(&ent.User{ID: 1, SocialKey: &sociaKey, SocialName: &model.KakaoSocialType}, nil).
+		ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.KakaoSocialType, sociaKey).
+			Return(&ent.User{ID: 1}, nil).
 			Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
 			Return("refreshToken", nil).
 			Once()
 
@@ -440,7 +1087,7 @@ func (ts *AuthUCTestSuite) TestSocialLogin() {
 			mock.AnythingOfType("string"),
 			mock.AnythingOfType("time.Duration")).Return(nil).Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("int")).
 			Return("AccessToken", nil).
 			Once()
 
@@ -451,32 +1098,31 @@ func (ts *AuthUCTestSuite) TestSocialLogin() {
 		ts.mockTokenService.On("GetExpiredAt", mock.AnythingOfType("int")).
 			Return(time.Now()).Once()
 
-		l, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, redirectCode)
+		l, _, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, redirectCode, "state", nil)
 
 		ts.Equal(l.AccessToken, "AccessToken")
 		ts.NoError(err, nil)
 	})
 
 	ts.Run("카카오 로그인 학원선생님 인증을 마친 유저", func() {
-		keyInt, _ := strconv.Atoi(sociaKey)
-		ts.mockAuthService.On("GetKakaoInfo", mock.AnythingOfType("string")).
-			Return(&kakao.GetUserInfoSuccessBody{
-				Id: uint(keyInt),
-			}, nil).
+		ts.expectOAuthState(model.KakaoString, "")
+		ts.mockKakao.On("ExchangeCode", redirectCode, "state", "").
+			Return(&oauth.Token{AccessToken: "kakaoAccessToken"}, nil).
+			Once()
+		ts.mockKakao.On("FetchProfile", mock.Anything).
+			Return(&oauth.Profile{Key: sociaKey}, nil).
 			Once()
 
-		ts.mockUserRepo.On("GetBySocialKey", mock.Anything, mock.Anything).
+		ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.KakaoSocialType, sociaKey).
 			Return(&ent.User{
-				ID:         1,
-				SocialKey:  &sociaKey,
-				SocialName: &model.KakaoSocialType,
-				Email:      &email,
-				Nickname:   &nickname,
-				Type:       &model.AcademyType,
+				ID:       1,
+				Email:    &email,
+				Nickname: &nickname,
+				Type:     &model.AcademyType,
 			}, nil).
 			Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
 			Return("refreshToken", nil).
 			Once()
 
@@ -484,7 +1130,7 @@ func (ts *AuthUCTestSuite) TestSocialLogin() {
 			mock.AnythingOfType("string"),
 			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(nil).Once()
 
-		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int")).
+		ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("int")).
 			Return("AccessToken", nil).
 			Once()
 
@@ -495,13 +1141,159 @@ func (ts *AuthUCTestSuite) TestSocialLogin() {
 		ts.mockTokenService.On("GetExpiredAt", mock.AnythingOfType("int")).
 			Return(time.Now()).Once()
 
-		l, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, redirectCode)
+		l, _, err := ts.authUC.SocialLogin(context.TODO(), model.KakaoString, redirectCode, "state", nil)
 
 		ts.Equal(l.AccessToken, "AccessToken")
 		ts.NoError(err, nil)
 	})
 }
 
+func (ts *AuthUCTestSuite) TestSocialLoginEmailCollision() {
+	email := "collision@naver.com"
+	nickname := "nickname"
+
+	ts.expectOAuthState(model.NaverString, "")
+	ts.mockNaver.On("ExchangeCode", "collisionCode", "state", "").
+		Return(&oauth.Token{AccessToken: "naverAccessToken"}, nil).
+		Once()
+	ts.mockNaver.On("FetchProfile", &oauth.Token{AccessToken: "naverAccessToken"}).
+		Return(&oauth.Profile{Key: "naverKey", Email: email, Nickname: nickname}, nil).
+		Once()
+	ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.NaverSocialType, "naverKey").
+		Return(nil, &ent.NotFoundError{}).
+		Once()
+	ts.mockUserRepo.On("GetByEmail", mock.Anything, email).
+		Return(&ent.User{ID: 21, Email: &email}, nil).
+		Once()
+
+	var stored string
+	ts.mockStore.On("Set", mock.Anything,
+		mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "social-link:") }),
+		mock.AnythingOfType("string"),
+		10*time.Minute,
+	).Run(func(args mock.Arguments) {
+		stored = args.Get(2).(string)
+	}).Return(nil).Once()
+
+	_, _, err := ts.authUC.SocialLogin(context.TODO(), model.NaverString, "collisionCode", "state", nil)
+
+	// 기존 계정에 자동으로 연결하거나 새 계정을 만들지 않는다.
+	httpErr := err.(common.HttpError)
+	ts.Equal(409, httpErr.ErrHttpCode)
+	ts.Equal(common.ErrSocialLinkRequired, httpErr.ErrCode)
+	detail := httpErr.ErrDetails.(*usecase.SocialLinkDetail)
+	ts.Len(detail.LinkToken, 64)
+	ts.Equal(model.NaverString, detail.Provider)
+	isNaverKey := mock.MatchedBy(func(i *ent.UserIdentity) bool { return i.ProviderKey == "naverKey" })
+	ts.mockUserRepo.AssertNotCalled(ts.T(), "CreateWithIdentity", mock.Anything, mock.Anything, isNaverKey)
+	ts.mockUserIdentityRepo.AssertNotCalled(ts.T(), "Create", mock.Anything, isNaverKey)
+
+	ts.Run("다른 계정으로 연결 확인", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "social-link:")
+		})).Return(stored, nil).Once()
+
+		err := ts.authUC.ConfirmSocialLink(context.TODO(), 22, &request.AuthSocialLinkConfirmBody{LinkToken: detail.LinkToken})
+		ts.Equal(403, err.(common.HttpError).ErrHttpCode)
+	})
+
+	ts.Run("만료된 연결 토큰", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.AnythingOfType("string")).Return("", nil).Once()
+
+		err := ts.authUC.ConfirmSocialLink(context.TODO(), 21, &request.AuthSocialLinkConfirmBody{LinkToken: detail.LinkToken})
+		ts.Equal(common.ErrSocialLinkTokenInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("기존 계정으로 로그인한 뒤 연결 확인", func() {
+		ts.mockStore.On("GetDel", mock.Anything, mock.AnythingOfType("string")).Return(stored, nil).Once()
+		ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.NaverSocialType, "naverKey").
+			Return(nil, &ent.NotFoundError{}).
+			Once()
+		ts.mockUserIdentityRepo.On("Create", mock.Anything, &ent.UserIdentity{
+			UserID:      21,
+			Provider:    model.NaverSocialType,
+			ProviderKey: "naverKey",
+			Email:       &email,
+		}).Return(&ent.UserIdentity{ID: 1}, nil).Once()
+
+		err := ts.authUC.ConfirmSocialLink(context.TODO(), 21, &request.AuthSocialLinkConfirmBody{LinkToken: detail.LinkToken})
+		ts.NoError(err)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestLinkSocial() {
+	ts.Run("다른 계정에 연결된 소셜 계정", func() {
+		ts.expectOAuthState(model.GoogleString, "verifier")
+		ts.mockGoogle.On("ExchangeCode", "linkCode", "state", "verifier").
+			Return(&oauth.Token{IdToken: "idToken"}, nil).Once()
+		ts.mockGoogle.On("FetchProfile", &oauth.Token{IdToken: "idToken"}).
+			Return(&oauth.Profile{Key: "googleKey"}, nil).Once()
+		ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.GoogleSocialType, "googleKey").
+			Return(&ent.User{ID: 99}, nil).Once()
+
+		err := ts.authUC.LinkSocial(context.TODO(), 31, model.GoogleString, "linkCode", "state")
+		ts.Equal(common.ErrSocialAlreadyLinked, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("이미 같은 업체가 연결됨", func() {
+		ts.expectOAuthState(model.GoogleString, "verifier")
+		ts.mockGoogle.On("ExchangeCode", "linkCode", "state", "verifier").
+			Return(&oauth.Token{IdToken: "idToken"}, nil).Once()
+		ts.mockGoogle.On("FetchProfile", &oauth.Token{IdToken: "idToken"}).
+			Return(&oauth.Profile{Key: "otherGoogleKey"}, nil).Once()
+		ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.GoogleSocialType, "otherGoogleKey").
+			Return(nil, &ent.NotFoundError{}).Once()
+		ts.mockUserIdentityRepo.On("Create", mock.Anything, mock.MatchedBy(func(i *ent.UserIdentity) bool {
+			return i.ProviderKey == "otherGoogleKey"
+		})).Return(nil, &ent.ConstraintError{}).Once()
+
+		err := ts.authUC.LinkSocial(context.TODO(), 31, model.GoogleString, "linkCode", "state")
+		ts.Equal(common.ErrSocialProviderAlreadyLinked, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("성공", func() {
+		ts.expectOAuthState(model.GoogleString, "verifier")
+		ts.mockGoogle.On("ExchangeCode", "linkCode", "state", "verifier").
+			Return(&oauth.Token{IdToken: "idToken"}, nil).Once()
+		ts.mockGoogle.On("FetchProfile", &oauth.Token{IdToken: "idToken"}).
+			Return(&oauth.Profile{Key: "newGoogleKey"}, nil).Once()
+		ts.mockUserIdentityRepo.On("GetUser", mock.Anything, model.GoogleSocialType, "newGoogleKey").
+			Return(nil, &ent.NotFoundError{}).Once()
+		ts.mockUserIdentityRepo.On("Create", mock.Anything, mock.MatchedBy(func(i *ent.UserIdentity) bool {
+			return i.UserID == 31 && i.ProviderKey == "newGoogleKey" && i.Email == nil
+		})).Return(&ent.UserIdentity{ID: 2}, nil).Once()
+
+		err := ts.authUC.LinkSocial(context.TODO(), 31, model.GoogleString, "linkCode", "state")
+		ts.NoError(err)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestUnlinkSocial() {
+	ts.Run("마지막 로그인 수단", func() {
+		ts.mockUserIdentityRepo.On("Delete", mock.Anything, 41, model.KakaoSocialType).
+			Return(errors.New(repository.ErrLastLoginMethod)).Once()
+
+		err := ts.authUC.UnlinkSocial(context.TODO(), 41, model.KakaoString)
+		ts.Equal(common.ErrLastLoginMethod, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("연결되지 않은 업체", func() {
+		ts.mockUserIdentityRepo.On("Delete", mock.Anything, 41, model.NaverSocialType).
+			Return(&ent.NotFoundError{}).Once()
+
+		err := ts.authUC.UnlinkSocial(context.TODO(), 41, model.NaverString)
+		ts.Equal(common.ErrSocialIdentityNotFound, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("성공", func() {
+		ts.mockUserIdentityRepo.On("Delete", mock.Anything, 41, model.GoogleSocialType).
+			Return(nil).Once()
+
+		err := ts.authUC.UnlinkSocial(context.TODO(), 41, model.GoogleString)
+		ts.NoError(err)
+	})
+}
+
 func (ts *AuthUCTestSuite) TestSocialSignup() {
 	ts.Run("email-already Exisit", func() {
 		email := "asd@naver.com"
@@ -531,6 +1323,234 @@ func (ts *AuthUCTestSuite) TestSocialSignup() {
 	})
 }
 
+// 2단계 인증을 통과하면 세션을 만들고 토큰을 발급한다.
+func (ts *AuthUCTestSuite) expectIssueTokens(userId int, loginType string, userType string) {
+	ts.mockTokenService.On("GenerateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), userId, loginType, userType, mock.AnythingOfType("int")).
+		Return("refreshToken", nil).Once()
+	ts.mockStore.On("HSet", mock.Anything, strconv.Itoa(userId), mock.AnythingOfType("string"), mock.MatchedBy(func(value string) bool {
+		return strings.Contains(value, `"twoFactor":true`)
+	}), mock.AnythingOfType("time.Duration")).
+		Return(nil).Once()
+	ts.mockTokenService.On("GenerateToken", mock.AnythingOfType("string"), userId, loginType, userType, true, mock.AnythingOfType("int")).
+		Return("AccessToken", nil).Once()
+	ts.mockTokenService.On("GetExpiredAt", mock.AnythingOfType("int")).Return(time.Now()).Twice()
+}
+
+func sha256Hex(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:])
+}
+
+const testTotpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func (ts *AuthUCTestSuite) TestLoginTwoFactor() {
+	userEmail := "totp@naver.com"
+	userPassword := "password"
+	hashedPassword := "$argon2id$totp"
+	enabledAt := time.Now()
+
+	ts.expectLoginNotLocked("login-lock:email:" + userEmail)
+	ts.mockStore.On("Del", mock.Anything, "login-fail:email:"+userEmail).Return(nil).Once()
+	ts.mockUserRepo.On("GetByEmail", mock.Anything, userEmail).Return(&ent.User{
+		ID:              30,
+		Email:           &userEmail,
+		Password:        &hashedPassword,
+		Type:            &model.AcademyType,
+		IsEmailVerified: true,
+		TotpEnabledAt:   &enabledAt,
+	}, nil).Once()
+	ts.mockAuthService.On("VerifyPassword", hashedPassword, userPassword).Return(true, false).Once()
+
+	var stored string
+	ts.mockStore.On("Set", mock.Anything,
+		mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "two-factor-challenge:") }),
+		mock.AnythingOfType("string"),
+		5*time.Minute,
+	).Run(func(args mock.Arguments) {
+		stored = args.String(2)
+	}).Return(nil).Once()
+
+	result, challenge, err := ts.authUC.Login(context.TODO(), &request.AuthLoginBody{
+		Email:    userEmail,
+		Password: userPassword,
+	}, nil)
+	ts.NoError(err)
+	ts.Nil(result)
+	ts.Len(challenge.ChallengeToken, 64)
+	ts.JSONEq(`{"userId":30,"userType":"academy","loginType":"normal"}`, stored)
+	ts.mockTokenService.AssertNotCalled(ts.T(), "GenerateToken", mock.Anything, 30, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *AuthUCTestSuite) TestVerifyTwoFactor() {
+	challengeToken := strings.Repeat("ef", 32)
+	challengeKey := "two-factor-challenge:" + sha256Hex(challengeToken)
+	attemptsKey := "two-factor-attempts:" + sha256Hex(challengeToken)
+	pending := `{"userId":31,"userType":"superAdmin","loginType":"normal"}`
+	secret := testTotpSecret
+	// DB 에는 암호화된 비밀키가 저장되어 있다.
+	sealedSecret, _ := ts.totpBox.Seal(secret)
+	enabledAt := time.Now()
+	user := &ent.User{ID: 31, TotpSecret: &sealedSecret, TotpEnabledAt: &enabledAt}
+
+	ts.Run("없는 challenge", func() {
+		ts.mockStore.On("Get", mock.Anything, challengeKey).Return("").Once()
+
+		_, err := ts.authUC.VerifyTwoFactor(context.TODO(), &request.AuthTwoFactorLoginBody{ChallengeToken: challengeToken, Code: "123456"}, nil)
+		ts.Equal(common.ErrTwoFactorChallengeInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("시도 횟수 초과", func() {
+		ts.mockStore.On("Get", mock.Anything, challengeKey).Return(pending).Once()
+		ts.mockStore.On("Incr", mock.Anything, attemptsKey, 5*time.Minute).Return(int64(6), nil).Once()
+		ts.mockStore.On("Del", mock.Anything, challengeKey).Return(nil).Once()
+
+		_, err := ts.authUC.VerifyTwoFactor(context.TODO(), &request.AuthTwoFactorLoginBody{ChallengeToken: challengeToken, Code: "123456"}, nil)
+		httpErr := err.(common.HttpError)
+		ts.Equal(429, httpErr.ErrHttpCode)
+		ts.Equal(common.ErrTwoFactorAttemptsExceeded, httpErr.ErrCode)
+	})
+
+	ts.Run("틀린 코드", func() {
+		ts.mockStore.On("Get", mock.Anything, challengeKey).Return(pending).Once()
+		ts.mockStore.On("Incr", mock.Anything, attemptsKey, 5*time.Minute).Return(int64(1), nil).Once()
+		ts.mockUserRepo.On("Get", mock.Anything, 31).Return(user, nil).Once()
+
+		// 6자리가 아니면 복구 코드로 확인한다.
+		ts.mockUserRepo.On("UseTotpRecoveryCode", mock.Anything, 31, sha256Hex("abcdefghij")).Return(false, nil).Once()
+
+		_, err := ts.authUC.VerifyTwoFactor(context.TODO(), &request.AuthTwoFactorLoginBody{ChallengeToken: challengeToken, Code: "ABCDE-FGHIJ"}, nil)
+		ts.Equal(common.ErrTotpCodeInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("이미 사용한 코드", func() {
+		code, _ := totp.Code(secret, time.Now())
+		ts.mockStore.On("Get", mock.Anything, challengeKey).Return(pending).Once()
+		ts.mockStore.On("Incr", mock.Anything, attemptsKey, 5*time.Minute).Return(int64(2), nil).Once()
+		ts.mockUserRepo.On("Get", mock.Anything, 31).Return(user, nil).Once()
+		ts.mockStore.On("SetNX", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "totp-used:31:") }), "1", 90*time.Second).Return(false, nil).Once()
+
+		_, err := ts.authUC.VerifyTwoFactor(context.TODO(), &request.AuthTwoFactorLoginBody{ChallengeToken: challengeToken, Code: code}, nil)
+		ts.Equal(common.ErrTotpCodeInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("성공", func() {
+		code, _ := totp.Code(secret, time.Now())
+		ts.mockStore.On("Get", mock.Anything, challengeKey).Return(pending).Once()
+		ts.mockStore.On("Incr", mock.Anything, attemptsKey, 5*time.Minute).Return(int64(3), nil).Once()
+		ts.mockUserRepo.On("Get", mock.Anything, 31).Return(user, nil).Once()
+		ts.mockStore.On("SetNX", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "totp-used:31:") }), "1", 90*time.Second).Return(true, nil).Once()
+		ts.mockStore.On("GetDel", mock.Anything, challengeKey).Return(pending, nil).Once()
+		ts.expectIssueTokens(31, "normal", "superAdmin")
+
+		result, err := ts.authUC.VerifyTwoFactor(context.TODO(), &request.AuthTwoFactorLoginBody{ChallengeToken: challengeToken, Code: code}, nil)
+		ts.NoError(err)
+		ts.Equal("AccessToken", result.AccessToken)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestSetupTwoFactor() {
+	email := "setup@naver.com"
+
+	ts.Run("이미 사용 중", func() {
+		enabledAt := time.Now()
+		ts.mockUserRepo.On("Get", mock.Anything, 32).Return(&ent.User{ID: 32, TotpEnabledAt: &enabledAt}, nil).Once()
+
+		_, err := ts.authUC.SetupTwoFactor(context.TODO(), 32)
+		ts.Equal(common.ErrTwoFactorAlreadyEnabled, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("비밀키 발급", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 33).Return(&ent.User{ID: 33, Email: &email}, nil).Once()
+		var stored string
+		ts.mockStore.On("Set", mock.Anything, "two-factor-setup:33", mock.AnythingOfType("string"), 10*time.Minute).
+			Run(func(args mock.Arguments) {
+				stored = args.String(2)
+			}).Return(nil).Once()
+
+		result, err := ts.authUC.SetupTwoFactor(context.TODO(), 33)
+		ts.NoError(err)
+		ts.Equal(stored, result.Secret)
+		ts.True(strings.HasPrefix(result.ProvisioningUri, "otpauth://totp/onthemat:setup@naver.com?"))
+		ts.Contains(result.ProvisioningUri, "secret="+result.Secret)
+	})
+}
+
+func (ts *AuthUCTestSuite) TestConfirmTwoFactor() {
+	ts.Run("등록 시간이 지남", func() {
+		ts.mockStore.On("Get", mock.Anything, "two-factor-setup:34").Return("").Once()
+
+		_, err := ts.authUC.ConfirmTwoFactor(context.TODO(), 34, "current", &request.AuthTwoFactorConfirmBody{Code: "123456"})
+		ts.Equal(common.ErrTwoFactorSetupExpired, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("틀린 코드", func() {
+		code, _ := totp.Code(testTotpSecret, time.Now().Add(-5*time.Minute))
+		ts.mockStore.On("Get", mock.Anything, "two-factor-setup:34").Return(testTotpSecret).Once()
+
+		_, err := ts.authUC.ConfirmTwoFactor(context.TODO(), 34, "current", &request.AuthTwoFactorConfirmBody{Code: code})
+		ts.Equal(common.ErrTotpCodeInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("켜고 복구 코드 발급", func() {
+		code, _ := totp.Code(testTotpSecret, time.Now())
+		ts.mockStore.On("Get", mock.Anything, "two-factor-setup:34").Return(testTotpSecret).Once()
+		ts.mockStore.On("SetNX", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "totp-used:34:") }), "1", 90*time.Second).Return(true, nil).Once()
+		var hashes []string
+		// 비밀키는 암호화해서 저장한다.
+		sealed := mock.MatchedBy(func(v string) bool {
+			plaintext, err := ts.totpBox.Open(v)
+			return v != testTotpSecret && err == nil && plaintext == testTotpSecret
+		})
+		ts.mockUserRepo.On("EnableTotp", mock.Anything, 34, sealed, mock.AnythingOfType("[]string"), mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) {
+				hashes = args.Get(3).([]string)
+			}).Return(nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "two-factor-setup:34").Return(nil).Once()
+		// 비밀번호만으로 로그인한 다른 세션은 폐기한다.
+		ts.mockStore.On("HGetAll", mock.Anything, "34").Return(map[string]string{"current": "{}", "other": "{}"}, nil).Once()
+		ts.mockStore.On("HDel", mock.Anything, "34", "other").Return(nil).Once()
+
+		recoveryCodes, err := ts.authUC.ConfirmTwoFactor(context.TODO(), 34, "current", &request.AuthTwoFactorConfirmBody{Code: code})
+		ts.NoError(err)
+		ts.Len(recoveryCodes, 10)
+		ts.Regexp(`^[a-z2-7]{5}-[a-z2-7]{5}$`, recoveryCodes[0])
+		// 복구 코드는 해시만 저장한다.
+		ts.Equal(sha256Hex(strings.ReplaceAll(recoveryCodes[0], "-", "")), hashes[0])
+		ts.mockStore.AssertCalled(ts.T(), "HDel", mock.Anything, "34", "other")
+		ts.mockStore.AssertNotCalled(ts.T(), "HDel", mock.Anything, "34", "current")
+	})
+}
+
+func (ts *AuthUCTestSuite) TestDisableTwoFactor() {
+	secret, _ := ts.totpBox.Seal(testTotpSecret)
+	enabledAt := time.Now()
+
+	ts.Run("관리자는 끌 수 없음", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 35).Return(&ent.User{ID: 35, Type: &model.SuperAdminType, TotpSecret: &secret, TotpEnabledAt: &enabledAt}, nil).Once()
+
+		err := ts.authUC.DisableTwoFactor(context.TODO(), 35, &request.AuthTwoFactorCodeBody{Code: "abcde-fghij"})
+		httpErr := err.(common.HttpError)
+		ts.Equal(403, httpErr.ErrHttpCode)
+		ts.Equal(common.ErrTwoFactorRequired, httpErr.ErrCode)
+	})
+
+	ts.Run("사용하지 않는 유저", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 36).Return(&ent.User{ID: 36}, nil).Once()
+
+		err := ts.authUC.DisableTwoFactor(context.TODO(), 36, &request.AuthTwoFactorCodeBody{Code: "abcde-fghij"})
+		ts.Equal(common.ErrTwoFactorNotEnabled, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("복구 코드로 끄기", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 37).Return(&ent.User{ID: 37, Type: &model.AcademyType, TotpSecret: &secret, TotpEnabledAt: &enabledAt}, nil).Once()
+		ts.mockUserRepo.On("UseTotpRecoveryCode", mock.Anything, 37, sha256Hex("abcdefghij")).Return(true, nil).Once()
+		ts.mockUserRepo.On("DisableTotp", mock.Anything, 37).Return(nil).Once()
+
+		err := ts.authUC.DisableTwoFactor(context.TODO(), 37, &request.AuthTwoFactorCodeBody{Code: "abcde-fghij"})
+		ts.NoError(err)
+	})
+}
+
 func TestAuthUCTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthUCTestSuite))
 }
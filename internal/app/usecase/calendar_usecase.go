@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	ex "onthemat/internal/app/common"
+	"onthemat/internal/app/model"
+	"onthemat/internal/app/repository"
+	"onthemat/pkg/ent"
+	"onthemat/pkg/ical"
+)
+
+const calendarProdId = "-//onthemat//substitute calendar//KO"
+
+type CalendarUsecase interface {
+	IssueToken(ctx context.Context, userId int) (token string, err error)
+	TeacherCalendar(ctx context.Context, token string) (result []byte, err error)
+	AcademyCalendar(ctx context.Context, token string) (result []byte, err error)
+}
+
+type calendarUsecase struct {
+	userRepo    repository.UserRepository
+	teacherRepo repository.TeacherRepository
+	academyRepo repository.AcademyRepository
+	recruitRepo repository.RecruitmentRepository
+}
+
+func NewCalendarUsecase(
+	userRepo repository.UserRepository,
+	teacherRepo repository.TeacherRepository,
+	academyRepo repository.AcademyRepository,
+	recruitRepo repository.RecruitmentRepository,
+) CalendarUsecase {
+	return &calendarUsecase{
+		userRepo:    userRepo,
+		teacherRepo: teacherRepo,
+		academyRepo: academyRepo,
+		recruitRepo: recruitRepo,
+	}
+}
+
+// 구독용 토큰을 새로 발급한다. 기존 토큰으로 구독한 캘린더는 더 이상 갱신되지 않는다.
+func (u *calendarUsecase) IssueToken(ctx context.Context, userId int) (token string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	token = hex.EncodeToString(b)
+
+	err = u.userRepo.UpdateCalendarToken(ctx, userId, hashCalendarToken(token))
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+			return
+		}
+		return
+	}
+	return
+}
+
+func (u *calendarUsecase) TeacherCalendar(ctx context.Context, token string) (result []byte, err error) {
+	user, err := u.getUserByToken(ctx, token, model.TeacherType)
+	if err != nil {
+		return
+	}
+
+	teacherId, err := u.teacherRepo.GetOnlyIdByUserId(ctx, user.ID)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrTeacherNotFound, nil)
+			return
+		}
+		return
+	}
+
+	insteads, err := u.recruitRepo.PasserScheduleList(ctx, teacherId)
+	if err != nil {
+		return
+	}
+
+	calendar := ical.NewCalendar(calendarProdId, "온더매트 대강 일정")
+	for _, v := range insteads {
+		summary := "대강"
+		location := ""
+		if academy := v.Edges.Recuritment.Edges.Writer; academy != nil {
+			summary = fmt.Sprintf("대강 - %s", academy.Name)
+			location = academy.AddressRoad
+			if academy.AddressDetail != nil {
+				location += " " + *academy.AddressDetail
+			}
+		}
+
+		addScheduleEvents(calendar, v, summary, location, ical.StatusConfirmed)
+	}
+
+	result = calendar.Bytes()
+	return
+}
+
+func (u *calendarUsecase) AcademyCalendar(ctx context.Context, token string) (result []byte, err error) {
+	user, err := u.getUserByToken(ctx, token, model.AcademyType)
+	if err != nil {
+		return
+	}
+
+	academyId, err := u.academyRepo.GetOnlyIdByUserId(ctx, user.ID)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrAcademyNotFound, nil)
+			return
+		}
+		return
+	}
+
+	insteads, err := u.recruitRepo.WriterScheduleList(ctx, academyId)
+	if err != nil {
+		return
+	}
+
+	calendar := ical.NewCalendar(calendarProdId, "온더매트 대강 모집 일정")
+	for _, v := range insteads {
+		summary := "대강 모집중"
+		status := ical.StatusTentative
+		if passer := v.Edges.Passer; passer != nil {
+			summary = fmt.Sprintf("대강 확정 - %s", passer.Name)
+			status = ical.StatusConfirmed
+		} else if v.Edges.Recuritment.IsFinish {
+			summary = "대강 모집 마감"
+			status = ical.StatusCancelled
+		}
+
+		addScheduleEvents(calendar, v, summary, "", status)
+	}
+
+	result = calendar.Bytes()
+	return
+}
+
+// DB 에는 토큰 원문이 아닌 해시를 저장한다.
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *calendarUsecase) getUserByToken(ctx context.Context, token string, userType model.UserType) (result *ent.User, err error) {
+	if token == "" {
+		err = ex.NewUnauthorizedError(ex.ErrTokenInvalid, nil)
+		return
+	}
+
+	result, err = u.userRepo.GetByCalendarToken(ctx, hashCalendarToken(token))
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewUnauthorizedError(ex.ErrTokenInvalid, nil)
+			return
+		}
+		return
+	}
+
+	if result.Type == nil || *result.Type != userType {
+		switch userType {
+		case model.TeacherType:
+			err = ex.NewForbiddenError(ex.ErrOnlyTeacher, nil)
+		default:
+			err = ex.NewForbiddenError(ex.ErrOnlyAcademy, nil)
+		}
+		return
+	}
+	return
+}
+
+func addScheduleEvents(calendar *ical.Calendar, instead *ent.RecruitmentInstead, summary, location, status string) {
+	// 일정은 Asia/Seoul 기준 벽시계 시간으로 저장되어 있다.
+	loc, err := time.LoadLocation(model.DefaultTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	for _, s := range instead.Edges.Schedules {
+		calendar.AddEvent(&ical.Event{
+			UID:          fmt.Sprintf("instead-%d-schedule-%d@onthemat", instead.ID, s.ID),
+			Summary:      summary,
+			Location:     location,
+			Status:       status,
+			Start:        model.InLocation(s.StartDateTime, loc),
+			End:          model.InLocation(s.EndDateTime, loc),
+			Stamp:        time.Time(instead.UpdatedAt),
+			LastModified: time.Time(instead.UpdatedAt),
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ex "onthemat/internal/app/common"
+	"onthemat/internal/app/mocks"
+	"onthemat/internal/app/model"
+	"onthemat/internal/app/transport"
+	"onthemat/internal/app/usecase"
+	"onthemat/pkg/ent"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CalendarUCTestSuite struct {
+	suite.Suite
+	calendarUC      usecase.CalendarUsecase
+	mockUserRepo    *mocks.UserRepository
+	mockTeacherRepo *mocks.TeacherRepository
+	mockAcademyRepo *mocks.AcademyRepository
+	mockRecruitRepo *mocks.RecruitmentRepository
+}
+
+// 각 테스트 시작 전 N회
+func (ts *CalendarUCTestSuite) SetupTest() {
+	ts.mockUserRepo = new(mocks.UserRepository)
+	ts.mockTeacherRepo = new(mocks.TeacherRepository)
+	ts.mockAcademyRepo = new(mocks.AcademyRepository)
+	ts.mockRecruitRepo = new(mocks.RecruitmentRepository)
+	ts.calendarUC = usecase.NewCalendarUsecase(ts.mockUserRepo, ts.mockTeacherRepo, ts.mockAcademyRepo, ts.mockRecruitRepo)
+}
+
+// ------------------- Test Case -------------------
+
+func (ts *CalendarUCTestSuite) TestIssueToken() {
+	var stored string
+	ts.mockUserRepo.On("UpdateCalendarToken", mock.Anything, 1, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) {
+			stored = args.String(2)
+		}).Return(nil).Once()
+
+	token, err := ts.calendarUC.IssueToken(context.Background(), 1)
+	ts.NoError(err)
+	ts.Len(token, 64)
+	// 원문이 아닌 해시만 저장한다.
+	ts.Equal(sha256Hex(token), stored)
+}
+
+func (ts *CalendarUCTestSuite) TestTeacherCalendar() {
+	ts.Run("유효하지 않은 토큰", func() {
+		ts.mockUserRepo.On("GetByCalendarToken", mock.Anything, sha256Hex("invalid")).
+			Return(nil, &ent.NotFoundError{}).Once()
+
+		_, err := ts.calendarUC.TeacherCalendar(context.Background(), "invalid")
+		ts.Equal(ex.ErrTokenInvalid, err.(ex.HttpError).ErrCode)
+	})
+
+	ts.Run("선생님이 아닌 유저", func() {
+		userType := model.AcademyType
+		ts.mockUserRepo.On("GetByCalendarToken", mock.Anything, sha256Hex("academy")).
+			Return(&ent.User{ID: 1, Type: &userType}, nil).Once()
+
+		_, err := ts.calendarUC.TeacherCalendar(context.Background(), "academy")
+		ts.Equal(ex.ErrOnlyTeacher, err.(ex.HttpError).ErrCode)
+	})
+
+	ts.Run("성공", func() {
+		userType := model.TeacherType
+		ts.mockUserRepo.On("GetByCalendarToken", mock.Anything, sha256Hex("teacher")).
+			Return(&ent.User{ID: 1, Type: &userType}, nil).Once()
+		ts.mockTeacherRepo.On("GetOnlyIdByUserId", mock.Anything, 1).
+			Return(3, nil).Once()
+
+		start := transport.TimeString(time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC))
+		end := transport.TimeString(time.Date(2026, 11, 2, 12, 0, 0, 0, time.UTC))
+		insteads := []*ent.RecruitmentInstead{{
+			ID: 7,
+			Edges: ent.RecruitmentInsteadEdges{
+				Recuritment: &ent.Recruitment{
+					Edges: ent.RecruitmentEdges{
+						Writer: &ent.Academy{Name: "온더매트 요가", AddressRoad: "서울 강남구 테헤란로 1"},
+					},
+				},
+				Schedules: []*ent.RecruitmentInsteadSchedule{
+					{ID: 11, StartDateTime: start, EndDateTime: end},
+				},
+			},
+		}}
+		ts.mockRecruitRepo.On("PasserScheduleList", mock.Anything, 3).
+			Return(insteads, nil).Once()
+
+		result, err := ts.calendarUC.TeacherCalendar(context.Background(), "teacher")
+		ts.NoError(err)
+
+		body := string(result)
+		ts.True(strings.Contains(body, "BEGIN:VEVENT"))
+		ts.True(strings.Contains(body, "UID:instead-7-schedule-11@onthemat"))
+		// Asia/Seoul 10시는 UTC 01시
+		ts.True(strings.Contains(body, "DTSTART:20261102T010000Z"))
+		ts.True(strings.Contains(body, "SUMMARY:대강 - 온더매트 요가"))
+	})
+}
+
+func TestCalendarUCTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarUCTestSuite))
+}
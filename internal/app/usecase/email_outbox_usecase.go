@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"onthemat/internal/app/repository"
+	"onthemat/internal/app/service"
+)
+
+const (
+	// 한 번에 보내는 최대 메일 수
+	emailOutboxBatchSize = 50
+	// 이 횟수만큼 실패하면 더 이상 재시도하지 않는다. (dead)
+	emailOutboxMaxAttempts = 8
+	// 재시도 간격은 1분, 2분, 4분 ... 으로 늘어나고 최대 6시간이다.
+	emailOutboxBaseBackoff = time.Minute
+	emailOutboxMaxBackoff  = 6 * time.Hour
+	// 가져간 메일을 다른 인스턴스가 다시 가져가지 못하는 시간.
+	// 한 번에 가져간 메일을 모두 보내는 최악의 시간(50통 x SMTP 제한 시간 30초)보다 길어야 한다.
+	emailOutboxClaimLease = 30 * time.Minute
+)
+
+type EmailOutboxUsecase interface {
+	// 보낼 때가 된 메일을 보내고, 보낸 메일 수를 반환한다. 실패한 메일은 다음 시도 시간을 늦춘다.
+	Dispatch(ctx context.Context, now time.Time) (count int, err error)
+}
+
+type emailOutboxUsecase struct {
+	emailOutboxRepo repository.EmailOutboxRepository
+	mailSvc         service.MailService
+}
+
+func NewEmailOutboxUsecase(emailOutboxRepo repository.EmailOutboxRepository, mailSvc service.MailService) EmailOutboxUsecase {
+	return &emailOutboxUsecase{
+		emailOutboxRepo: emailOutboxRepo,
+		mailSvc:         mailSvc,
+	}
+}
+
+func (u *emailOutboxUsecase) Dispatch(ctx context.Context, now time.Time) (count int, err error) {
+	mails, err := u.emailOutboxRepo.ClaimDue(ctx, now, emailOutboxClaimLease, emailOutboxBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, mail := range mails {
+		if errS := u.mailSvc.Send(mail.Recipient, mail.Template, mail.Data); errS != nil {
+			attempts := mail.Attempts + 1
+			dead := attempts >= emailOutboxMaxAttempts
+			if err = u.emailOutboxRepo.MarkFailed(ctx, mail.ID, now.Add(emailOutboxBackoff(attempts)), errS.Error(), dead); err != nil {
+				return
+			}
+			continue
+		}
+
+		if err = u.emailOutboxRepo.MarkSent(ctx, mail.ID, now); err != nil {
+			return
+		}
+		count++
+	}
+	return
+}
+
+// attempts 번 실패한 뒤 다음 시도까지 기다리는 시간
+func emailOutboxBackoff(attempts int) time.Duration {
+	backoff := emailOutboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= emailOutboxMaxBackoff {
+			return emailOutboxMaxBackoff
+		}
+	}
+	return backoff
+}
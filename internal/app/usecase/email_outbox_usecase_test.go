@@ -0,0 +1,81 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/mocks"
+	"onthemat/internal/app/service"
+	"onthemat/internal/app/usecase"
+	"onthemat/pkg/ent"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EmailOutboxUsecaseTestSuite struct {
+	suite.Suite
+	emailOutboxUsecase  usecase.EmailOutboxUsecase
+	mockEmailOutboxRepo *mocks.EmailOutboxRepository
+	mockMailService     *mocks.MailService
+}
+
+// 각 테스트 시작 전 N회
+func (ts *EmailOutboxUsecaseTestSuite) SetupTest() {
+	ts.mockEmailOutboxRepo = new(mocks.EmailOutboxRepository)
+	ts.mockMailService = new(mocks.MailService)
+	ts.emailOutboxUsecase = usecase.NewEmailOutboxUsecase(ts.mockEmailOutboxRepo, ts.mockMailService)
+}
+
+// ------------------- Test Case -------------------
+
+func (ts *EmailOutboxUsecaseTestSuite) TestDispatch() {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	data := map[string]string{"resetUrl": "https://onthemat.com/password/reset?token=abc"}
+
+	ts.Run("보낸 메일은 sent, 실패한 메일은 다음 시도 시간을 늦춘다", func() {
+		ts.mockEmailOutboxRepo.On("ClaimDue", mock.Anything, now, 30*time.Minute, 50).Return([]*ent.EmailOutbox{
+			{ID: 1, Recipient: "a@onthemat.com", Template: service.MailPasswordReset, Data: data},
+			{ID: 2, Recipient: "b@onthemat.com", Template: service.MailPasswordReset, Data: data, Attempts: 3},
+		}, nil).Once()
+		ts.mockMailService.On("Send", "a@onthemat.com", service.MailPasswordReset, data).Return(nil).Once()
+		ts.mockMailService.On("Send", "b@onthemat.com", service.MailPasswordReset, data).Return(errors.New("421 try again later")).Once()
+		ts.mockEmailOutboxRepo.On("MarkSent", mock.Anything, 1, now).Return(nil).Once()
+		// 네 번째 실패 -> 8분 뒤
+		ts.mockEmailOutboxRepo.On("MarkFailed", mock.Anything, 2, now.Add(8*time.Minute), "421 try again later", false).Return(nil).Once()
+
+		count, err := ts.emailOutboxUsecase.Dispatch(context.Background(), now)
+		ts.NoError(err)
+		ts.Equal(1, count)
+		ts.mockEmailOutboxRepo.AssertExpectations(ts.T())
+		ts.mockMailService.AssertExpectations(ts.T())
+	})
+
+	ts.Run("최대 횟수만큼 실패하면 dead", func() {
+		ts.mockEmailOutboxRepo.On("ClaimDue", mock.Anything, now, 30*time.Minute, 50).Return([]*ent.EmailOutbox{
+			{ID: 3, Recipient: "c@onthemat.com", Template: service.MailVerifyEmail, Attempts: 7},
+		}, nil).Once()
+		ts.mockMailService.On("Send", "c@onthemat.com", service.MailVerifyEmail, map[string]string(nil)).Return(errors.New("550 no such user")).Once()
+		ts.mockEmailOutboxRepo.On("MarkFailed", mock.Anything, 3, now.Add(128*time.Minute), "550 no such user", true).Return(nil).Once()
+
+		count, err := ts.emailOutboxUsecase.Dispatch(context.Background(), now)
+		ts.NoError(err)
+		ts.Equal(0, count)
+		ts.mockEmailOutboxRepo.AssertExpectations(ts.T())
+	})
+
+	ts.Run("보낼 메일이 없음", func() {
+		ts.mockEmailOutboxRepo.On("ClaimDue", mock.Anything, now, 30*time.Minute, 50).Return([]*ent.EmailOutbox{}, nil).Once()
+
+		count, err := ts.emailOutboxUsecase.Dispatch(context.Background(), now)
+		ts.NoError(err)
+		ts.Equal(0, count)
+		ts.mockMailService.AssertNumberOfCalls(ts.T(), "Send", 3)
+	})
+}
+
+func TestEmailOutboxUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailOutboxUsecaseTestSuite))
+}
@@ -2,10 +2,13 @@ package usecase
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	ex "onthemat/internal/app/common"
 	"onthemat/internal/app/model"
 	"onthemat/internal/app/repository"
+	"onthemat/internal/app/transport"
 	"onthemat/internal/app/transport/request"
 	"onthemat/internal/app/utils"
 	"onthemat/pkg/ent"
@@ -18,6 +21,24 @@ type RecruitmentUsecase interface {
 	SoftDelete(ctx context.Context, id, academyId int) (err error)
 	List(ctx context.Context, a *request.RecruitmentListQueries) (result []*ent.Recruitment, paginationInfo *utils.PagenationInfo, err error)
 	Get(ctx context.Context, id int) (result *ent.Recruitment, err error)
+
+	// 대강 지원
+	Apply(ctx context.Context, id, insteadId, teacherId int) (err error)
+	CancelApply(ctx context.Context, id, insteadId, teacherId int) (err error)
+	ApplicantList(ctx context.Context, id, insteadId, academyId int) (result []*ent.RecruitmentApplication, err error)
+
+	// 지원자 선발
+	ChangeApplicationStatus(ctx context.Context, id, insteadId, teacherId, academyId int, status model.ApplicationStatus) (err error)
+
+	// 지원 전 이미 합격한 대강과 시간이 겹치는지 확인
+	ScheduleConflicts(ctx context.Context, id, insteadId, teacherId int) (result []*ent.RecruitmentInstead, err error)
+
+	// 모든 일정이 지난 공고 자동 마감
+	FinishExpired(ctx context.Context, now time.Time) (count int, err error)
+
+	// 대강 일정 개별 수정 / 삭제
+	UpdateSchedule(ctx context.Context, d *request.RecruitmentScheduleBody, id, insteadId, scheduleId, academyId int) (err error)
+	DeleteSchedule(ctx context.Context, id, insteadId, scheduleId, academyId int) (err error)
 }
 
 type recruitmentUsecase struct {
@@ -36,18 +57,21 @@ func (u *recruitmentUsecase) Create(ctx context.Context, d *request.RecruitmentC
 	// Prepare Data
 	insteadInfo := make([]*ent.RecruitmentInstead, 0)
 	for _, v := range d.InsteadInfo {
-		schedules := make([]*model.Schedule, 0)
-
-		for _, s := range v.Schedules {
-			schedules = append(schedules, &model.Schedule{
-				StartDateTime: s.StartDateTime,
-				EndDateTime:   s.EndDateTime,
-			})
+		schedules, recurrence, errR := newInsteadSchedules(v.Schedules, v.Recurrence)
+		if errR != nil {
+			err = ex.NewBadRequestError(ex.ErrRecurrenceInvalid, errR.Error())
+			return
 		}
+
 		insteadInfo = append(insteadInfo, &ent.RecruitmentInstead{
-			MinCareer: v.MinCareer,
-			Pay:       v.Pay,
-			Schedule:  schedules,
+			MinCareerMonth: v.MinCareerMonth,
+			PayAmount:      v.PayAmount,
+			PayCurrency:    payCurrency(v.PayCurrency),
+			PayUnit:        *new(model.PayUnit).ToPayUnit(&v.PayUnit),
+			Recurrence:     recurrence,
+			Edges: ent.RecruitmentInsteadEdges{
+				Schedules: schedules,
+			},
 		})
 	}
 
@@ -79,19 +103,22 @@ func (u *recruitmentUsecase) Update(ctx context.Context, d *request.RecruitmentU
 	// Prepare Data
 	insteadInfo := make([]*ent.RecruitmentInstead, 0)
 	for _, v := range d.InsteadInfo {
-		schedules := make([]*model.Schedule, 0)
-
-		for _, s := range v.Schedules {
-			schedules = append(schedules, &model.Schedule{
-				StartDateTime: s.StartDateTime,
-				EndDateTime:   s.EndDateTime,
-			})
+		schedules, recurrence, errR := newInsteadSchedules(v.Schedules, v.Recurrence)
+		if errR != nil {
+			err = ex.NewBadRequestError(ex.ErrRecurrenceInvalid, errR.Error())
+			return
 		}
+
 		insteadInfo = append(insteadInfo, &ent.RecruitmentInstead{
-			ID:        v.Id,
-			MinCareer: v.MinCareer,
-			Pay:       v.Pay,
-			Schedule:  schedules,
+			ID:             v.Id,
+			MinCareerMonth: v.MinCareerMonth,
+			PayAmount:      v.PayAmount,
+			PayCurrency:    payCurrency(v.PayCurrency),
+			PayUnit:        *new(model.PayUnit).ToPayUnit(&v.PayUnit),
+			Recurrence:     recurrence,
+			Edges: ent.RecruitmentInsteadEdges{
+				Schedules: schedules,
+			},
 		})
 	}
 
@@ -133,6 +160,10 @@ func (u *recruitmentUsecase) Update(ctx context.Context, d *request.RecruitmentU
 func (u *recruitmentUsecase) Patch(ctx context.Context, d *request.RecruitmentPatchBody, id, academyId int) (isUpdated bool, err error) {
 	isCreated, err := u.recruitRepo.Patch(ctx, d, id, academyId)
 	if err != nil {
+		if err.Error() == repository.ErrOnlyOwnUser {
+			err = ex.NewConflictError(ex.ErrResourceUnOwned, nil)
+			return
+		}
 		if ent.IsConstraintError(err) {
 			err = ex.NewConflictError(ex.ErrConflict, nil)
 			return
@@ -143,17 +174,40 @@ func (u *recruitmentUsecase) Patch(ctx context.Context, d *request.RecruitmentPa
 	return
 }
 
+const ErrPayUnitRequired = "급여로 조회하거나 정렬하려면 급여 단위(payUnit)가 필요합니다"
+
 func (u *recruitmentUsecase) List(ctx context.Context, a *request.RecruitmentListQueries) (result []*ent.Recruitment, paginationInfo *utils.PagenationInfo, err error) {
 	paginationModule := utils.NewPagination(a.PageNo, a.PageSize)
 
 	pginationModule := utils.NewPagination(a.PageNo, a.PageSize)
-	total, err := u.recruitRepo.Total(ctx, a.StartDateTime, a.EndDateTime, a.YogaIDs, a.SigunguIds)
+	if a.OrderCol != nil {
+		*a.OrderCol = strings.ToUpper(*a.OrderCol)
+	}
+
+	payUnit := new(model.PayUnit).ToPayUnit(a.PayUnit)
+	// 급여 단위끼리는 환산할 수 없으므로 급여로 거르거나 정렬할 때는 단위가 필요하다.
+	if payUnit == nil && (a.MinPay != nil || (a.OrderCol != nil && *a.OrderCol == "PAY")) {
+		err = ex.NewBadRequestError(ex.ErrReqeustsInvalid, ErrPayUnitRequired)
+		return
+	}
+
+	total, err := u.recruitRepo.Total(ctx,
+		a.StartDateTime, a.EndDateTime, a.YogaIDs, a.SigunguIds, a.SidoIds,
+		a.MinPay, payUnit, a.MaxCareer, a.IsFinish, a.HasOpenSlot)
 	if err != nil {
 		return
 	}
 
+	orderType := ex.DESC
+	if a.OrderType != nil && *a.OrderType == string(ex.ASC) {
+		orderType = ex.ASC
+	}
+
 	pginationModule.SetTotal(total)
-	result, err = u.recruitRepo.List(ctx, paginationModule, a.StartDateTime, a.EndDateTime, a.YogaIDs, a.SigunguIds)
+	result, err = u.recruitRepo.List(ctx, paginationModule,
+		a.StartDateTime, a.EndDateTime, a.YogaIDs, a.SigunguIds, a.SidoIds,
+		a.MinPay, payUnit, a.MaxCareer, a.IsFinish, a.HasOpenSlot,
+		a.OrderCol, orderType)
 	if err != nil {
 		return
 	}
@@ -190,3 +244,296 @@ func (u *recruitmentUsecase) SoftDelete(ctx context.Context, id, academyId int)
 
 	return
 }
+
+func (u *recruitmentUsecase) Apply(ctx context.Context, id, insteadId, teacherId int) (err error) {
+	instead, err := u.getInstead(ctx, id, insteadId)
+	if err != nil {
+		return
+	}
+
+	recruit := instead.Edges.Recuritment
+	if recruit.IsFinish || !recruit.IsOpen || instead.TeacherID != nil {
+		err = ex.NewConflictError(ex.ErrRecruitmentAlreadyClosed, nil)
+		return
+	}
+
+	application, err := u.recruitRepo.GetApplication(ctx, insteadId, teacherId)
+	if err != nil && !ent.IsNotFound(err) {
+		return
+	}
+
+	// 처음 지원하는 경우
+	if application == nil {
+		if err = u.recruitRepo.CreateApplication(ctx, insteadId, teacherId); err != nil {
+			if ent.IsConstraintError(err) {
+				err = ex.NewConflictError(ex.ErrAlreadyApplied, nil)
+			}
+			return
+		}
+		return
+	}
+
+	// 지원을 취소했던 경우에만 재지원 가능
+	if !application.Status.CanTransitionTo(model.ApplicationApplied) {
+		err = ex.NewConflictError(ex.ErrAlreadyApplied, nil)
+		return
+	}
+
+	err = u.recruitRepo.UpdateApplicationStatus(ctx, insteadId, teacherId, model.ApplicationApplied)
+	return
+}
+
+func (u *recruitmentUsecase) CancelApply(ctx context.Context, id, insteadId, teacherId int) (err error) {
+	if _, err = u.getInstead(ctx, id, insteadId); err != nil {
+		return
+	}
+
+	application, err := u.getApplication(ctx, insteadId, teacherId)
+	if err != nil {
+		return
+	}
+
+	if !application.Status.CanTransitionTo(model.ApplicationWithdrawn) {
+		err = ex.NewConflictError(ex.ErrApplicationStatusInvalid, application.Status.ToString())
+		return
+	}
+
+	err = u.recruitRepo.UpdateApplicationStatus(ctx, insteadId, teacherId, model.ApplicationWithdrawn)
+	return
+}
+
+func (u *recruitmentUsecase) ApplicantList(ctx context.Context, id, insteadId, academyId int) (result []*ent.RecruitmentApplication, err error) {
+	instead, err := u.getInstead(ctx, id, insteadId)
+	if err != nil {
+		return
+	}
+
+	if instead.Edges.Recuritment.AcademyID != academyId {
+		err = ex.NewForbiddenError(ex.ErrOnlyOwnUser, nil)
+		return
+	}
+
+	result, err = u.recruitRepo.ApplicationList(ctx, insteadId)
+	return
+}
+
+// 학원이 지원자의 상태를 변경 (shortlisted, accepted, rejected, cancelled)
+func (u *recruitmentUsecase) ChangeApplicationStatus(ctx context.Context, id, insteadId, teacherId, academyId int, status model.ApplicationStatus) (err error) {
+	instead, err := u.getInstead(ctx, id, insteadId)
+	if err != nil {
+		return
+	}
+
+	if instead.Edges.Recuritment.AcademyID != academyId {
+		err = ex.NewForbiddenError(ex.ErrOnlyOwnUser, nil)
+		return
+	}
+
+	application, err := u.getApplication(ctx, insteadId, teacherId)
+	if err != nil {
+		return
+	}
+
+	if status == model.ApplicationWithdrawn || !application.Status.CanTransitionTo(status) {
+		err = ex.NewConflictError(ex.ErrApplicationStatusInvalid, application.Status.ToString())
+		return
+	}
+
+	switch status {
+	case model.ApplicationAccepted:
+		if instead.TeacherID != nil {
+			err = ex.NewConflictError(ex.ErrInsteadAlreadyFilled, nil)
+			return
+		}
+
+		// 일정 겹침은 합격 처리와 같은 트랜잭션에서 확인한다.
+		conflicts, errA := u.recruitRepo.AcceptApplication(ctx, insteadId, teacherId)
+		if errA != nil {
+			err = errA
+			break
+		}
+
+		if len(conflicts) > 0 {
+			err = ex.NewConflictError(ex.ErrScheduleConflict, conflictInsteadIds(conflicts))
+			return
+		}
+
+	case model.ApplicationCancelled:
+		err = u.recruitRepo.CancelAcceptedApplication(ctx, insteadId, teacherId)
+
+	default:
+		err = u.recruitRepo.UpdateApplicationStatus(ctx, insteadId, teacherId, status)
+	}
+
+	if err != nil {
+		switch err.Error() {
+		case repository.ErrInsteadAlreadyFilled:
+			err = ex.NewConflictError(ex.ErrInsteadAlreadyFilled, nil)
+		case repository.ErrApplicationStatusChanged:
+			err = ex.NewConflictError(ex.ErrApplicationStatusInvalid, nil)
+		}
+		return
+	}
+	return
+}
+
+func (u *recruitmentUsecase) ScheduleConflicts(ctx context.Context, id, insteadId, teacherId int) (result []*ent.RecruitmentInstead, err error) {
+	instead, err := u.getInstead(ctx, id, insteadId)
+	if err != nil {
+		return
+	}
+
+	result, err = u.scheduleConflicts(ctx, instead, teacherId)
+	return
+}
+
+// 선생님이 합격한 다른 대강 중 스케쥴이 겹치는 대강들
+func (u *recruitmentUsecase) scheduleConflicts(ctx context.Context, instead *ent.RecruitmentInstead, teacherId int) (result []*ent.RecruitmentInstead, err error) {
+	return u.recruitRepo.OverlappedPasserInsteadList(ctx, teacherId, instead.ID)
+}
+
+// 통화를 입력하지 않으면 원화로
+func payCurrency(v string) string {
+	if v == "" {
+		return model.DefaultPayCurrency
+	}
+	return v
+}
+
+// 반복 규칙이 있으면 규칙을 펼친 일정들을, 없으면 입력받은 일정들을 사용한다.
+func newInsteadSchedules(vals []request.Schedule, r *request.Recurrence) (result []*ent.RecruitmentInsteadSchedule, recurrence *model.Recurrence, err error) {
+	result = make([]*ent.RecruitmentInsteadSchedule, 0)
+
+	if r == nil {
+		for _, s := range vals {
+			result = append(result, &ent.RecruitmentInsteadSchedule{
+				StartDateTime: s.StartDateTime,
+				EndDateTime:   s.EndDateTime,
+			})
+		}
+		return
+	}
+
+	recurrence = &model.Recurrence{
+		Frequency:     r.Frequency,
+		Interval:      r.Interval,
+		Weekdays:      r.Weekdays,
+		Count:         r.Count,
+		Until:         r.Until,
+		StartDateTime: r.StartDateTime,
+		EndDateTime:   r.EndDateTime,
+		ExDates:       r.ExDates,
+	}
+
+	occurrences, err := recurrence.Expand()
+	if err != nil {
+		return
+	}
+
+	for _, s := range occurrences {
+		original := s.StartDateTime
+		result = append(result, &ent.RecruitmentInsteadSchedule{
+			StartDateTime:         s.StartDateTime,
+			EndDateTime:           s.EndDateTime,
+			OriginalStartDateTime: &original,
+		})
+	}
+	return
+}
+
+func (u *recruitmentUsecase) UpdateSchedule(ctx context.Context, d *request.RecruitmentScheduleBody, id, insteadId, scheduleId, academyId int) (err error) {
+	if !time.Time(d.StartDateTime).Before(time.Time(d.EndDateTime)) {
+		err = ex.NewBadRequestError(ex.ErrReqeustsInvalid, model.ErrRecurrenceEndInvalid)
+		return
+	}
+
+	if _, err = u.getOwnSchedule(ctx, id, insteadId, scheduleId, academyId); err != nil {
+		return
+	}
+
+	return u.recruitRepo.UpdateSchedule(ctx, scheduleId, d.StartDateTime, d.EndDateTime)
+}
+
+// 반복 규칙으로 생성된 일정이면 규칙의 제외 일정에 추가해서, 시리즈를 수정해도 다시 생기지 않도록 한다.
+func (u *recruitmentUsecase) DeleteSchedule(ctx context.Context, id, insteadId, scheduleId, academyId int) (err error) {
+	schedule, err := u.getOwnSchedule(ctx, id, insteadId, scheduleId, academyId)
+	if err != nil {
+		return
+	}
+
+	recurrence := schedule.Edges.Instead.Recurrence
+	if recurrence != nil && schedule.OriginalStartDateTime != nil {
+		recurrence.ExDates = append(recurrence.ExDates, *schedule.OriginalStartDateTime)
+	} else {
+		recurrence = nil
+	}
+
+	return u.recruitRepo.DeleteSchedule(ctx, insteadId, scheduleId, recurrence)
+}
+
+func (u *recruitmentUsecase) FinishExpired(ctx context.Context, now time.Time) (count int, err error) {
+	// 일정은 Asia/Seoul 기준 벽시계 시간으로 저장되어 있다.
+	loc, err := time.LoadLocation(model.DefaultTimezone)
+	if err != nil {
+		return
+	}
+	n := now.In(loc)
+	wallClock := transport.TimeString(time.Date(n.Year(), n.Month(), n.Day(), n.Hour(), n.Minute(), n.Second(), 0, time.UTC))
+
+	return u.recruitRepo.FinishExpired(ctx, wallClock)
+}
+
+func (u *recruitmentUsecase) getOwnSchedule(ctx context.Context, id, insteadId, scheduleId, academyId int) (result *ent.RecruitmentInsteadSchedule, err error) {
+	instead, err := u.getInstead(ctx, id, insteadId)
+	if err != nil {
+		return
+	}
+
+	if instead.Edges.Recuritment.AcademyID != academyId {
+		err = ex.NewForbiddenError(ex.ErrOnlyOwnUser, nil)
+		return
+	}
+
+	result, err = u.recruitRepo.GetSchedule(ctx, insteadId, scheduleId)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrScheduleNotFound, nil)
+			return
+		}
+		return
+	}
+	result.Edges.Instead = instead
+	return
+}
+
+func conflictInsteadIds(vals []*ent.RecruitmentInstead) []int {
+	result := make([]int, 0)
+	for _, v := range vals {
+		result = append(result, v.ID)
+	}
+	return result
+}
+
+func (u *recruitmentUsecase) getApplication(ctx context.Context, insteadId, teacherId int) (result *ent.RecruitmentApplication, err error) {
+	result, err = u.recruitRepo.GetApplication(ctx, insteadId, teacherId)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrApplicantNotFound, nil)
+			return
+		}
+		return
+	}
+	return
+}
+
+func (u *recruitmentUsecase) getInstead(ctx context.Context, id, insteadId int) (result *ent.RecruitmentInstead, err error) {
+	result, err = u.recruitRepo.GetInstead(ctx, id, insteadId)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrInsteadNotFound, nil)
+			return
+		}
+		return
+	}
+	return
+}
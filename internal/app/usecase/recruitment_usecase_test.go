@@ -0,0 +1,257 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/common"
+	"onthemat/internal/app/mocks"
+	"onthemat/internal/app/model"
+	"onthemat/internal/app/repository"
+	"onthemat/internal/app/transport"
+	"onthemat/internal/app/transport/request"
+	"onthemat/internal/app/usecase"
+	"onthemat/internal/app/utils"
+	"onthemat/pkg/ent"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecruitmentUCTestSuite struct {
+	suite.Suite
+	recruitmentUC   usecase.RecruitmentUsecase
+	mockRecruitRepo *mocks.RecruitmentRepository
+}
+
+// 각 테스트 시작 전 N회
+func (ts *RecruitmentUCTestSuite) SetupTest() {
+	ts.mockRecruitRepo = new(mocks.RecruitmentRepository)
+	ts.recruitmentUC = usecase.NewRecruitmentUsecase(ts.mockRecruitRepo)
+}
+
+func (ts *RecruitmentUCTestSuite) instead(academyId int, passerId *int) *ent.RecruitmentInstead {
+	return &ent.RecruitmentInstead{
+		ID:        1,
+		TeacherID: passerId,
+		Edges: ent.RecruitmentInsteadEdges{
+			Recuritment: &ent.Recruitment{ID: 1, AcademyID: academyId, IsOpen: true},
+		},
+	}
+}
+
+// ------------------- Test Case -------------------
+
+func (ts *RecruitmentUCTestSuite) TestCreate() {
+	ts.Run("급여 단위 변환, 통화 기본값", func() {
+		ts.mockRecruitRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *ent.Recruitment) bool {
+			v := d.Edges.RecruitmentInstead[0]
+			return v.PayAmount == 15000 &&
+				v.PayCurrency == model.DefaultPayCurrency &&
+				v.PayUnit == model.PayPerHour &&
+				v.MinCareerMonth == 18
+		})).Return(nil).Once()
+
+		start := transport.TimeString(time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC))
+		body := &request.RecruitmentCreateBody{
+			InsteadInfo: []*request.RecruitmentInsteadForCreate{{
+				MinCareerMonth: 18,
+				PayAmount:      15000,
+				PayUnit:        model.PayPerHourString,
+				Schedules: []request.Schedule{
+					{StartDateTime: start, EndDateTime: transport.TimeString(time.Time(start).Add(time.Hour))},
+				},
+			}},
+		}
+
+		err := ts.recruitmentUC.Create(context.Background(), body, 1)
+		ts.NoError(err)
+		ts.mockRecruitRepo.AssertExpectations(ts.T())
+	})
+}
+
+func (ts *RecruitmentUCTestSuite) TestApply() {
+	ts.Run("성공", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetApplication", mock.Anything, 1, 2).
+			Return(nil, &ent.NotFoundError{}).Once()
+		ts.mockRecruitRepo.On("CreateApplication", mock.Anything, 1, 2).
+			Return(nil).Once()
+
+		err := ts.recruitmentUC.Apply(context.Background(), 1, 1, 2)
+		ts.NoError(err)
+	})
+
+	ts.Run("이미 지원한 경우", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetApplication", mock.Anything, 1, 2).
+			Return(&ent.RecruitmentApplication{Status: model.ApplicationApplied}, nil).Once()
+
+		err := ts.recruitmentUC.Apply(context.Background(), 1, 1, 2)
+		ts.Equal(common.ErrAlreadyApplied, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("합격자가 정해진 대강", func() {
+		passerId := 3
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, &passerId), nil).Once()
+
+		err := ts.recruitmentUC.Apply(context.Background(), 1, 1, 2)
+		ts.Equal(common.ErrRecruitmentAlreadyClosed, err.(common.HttpError).ErrCode)
+	})
+}
+
+func (ts *RecruitmentUCTestSuite) TestChangeApplicationStatus() {
+	ts.Run("합격", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetApplication", mock.Anything, 1, 2).
+			Return(&ent.RecruitmentApplication{Status: model.ApplicationShortlisted}, nil).Once()
+		ts.mockRecruitRepo.On("AcceptApplication", mock.Anything, 1, 2).
+			Return([]*ent.RecruitmentInstead{}, nil).Once()
+
+		err := ts.recruitmentUC.ChangeApplicationStatus(context.Background(), 1, 1, 2, 1, model.ApplicationAccepted)
+		ts.NoError(err)
+	})
+
+	ts.Run("이미 합격한 대강과 시간이 겹치는 경우", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetApplication", mock.Anything, 1, 2).
+			Return(&ent.RecruitmentApplication{Status: model.ApplicationApplied}, nil).Once()
+		ts.mockRecruitRepo.On("AcceptApplication", mock.Anything, 1, 2).
+			Return([]*ent.RecruitmentInstead{{ID: 5}}, nil).Once()
+
+		err := ts.recruitmentUC.ChangeApplicationStatus(context.Background(), 1, 1, 2, 1, model.ApplicationAccepted)
+		errorStruct := err.(common.HttpError)
+		ts.Equal(common.ErrScheduleConflict, errorStruct.ErrCode)
+		ts.Equal([]int{5}, errorStruct.ErrDetails)
+	})
+
+	ts.Run("합격 처리하는 사이 지원을 취소한 경우", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetApplication", mock.Anything, 1, 2).
+			Return(&ent.RecruitmentApplication{Status: model.ApplicationApplied}, nil).Once()
+		ts.mockRecruitRepo.On("AcceptApplication", mock.Anything, 1, 2).
+			Return(nil, errors.New(repository.ErrApplicationStatusChanged)).Once()
+
+		err := ts.recruitmentUC.ChangeApplicationStatus(context.Background(), 1, 1, 2, 1, model.ApplicationAccepted)
+		ts.Equal(common.ErrApplicationStatusInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("불합격된 지원자는 합격시킬 수 없음", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetApplication", mock.Anything, 1, 2).
+			Return(&ent.RecruitmentApplication{Status: model.ApplicationRejected}, nil).Once()
+
+		err := ts.recruitmentUC.ChangeApplicationStatus(context.Background(), 1, 1, 2, 1, model.ApplicationAccepted)
+		ts.Equal(common.ErrApplicationStatusInvalid, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("다른 학원의 공고", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(2, nil), nil).Once()
+
+		err := ts.recruitmentUC.ChangeApplicationStatus(context.Background(), 1, 1, 2, 1, model.ApplicationRejected)
+		ts.Equal(common.ErrOnlyOwnUser, err.(common.HttpError).ErrCode)
+	})
+}
+
+func (ts *RecruitmentUCTestSuite) TestDeleteSchedule() {
+	ts.Run("반복 일정이면 제외 일정에 추가", func() {
+		original := transport.TimeString(time.Date(2022, 12, 6, 19, 0, 0, 0, time.UTC))
+
+		instead := ts.instead(1, nil)
+		instead.Recurrence = &model.Recurrence{Frequency: model.FrequencyWeekly}
+
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(instead, nil).Once()
+		ts.mockRecruitRepo.On("GetSchedule", mock.Anything, 1, 3).
+			Return(&ent.RecruitmentInsteadSchedule{ID: 3, OriginalStartDateTime: &original}, nil).Once()
+		ts.mockRecruitRepo.On("DeleteSchedule", mock.Anything, 1, 3,
+			mock.MatchedBy(func(r *model.Recurrence) bool {
+				return len(r.ExDates) == 1 && r.ExDates[0] == original
+			})).
+			Return(nil).Once()
+
+		err := ts.recruitmentUC.DeleteSchedule(context.Background(), 1, 1, 3, 1)
+		ts.NoError(err)
+	})
+
+	ts.Run("직접 입력한 일정", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetSchedule", mock.Anything, 1, 3).
+			Return(&ent.RecruitmentInsteadSchedule{ID: 3}, nil).Once()
+		ts.mockRecruitRepo.On("DeleteSchedule", mock.Anything, 1, 3, (*model.Recurrence)(nil)).
+			Return(nil).Once()
+
+		err := ts.recruitmentUC.DeleteSchedule(context.Background(), 1, 1, 3, 1)
+		ts.NoError(err)
+	})
+
+	ts.Run("없는 일정", func() {
+		ts.mockRecruitRepo.On("GetInstead", mock.Anything, 1, 1).
+			Return(ts.instead(1, nil), nil).Once()
+		ts.mockRecruitRepo.On("GetSchedule", mock.Anything, 1, 3).
+			Return(nil, &ent.NotFoundError{}).Once()
+
+		err := ts.recruitmentUC.DeleteSchedule(context.Background(), 1, 1, 3, 1)
+		ts.Equal(common.ErrScheduleNotFound, err.(common.HttpError).ErrCode)
+	})
+}
+
+func (ts *RecruitmentUCTestSuite) TestList() {
+	ts.Run("빠른 시작 순 정렬", func() {
+		ts.mockRecruitRepo.On("Total", mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(12, nil).Once()
+
+		recruitments := make([]*ent.Recruitment, 10)
+		ts.mockRecruitRepo.On("List", mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(orderCol *string) bool { return *orderCol == "START" }), common.Sorts(common.ASC)).
+			Return(recruitments, nil).Once()
+
+		queries := request.NewRecruitmentListQueries()
+		queries.OrderCol = utils.String("start")
+		queries.OrderType = utils.String("ASC")
+
+		_, p, err := ts.recruitmentUC.List(context.Background(), queries)
+		ts.NoError(err)
+		ts.Equal(2, p.PageCount)
+		ts.Equal(10, p.RowCount)
+	})
+
+	ts.Run("급여 단위 없이 급여 정렬", func() {
+		queries := request.NewRecruitmentListQueries()
+		queries.OrderCol = utils.String("pay")
+
+		_, _, err := ts.recruitmentUC.List(context.Background(), queries)
+		ts.Equal(common.ErrReqeustsInvalid, err.(common.HttpError).ErrCode)
+	})
+}
+
+func (ts *RecruitmentUCTestSuite) TestFinishExpired() {
+	ts.Run("서울 기준 벽시계 시간으로 비교", func() {
+		wallClock := transport.TimeString(time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC))
+		ts.mockRecruitRepo.On("FinishExpired", mock.Anything, wallClock).
+			Return(2, nil).Once()
+
+		count, err := ts.recruitmentUC.FinishExpired(context.Background(), time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC))
+		ts.NoError(err)
+		ts.Equal(2, count)
+	})
+}
+
+func TestRecruitmentUCTestSuite(t *testing.T) {
+	suite.Run(t, new(RecruitmentUCTestSuite))
+}
@@ -2,10 +2,12 @@ package usecase
 
 import (
 	"context"
+	"strings"
 
 	ex "onthemat/internal/app/common"
 	"onthemat/internal/app/repository"
 	"onthemat/internal/app/transport/request"
+	"onthemat/internal/app/utils"
 	"onthemat/pkg/ent"
 )
 
@@ -14,6 +16,7 @@ type TeacherUsecase interface {
 	Update(ctx context.Context, d *request.TeacherUpdateBody, id, userId int) (isUpdated bool, err error)
 	Patch(ctx context.Context, d *request.TeacherPatchBody, id, userId int) (isUpdated bool, err error)
 	Get(ctx context.Context, id, userId int) (*ent.Teacher, error)
+	List(ctx context.Context, a *request.TeacherListQueries) ([]*ent.Teacher, *utils.PagenationInfo, error)
 }
 
 type teacherUseCase struct {
@@ -234,3 +237,30 @@ func (u *teacherUseCase) Update(ctx context.Context, d *request.TeacherUpdateBod
 	}
 	return
 }
+
+func (u *teacherUseCase) List(ctx context.Context, a *request.TeacherListQueries) (result []*ent.Teacher, paginationInfo *utils.PagenationInfo, err error) {
+	paginationModule := utils.NewPagination(a.PageNo, a.PageSize)
+
+	if a.OrderCol != nil {
+		*a.OrderCol = strings.ToUpper(*a.OrderCol)
+	}
+
+	total, err := u.teacherRepo.Total(ctx, a.YogaIDs, a.SigunguIds, a.MinCareerYear, a.AgencyName)
+	if err != nil {
+		return
+	}
+
+	orderType := ex.DESC
+	if a.OrderType != nil && *a.OrderType == string(ex.ASC) {
+		orderType = ex.ASC
+	}
+
+	paginationModule.SetTotal(total)
+	result, err = u.teacherRepo.List(ctx, paginationModule, a.YogaIDs, a.SigunguIds, a.MinCareerYear, a.AgencyName, a.OrderCol, orderType)
+	if err != nil {
+		return
+	}
+
+	paginationInfo = paginationModule.GetInfo(len(result))
+	return
+}
@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"onthemat/internal/app/common"
+	"onthemat/internal/app/mocks"
+	"onthemat/internal/app/transport/request"
+	"onthemat/internal/app/usecase"
+	"onthemat/internal/app/utils"
+	"onthemat/pkg/ent"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TeacherUCTestSuite struct {
+	suite.Suite
+	teacherUC       usecase.TeacherUsecase
+	mockTeacherRepo *mocks.TeacherRepository
+	mockUserRepo    *mocks.UserRepository
+}
+
+// 각 테스트 시작 전 N회
+func (ts *TeacherUCTestSuite) SetupTest() {
+	ts.mockTeacherRepo = new(mocks.TeacherRepository)
+	ts.mockUserRepo = new(mocks.UserRepository)
+	ts.teacherUC = usecase.NewTeacherUsecase(ts.mockTeacherRepo, ts.mockUserRepo)
+}
+
+// ------------------- Test Case -------------------
+
+func (ts *TeacherUCTestSuite) TestList() {
+	ts.Run("성공", func() {
+		ts.mockTeacherRepo.On("Total", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(25, nil).Once()
+
+		teachers := make([]*ent.Teacher, 10)
+		ts.mockTeacherRepo.On("List", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, common.Sorts(common.ASC)).
+			Return(teachers, nil).Once()
+
+		queries := request.NewTeacherListQueries()
+		queries.OrderType = utils.String("ASC")
+
+		_, p, err := ts.teacherUC.List(context.Background(), queries)
+		ts.NoError(err)
+		ts.Equal(3, p.PageCount)
+		ts.Equal(10, p.RowCount)
+	})
+}
+
+func TestTeacherUCTestSuite(t *testing.T) {
+	suite.Run(t, new(TeacherUCTestSuite))
+}
@@ -2,30 +2,50 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
 
 	ex "onthemat/internal/app/common"
 	"onthemat/internal/app/repository"
 	"onthemat/internal/app/transport/request"
+	"onthemat/pkg/auth/store"
 	"onthemat/pkg/ent"
+	"onthemat/pkg/sms"
 )
 
 type UserUseCase interface {
 	GetMe(ctx context.Context, id int) (result *ent.User, err error)
 	Update(ctx context.Context, reqBody *request.UserUpdateBody, id int) (err error)
+
+	// 휴대폰 번호로 6자리 인증번호를 보낸다.
+	RequestPhoneVerification(ctx context.Context, userId int, body *request.UserPhoneVerifyRequestBody) (*PhoneVerificationResult, error)
+	// 인증번호가 맞으면 인증한 번호를 유저의 휴대폰 번호로 저장한다.
+	ConfirmPhoneVerification(ctx context.Context, userId int, body *request.UserPhoneVerifyConfirmBody) error
 }
 
 type userUseCase struct {
 	userRepo repository.UserRepository
+	store    store.Store
+	sms      sms.Sender
 }
 
-func NewUserUseCase(userRepo repository.UserRepository) UserUseCase {
+func NewUserUseCase(userRepo repository.UserRepository, store store.Store, sms sms.Sender) UserUseCase {
 	return &userUseCase{
 		userRepo: userRepo,
+		store:    store,
+		sms:      sms,
 	}
 }
 
 func (u *userUseCase) GetMe(ctx context.Context, id int) (result *ent.User, err error) {
-	result, err = u.userRepo.Get(ctx, id)
+	result, err = u.userRepo.GetWithIdentities(ctx, id)
 
 	if ent.IsNotFound(err) {
 		err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
@@ -35,10 +55,22 @@ func (u *userUseCase) GetMe(ctx context.Context, id int) (result *ent.User, err
 }
 
 func (u *userUseCase) Update(ctx context.Context, reqBody *request.UserUpdateBody, id int) (err error) {
+	current, err := u.userRepo.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+		}
+		return
+	}
+
+	// 번호가 바뀌면 다시 인증해야 한다.
+	isPhoneVerified := current.IsPhoneVerified && current.PhoneNum != nil && *current.PhoneNum == reqBody.PhoneNum
+
 	_, err = u.userRepo.Update(ctx, &ent.User{
-		ID:       id,
-		Nickname: &reqBody.Nickname,
-		PhoneNum: &reqBody.PhoneNum,
+		ID:              id,
+		Nickname:        &reqBody.Nickname,
+		PhoneNum:        &reqBody.PhoneNum,
+		IsPhoneVerified: isPhoneVerified,
 	})
 
 	if err != nil {
@@ -46,3 +78,150 @@ func (u *userUseCase) Update(ctx context.Context, reqBody *request.UserUpdateBod
 	}
 	return
 }
+
+const (
+	// 인증번호 유효 시간
+	phoneVerifyCodeExpired = 3 * time.Minute
+	// 인증번호를 다시 요청하려면 기다려야 하는 시간
+	phoneVerifyCooldown = time.Minute
+	// 하루에 유저, 휴대폰 번호별로 요청할 수 있는 횟수 (문자 비용, 스팸 방지)
+	phoneVerifyDailyLimit = 5
+	// 인증번호 하나로 틀릴 수 있는 횟수. 넘으면 인증번호를 다시 받아야 한다.
+	phoneVerifyMaxAttempts = 5
+)
+
+type PhoneVerificationResult struct {
+	// 이 일시까지 인증번호를 입력해야 한다.
+	ExpiredAt time.Time `json:"expiredAt"`
+}
+
+// store 에는 인증번호 원문이 아닌 해시를 저장한다.
+type phoneVerification struct {
+	PhoneNum string `json:"phoneNum"`
+	CodeHash string `json:"codeHash"`
+}
+
+func phoneVerifyKey(userId int) string {
+	return "phone-verify:" + strconv.Itoa(userId)
+}
+
+func phoneVerifyAttemptsKey(userId int) string {
+	return "phone-verify-attempts:" + strconv.Itoa(userId)
+}
+
+func hashPhoneVerifyCode(userId int, code string) string {
+	sum := sha256.Sum256([]byte(strconv.Itoa(userId) + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+func newPhoneVerifyCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func (u *userUseCase) RequestPhoneVerification(ctx context.Context, userId int, body *request.UserPhoneVerifyRequestBody) (result *PhoneVerificationResult, err error) {
+	cooldownKey := "phone-verify-cooldown:" + strconv.Itoa(userId)
+	ok, err := u.store.SetNX(ctx, cooldownKey, "1", phoneVerifyCooldown)
+	if err != nil {
+		return
+	}
+	if !ok {
+		err = ex.NewTooManyRequestsError(ex.ErrPhoneVerifyCooldown, nil)
+		return
+	}
+
+	for _, key := range []string{
+		"phone-verify-daily:user:" + strconv.Itoa(userId),
+		"phone-verify-daily:phone:" + body.PhoneNum,
+	} {
+		count, errI := u.store.Incr(ctx, key, 24*time.Hour)
+		if errI != nil {
+			err = errI
+			return
+		}
+		if count > phoneVerifyDailyLimit {
+			err = ex.NewTooManyRequestsError(ex.ErrPhoneVerifyAttemptsExceeded, nil)
+			return
+		}
+	}
+
+	code, err := newPhoneVerifyCode()
+	if err != nil {
+		return
+	}
+
+	value, _ := json.Marshal(phoneVerification{
+		PhoneNum: body.PhoneNum,
+		CodeHash: hashPhoneVerifyCode(userId, code),
+	})
+	// 새 인증번호를 받으면 틀린 횟수를 다시 센다.
+	if err = u.store.Del(ctx, phoneVerifyAttemptsKey(userId)); err != nil {
+		return
+	}
+	if err = u.store.Set(ctx, phoneVerifyKey(userId), string(value), phoneVerifyCodeExpired); err != nil {
+		return
+	}
+
+	if err = u.sms.Send(ctx, body.PhoneNum, fmt.Sprintf("[온더맷] 인증번호 [%s]를 입력해주세요.", code)); err != nil {
+		// 보내지 못했으면 바로 다시 요청할 수 있게 한다.
+		u.store.Del(ctx, phoneVerifyKey(userId))
+		u.store.Del(ctx, cooldownKey)
+		return
+	}
+
+	result = &PhoneVerificationResult{
+		ExpiredAt: time.Now().Add(phoneVerifyCodeExpired),
+	}
+	return
+}
+
+func (u *userUseCase) ConfirmPhoneVerification(ctx context.Context, userId int, body *request.UserPhoneVerifyConfirmBody) (err error) {
+	value := u.store.Get(ctx, phoneVerifyKey(userId))
+	verification := new(phoneVerification)
+	if value == "" || json.Unmarshal([]byte(value), verification) != nil {
+		err = ex.NewBadRequestError(ex.ErrPhoneVerifyCodeExpired, nil)
+		return
+	}
+
+	// 인증번호가 6자리라 횟수를 제한하지 않으면 모든 번호를 대입해볼 수 있다.
+	attempts, err := u.store.Incr(ctx, phoneVerifyAttemptsKey(userId), phoneVerifyCodeExpired)
+	if err != nil {
+		return
+	}
+	if attempts > phoneVerifyMaxAttempts {
+		if err = u.store.Del(ctx, phoneVerifyKey(userId)); err != nil {
+			return
+		}
+		err = ex.NewBadRequestError(ex.ErrPhoneVerifyCodeExpired, nil)
+		return
+	}
+
+	codeHash := hashPhoneVerifyCode(userId, body.Code)
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(verification.CodeHash)) != 1 {
+		err = ex.NewBadRequestError(ex.ErrPhoneVerifyCodeInvalid, map[string]int{
+			"remainingAttempts": phoneVerifyMaxAttempts - int(attempts),
+		})
+		return
+	}
+
+	// 같은 인증번호로 다시 인증할 수 없도록 먼저 지운다.
+	deleted, err := u.store.GetDel(ctx, phoneVerifyKey(userId))
+	if err != nil {
+		return
+	}
+	if deleted == "" {
+		err = ex.NewBadRequestError(ex.ErrPhoneVerifyCodeExpired, nil)
+		return
+	}
+
+	if err = u.userRepo.UpdatePhoneVerified(ctx, userId, verification.PhoneNum); err != nil {
+		if ent.IsNotFound(err) {
+			err = ex.NewNotFoundError(ex.ErrUserNotFound, nil)
+		}
+		return
+	}
+	return u.store.Del(ctx, phoneVerifyAttemptsKey(userId))
+}
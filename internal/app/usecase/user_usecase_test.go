@@ -2,12 +2,18 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
+	"regexp"
 	"testing"
+	"time"
 
 	"onthemat/internal/app/common"
 	"onthemat/internal/app/mocks"
+	"onthemat/internal/app/transport/request"
 	"onthemat/internal/app/usecase"
 	"onthemat/pkg/ent"
+	pkgMock "onthemat/pkg/mocks"
+	"onthemat/pkg/sms"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -17,19 +23,23 @@ type UserUsecaseTestSuite struct {
 	suite.Suite
 	userUsecase  usecase.UserUseCase
 	mockUserRepo *mocks.UserRepository
+	mockStore    *pkgMock.Store
+	fakeSms      *sms.Fake
 }
 
-// 모든 테스트 시작 전 1회
-func (ts *UserUsecaseTestSuite) SetupSuite() {
+// 각 테스트 시작 전 N회
+func (ts *UserUsecaseTestSuite) SetupTest() {
 	ts.mockUserRepo = new(mocks.UserRepository)
-	ts.userUsecase = usecase.NewUserUseCase(ts.mockUserRepo)
+	ts.mockStore = new(pkgMock.Store)
+	ts.fakeSms = sms.NewFake()
+	ts.userUsecase = usecase.NewUserUseCase(ts.mockUserRepo, ts.mockStore, ts.fakeSms)
 }
 
 // ------------------- Test Case -------------------
 
 func (ts *UserUsecaseTestSuite) TestGetMe() {
 	ts.Run("성공", func() {
-		ts.mockUserRepo.On("Get", mock.Anything, mock.Anything).
+		ts.mockUserRepo.On("GetWithIdentities", mock.Anything, mock.Anything).
 			Return(&ent.User{
 				ID: 1,
 			}, nil).Once()
@@ -40,7 +50,7 @@ func (ts *UserUsecaseTestSuite) TestGetMe() {
 	})
 
 	ts.Run("NotFound", func() {
-		ts.mockUserRepo.On("Get", mock.Anything, mock.Anything).
+		ts.mockUserRepo.On("GetWithIdentities", mock.Anything, mock.Anything).
 			Return(nil, &ent.NotFoundError{}).Once()
 
 		_, err := ts.userUsecase.GetMe(context.Background(), 1)
@@ -49,6 +59,133 @@ func (ts *UserUsecaseTestSuite) TestGetMe() {
 	})
 }
 
+func (ts *UserUsecaseTestSuite) TestUpdate() {
+	phoneNum := "01012345678"
+
+	ts.Run("번호가 같으면 인증 유지", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 1).
+			Return(&ent.User{ID: 1, PhoneNum: &phoneNum, IsPhoneVerified: true}, nil).Once()
+		ts.mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *ent.User) bool {
+			return *u.PhoneNum == phoneNum && u.IsPhoneVerified
+		})).Return(&ent.User{}, nil).Once()
+
+		err := ts.userUsecase.Update(context.Background(), &request.UserUpdateBody{Nickname: "nick", PhoneNum: phoneNum}, 1)
+		ts.NoError(err)
+	})
+
+	ts.Run("번호가 바뀌면 다시 인증해야 함", func() {
+		ts.mockUserRepo.On("Get", mock.Anything, 1).
+			Return(&ent.User{ID: 1, PhoneNum: &phoneNum, IsPhoneVerified: true}, nil).Once()
+		ts.mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *ent.User) bool {
+			return *u.PhoneNum == "01099998888" && !u.IsPhoneVerified
+		})).Return(&ent.User{}, nil).Once()
+
+		err := ts.userUsecase.Update(context.Background(), &request.UserUpdateBody{Nickname: "nick", PhoneNum: "01099998888"}, 1)
+		ts.NoError(err)
+	})
+	ts.mockUserRepo.AssertExpectations(ts.T())
+}
+
+func (ts *UserUsecaseTestSuite) TestRequestPhoneVerification() {
+	body := &request.UserPhoneVerifyRequestBody{PhoneNum: "01012345678"}
+
+	ts.Run("1분 안에 다시 요청", func() {
+		ts.mockStore.On("SetNX", mock.Anything, "phone-verify-cooldown:1", "1", time.Minute).Return(false, nil).Once()
+
+		_, err := ts.userUsecase.RequestPhoneVerification(context.Background(), 1, body)
+		ts.Equal(429, err.(common.HttpError).ErrHttpCode)
+		ts.Equal(common.ErrPhoneVerifyCooldown, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("하루 요청 횟수 초과", func() {
+		ts.mockStore.On("SetNX", mock.Anything, "phone-verify-cooldown:1", "1", time.Minute).Return(true, nil).Once()
+		ts.mockStore.On("Incr", mock.Anything, "phone-verify-daily:user:1", 24*time.Hour).Return(int64(6), nil).Once()
+
+		_, err := ts.userUsecase.RequestPhoneVerification(context.Background(), 1, body)
+		ts.Equal(common.ErrPhoneVerifyAttemptsExceeded, err.(common.HttpError).ErrCode)
+		ts.Empty(ts.fakeSms.Messages())
+	})
+
+	ts.Run("문자 발송 실패", func() {
+		ts.fakeSms.Err = errors.New("발송 실패")
+		ts.mockStore.On("SetNX", mock.Anything, "phone-verify-cooldown:1", "1", time.Minute).Return(true, nil).Once()
+		ts.mockStore.On("Incr", mock.Anything, mock.AnythingOfType("string"), 24*time.Hour).Return(int64(1), nil).Twice()
+		ts.mockStore.On("Del", mock.Anything, "phone-verify-attempts:1").Return(nil).Once()
+		ts.mockStore.On("Set", mock.Anything, "phone-verify:1", mock.AnythingOfType("string"), 3*time.Minute).Return(nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "phone-verify:1").Return(nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "phone-verify-cooldown:1").Return(nil).Once()
+
+		_, err := ts.userUsecase.RequestPhoneVerification(context.Background(), 1, body)
+		ts.Error(err)
+		ts.mockStore.AssertExpectations(ts.T())
+	})
+}
+
+func (ts *UserUsecaseTestSuite) TestConfirmPhoneVerification() {
+	// 인증번호를 요청해서 store 에 저장되는 값과 문자로 보낸 인증번호를 얻는다.
+	var stored string
+	ts.mockStore.On("SetNX", mock.Anything, "phone-verify-cooldown:1", "1", time.Minute).Return(true, nil).Once()
+	ts.mockStore.On("Incr", mock.Anything, "phone-verify-daily:user:1", 24*time.Hour).Return(int64(1), nil).Once()
+	ts.mockStore.On("Incr", mock.Anything, "phone-verify-daily:phone:01012345678", 24*time.Hour).Return(int64(1), nil).Once()
+	ts.mockStore.On("Del", mock.Anything, "phone-verify-attempts:1").Return(nil).Once()
+	ts.mockStore.On("Set", mock.Anything, "phone-verify:1", mock.AnythingOfType("string"), 3*time.Minute).
+		Run(func(args mock.Arguments) { stored = args.String(2) }).Return(nil).Once()
+
+	result, err := ts.userUsecase.RequestPhoneVerification(context.Background(), 1, &request.UserPhoneVerifyRequestBody{PhoneNum: "01012345678"})
+	ts.NoError(err)
+	ts.WithinDuration(time.Now().Add(3*time.Minute), result.ExpiredAt, time.Second)
+
+	messages := ts.fakeSms.Messages()
+	ts.Len(messages, 1)
+	ts.Equal("01012345678", messages[0].To)
+	code := regexp.MustCompile(`\[(\d{6})\]`).FindStringSubmatch(messages[0].Text)[1]
+	ts.NotContains(stored, code)
+
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+
+	ts.Run("만료되었거나 요청하지 않음", func() {
+		ts.mockStore.On("Get", mock.Anything, "phone-verify:2").Return("").Once()
+
+		err := ts.userUsecase.ConfirmPhoneVerification(context.Background(), 2, &request.UserPhoneVerifyConfirmBody{Code: code})
+		ts.Equal(common.ErrPhoneVerifyCodeExpired, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("인증번호 불일치", func() {
+		ts.mockStore.On("Get", mock.Anything, "phone-verify:1").Return(stored).Once()
+		ts.mockStore.On("Incr", mock.Anything, "phone-verify-attempts:1", 3*time.Minute).Return(int64(1), nil).Once()
+
+		err := ts.userUsecase.ConfirmPhoneVerification(context.Background(), 1, &request.UserPhoneVerifyConfirmBody{Code: wrongCode})
+		ts.Equal(common.ErrPhoneVerifyCodeInvalid, err.(common.HttpError).ErrCode)
+		ts.Equal(map[string]int{"remainingAttempts": 4}, err.(common.HttpError).ErrDetails)
+	})
+
+	ts.Run("틀린 횟수 초과 시 인증번호 폐기", func() {
+		ts.mockStore.On("Get", mock.Anything, "phone-verify:1").Return(stored).Once()
+		ts.mockStore.On("Incr", mock.Anything, "phone-verify-attempts:1", 3*time.Minute).Return(int64(6), nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "phone-verify:1").Return(nil).Once()
+
+		// 맞는 인증번호여도 거부한다.
+		err := ts.userUsecase.ConfirmPhoneVerification(context.Background(), 1, &request.UserPhoneVerifyConfirmBody{Code: code})
+		ts.Equal(common.ErrPhoneVerifyCodeExpired, err.(common.HttpError).ErrCode)
+	})
+
+	ts.Run("성공", func() {
+		ts.mockStore.On("Get", mock.Anything, "phone-verify:1").Return(stored).Once()
+		ts.mockStore.On("Incr", mock.Anything, "phone-verify-attempts:1", 3*time.Minute).Return(int64(2), nil).Once()
+		ts.mockStore.On("GetDel", mock.Anything, "phone-verify:1").Return(stored, nil).Once()
+		ts.mockUserRepo.On("UpdatePhoneVerified", mock.Anything, 1, "01012345678").Return(nil).Once()
+		ts.mockStore.On("Del", mock.Anything, "phone-verify-attempts:1").Return(nil).Once()
+
+		err := ts.userUsecase.ConfirmPhoneVerification(context.Background(), 1, &request.UserPhoneVerifyConfirmBody{Code: code})
+		ts.NoError(err)
+		ts.mockUserRepo.AssertExpectations(ts.T())
+		ts.mockStore.AssertExpectations(ts.T())
+	})
+}
+
 func TestUserUsecaseTestSuite(t *testing.T) {
 	suite.Run(t, new(UserUsecaseTestSuite))
 }
@@ -3,7 +3,6 @@ package utils
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"time"
 
 	"onthemat/internal/app/config"
@@ -11,6 +10,7 @@ import (
 	"onthemat/internal/app/model"
 	"onthemat/internal/app/service"
 	"onthemat/internal/app/transport"
+	"onthemat/pkg/auth/password"
 	"onthemat/pkg/ent"
 	"onthemat/pkg/ent/user"
 
@@ -30,7 +30,7 @@ func NewSeeding() *seeding {
 	}
 
 	cli := infrastructure.NewPostgresDB(c)
-	as := service.NewAuthService(nil, nil, nil, nil)
+	as := service.NewAuthService(password.NewHasher(password.DefaultParams, c.Secret.Password))
 	return &seeding{
 		db: cli,
 		as: as,
@@ -41,8 +41,9 @@ func NewSeeding() *seeding {
 func (t *seeding) Users() {
 	bulk := make([]*ent.UserCreate, 40)
 
+	// 같은 비밀번호이므로 한 번만 해시한다.
+	hased, _ := t.as.HashPassword("asd123456!")
 	for i := 0; i < 40; i++ {
-		hased := t.as.HashPassword("asd123456!", t.c.Secret.Password)
 		bulk[i] = t.db.User.Create().
 			SetEmail(fake.Email()).
 			SetIsEmailVerified(true).
@@ -113,18 +114,22 @@ func (t *seeding) Recruitment() {
 		if k%2 == 0 {
 			rid++
 		}
-		schedules := make([]*model.Schedule, 0)
-		for i := 0; i < 3; i++ {
-			schedules = append(schedules, &model.Schedule{
-				StartDateTime: transport.TimeString(fake.DateRange(time.Now().AddDate(0, -4, 0), time.Now().AddDate(0, -3, 0))),
-				EndDateTime:   transport.TimeString(fake.DateRange(time.Now(), time.Now().AddDate(0, 3, 0))),
-			})
+		instead, _ := t.db.RecruitmentInstead.Create().SetRecruitmentID(rid).
+			SetMinCareerMonth(fake.Number(0, 120)).
+			SetPayAmount(fake.Number(10, 50) * 1000).
+			Save(context.Background())
+		if instead == nil {
+			continue
 		}
 
-		t.db.RecruitmentInstead.Create().SetRecruitmentID(rid).
-			SetMinCareer(strconv.Itoa(fake.Number(1, 20))).
-			SetPay(strconv.Itoa(fake.Number(10000, 50000))).
-			SetSchedule(schedules).Exec(context.Background())
+		schedules := make([]*ent.RecruitmentInsteadScheduleCreate, 0)
+		for i := 0; i < 3; i++ {
+			schedules = append(schedules, t.db.RecruitmentInsteadSchedule.Create().
+				SetRInsteadID(instead.ID).
+				SetStartDateTime(transport.TimeString(fake.DateRange(time.Now().AddDate(0, -4, 0), time.Now().AddDate(0, -3, 0)))).
+				SetEndDateTime(transport.TimeString(fake.DateRange(time.Now(), time.Now().AddDate(0, 3, 0)))))
+		}
+		t.db.RecruitmentInsteadSchedule.CreateBulk(schedules...).Exec(context.Background())
 
 	}
 }
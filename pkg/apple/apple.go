@@ -0,0 +1,181 @@
+package apple
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"onthemat/internal/app/config"
+	"onthemat/pkg/auth/jwt"
+	"onthemat/pkg/oauth"
+
+	jwtLib "github.com/golang-jwt/jwt/v4"
+	"github.com/valyala/fasthttp"
+)
+
+const issuer = "https://appleid.apple.com"
+
+type Apple struct {
+	authUrl string
+	client  *fasthttp.Client
+	config  *config.Config
+	// client_secret 서명용 개인키 (ES256)
+	privateKey *ecdsa.PrivateKey
+	// id_token 서명 검증용 공개키
+	keySet *jwt.RemoteKeySet
+	now    func() time.Time
+}
+
+func NewApple(config *config.Config, privateKey *ecdsa.PrivateKey) *Apple {
+	return &Apple{
+		authUrl:    issuer,
+		client:     &fasthttp.Client{},
+		config:     config,
+		privateKey: privateKey,
+		keySet:     jwt.NewRemoteKeySet(issuer + "/auth/keys"),
+		now:        time.Now,
+	}
+}
+
+// 애플 개발자 사이트에서 받은 .p8(PKCS#8 EC) 개인키를 읽는다.
+func LoadPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("apple: PEM 형식이 아닙니다")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("apple: 지원하지 않는 키 타입입니다 (%T)", key)
+	}
+	return ecKey, nil
+}
+
+func (a *Apple) Name() string {
+	return "apple"
+}
+
+// 애플은 PKCE 를 지원하지 않아 state 만 사용한다.
+func (a *Apple) SupportsPKCE() bool {
+	return false
+}
+
+// 이메일을 요청하면 콜백은 GET 이 아닌 form POST 로 온다.
+func (a *Apple) AuthorizeURL(state string, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("response_mode", "form_post")
+	query.Set("scope", "name email")
+	query.Set("client_id", a.config.Oauth.AppleClientId)
+	query.Set("redirect_uri", a.config.Oauth.AppleRedirect)
+	query.Set("state", state)
+
+	return a.authUrl + "/auth/authorize?" + query.Encode()
+}
+
+func (a *Apple) ExchangeCode(code string, state string, codeVerifier string) (*oauth.Token, error) {
+	clientSecret, err := a.clientSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", a.config.Oauth.AppleRedirect)
+	form.Set("client_id", a.config.Oauth.AppleClientId)
+	form.Set("client_secret", clientSecret)
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.SetRequestURI(a.authUrl + "/auth/token")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBodyString(form.Encode())
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	if err := a.client.Do(req, resp); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode() != fasthttp.StatusOK {
+		body := new(GetTokenErrorBody)
+		json.Unmarshal(resp.Body(), body)
+		if body.Error == "" {
+			return nil, fmt.Errorf("apple: %d", resp.StatusCode())
+		}
+		return nil, errors.New(body.Error)
+	}
+
+	body := new(GetTokenSuccessBody)
+	if err := json.Unmarshal(resp.Body(), body); err != nil {
+		return nil, err
+	}
+	return &oauth.Token{AccessToken: body.AccessToken, IdToken: body.IdToken}, nil
+}
+
+// 애플은 유저 정보 API 가 없어 id_token 에서 꺼낸다. 이름은 최초 로그인 때 콜백으로만 전달되므로 사용하지 않는다.
+func (a *Apple) FetchProfile(token *oauth.Token) (*oauth.Profile, error) {
+	claims, err := a.VerifyIdToken(token.IdToken)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &oauth.Profile{Key: claims.Subject}
+	// 인증되지 않은 이메일로 기존 계정과 연결되지 않도록 한다.
+	if claims.EmailVerified {
+		profile.Email = claims.Email
+	}
+	return profile, nil
+}
+
+// id_token 을 애플 공개키(JWKS)로 검증한다. 서명, 만료, 발급자(iss), 대상(aud = Services ID)을 확인한다.
+func (a *Apple) VerifyIdToken(idToken string) (*IdTokenClaims, error) {
+	claims := new(IdTokenClaims)
+	if _, err := jwtLib.ParseWithClaims(idToken, claims, a.keySet.Keyfunc); err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyAudience(a.config.Oauth.AppleClientId, true) {
+		return nil, errors.New("apple: id_token 의 aud 가 올바르지 않습니다")
+	}
+	if !claims.VerifyIssuer(issuer, true) {
+		return nil, fmt.Errorf("apple: id_token 의 iss 가 올바르지 않습니다 (%s)", claims.Issuer)
+	}
+	return claims, nil
+}
+
+// 애플은 client_secret 대신 개인키로 서명한 JWT 를 받는다. (최대 6개월, 여기서는 요청마다 짧게 만든다)
+func (a *Apple) clientSecret() (string, error) {
+	if a.privateKey == nil {
+		return "", errors.New("apple: 개인키가 설정되지 않았습니다")
+	}
+
+	now := a.now()
+	token := jwtLib.NewWithClaims(jwtLib.SigningMethodES256, jwtLib.RegisteredClaims{
+		Issuer:    a.config.Oauth.AppleTeamId,
+		Subject:   a.config.Oauth.AppleClientId,
+		Audience:  jwtLib.ClaimStrings{issuer},
+		IssuedAt:  jwtLib.NewNumericDate(now),
+		ExpiresAt: jwtLib.NewNumericDate(now.Add(5 * time.Minute)),
+	})
+	token.Header["kid"] = a.config.Oauth.AppleKeyId
+
+	return token.SignedString(a.privateKey)
+}
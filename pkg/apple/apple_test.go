@@ -0,0 +1,191 @@
+package apple
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/config"
+	"onthemat/pkg/auth/jwt"
+	"onthemat/pkg/oauth"
+
+	jwtLib "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// appleid.apple.com 대신 사용하는 로컬 서버
+type standIn struct {
+	server  *httptest.Server
+	signer  jwt.Jwt
+	idToken string
+	// 마지막 토큰 요청
+	form url.Values
+}
+
+func newStandIn(t *testing.T) *standIn {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	s := &standIn{signer: jwt.NewJwt().WithPrivateKey("apple-1", key).Init()}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(s.signer.JWKS())
+	})
+	mux.HandleFunc("/auth/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		s.form = r.PostForm
+		if r.PostForm.Get("code") != "valid-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(GetTokenSuccessBody{
+			AccessToken: "access-token",
+			TokenType:   "Bearer",
+			IdToken:     s.idToken,
+		})
+	})
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *standIn) sign(claims *IdTokenClaims) string {
+	token, _ := s.signer.GenerateToken(claims)
+	return token
+}
+
+func newClaims() *IdTokenClaims {
+	return &IdTokenClaims{
+		Email:          "relay@privaterelay.appleid.com",
+		EmailVerified:  true,
+		IsPrivateEmail: true,
+		RegisteredClaims: jwtLib.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   "001234.apple-sub",
+			Audience:  jwtLib.ClaimStrings{"com.onthemat.service"},
+			ExpiresAt: jwtLib.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func TestApple(t *testing.T) {
+	assert := assert.New(t)
+	standIn := newStandIn(t)
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c := config.NewConfig()
+	c.Oauth.AppleClientId = "com.onthemat.service"
+	c.Oauth.AppleTeamId = "TEAMID1234"
+	c.Oauth.AppleKeyId = "KEYID12345"
+	c.Oauth.AppleRedirect = "https://onthemat.com/api/v1/auth/apple/callback"
+
+	a := NewApple(c, ecKey)
+	a.authUrl = standIn.server.URL
+	a.keySet = jwt.NewRemoteKeySet(standIn.server.URL + "/auth/keys")
+
+	var provider oauth.SocialProvider = a
+
+	t.Run("로그인 페이지 주소", func(t *testing.T) {
+		u, err := url.Parse(provider.AuthorizeURL("state1234", ""))
+		assert.NoError(err)
+		assert.Equal("/auth/authorize", u.Path)
+		assert.Equal("state1234", u.Query().Get("state"))
+		assert.Equal("form_post", u.Query().Get("response_mode"))
+		assert.Equal("com.onthemat.service", u.Query().Get("client_id"))
+	})
+
+	t.Run("인가 코드 교환 후 id_token 검증", func(t *testing.T) {
+		standIn.idToken = standIn.sign(newClaims())
+
+		token, err := provider.ExchangeCode("valid-code", "state1234", "")
+		assert.NoError(err)
+
+		// client_secret 은 팀 개인키로 서명한 ES256 JWT
+		secret := new(jwtLib.RegisteredClaims)
+		parsed, err := jwtLib.ParseWithClaims(standIn.form.Get("client_secret"), secret, func(token *jwtLib.Token) (interface{}, error) {
+			return &ecKey.PublicKey, nil
+		})
+		assert.NoError(err)
+		assert.Equal("ES256", parsed.Method.Alg())
+		assert.Equal("KEYID12345", parsed.Header["kid"])
+		assert.Equal("TEAMID1234", secret.Issuer)
+		assert.Equal("com.onthemat.service", secret.Subject)
+		assert.True(secret.VerifyAudience(issuer, true))
+
+		profile, err := provider.FetchProfile(token)
+		assert.NoError(err)
+		assert.Equal("001234.apple-sub", profile.Key)
+		assert.Equal("relay@privaterelay.appleid.com", profile.Email)
+	})
+
+	t.Run("잘못된 인가 코드", func(t *testing.T) {
+		_, err := provider.ExchangeCode("invalid-code", "state1234", "")
+		assert.EqualError(err, "invalid_grant")
+	})
+
+	t.Run("email_verified 가 문자열로 오거나 거짓이면", func(t *testing.T) {
+		claims := jwtLib.MapClaims{
+			"iss":            issuer,
+			"sub":            "001234.apple-sub",
+			"aud":            "com.onthemat.service",
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"email":          "user@icloud.com",
+			"email_verified": "true",
+		}
+		idToken, _ := standIn.signer.GenerateToken(claims)
+		profile, err := provider.FetchProfile(&oauth.Token{IdToken: idToken})
+		assert.NoError(err)
+		assert.Equal("user@icloud.com", profile.Email)
+
+		claims["email_verified"] = "false"
+		idToken, _ = standIn.signer.GenerateToken(claims)
+		profile, err = provider.FetchProfile(&oauth.Token{IdToken: idToken})
+		assert.NoError(err)
+		assert.Empty(profile.Email)
+	})
+
+	t.Run("검증 실패", func(t *testing.T) {
+		other := newClaims()
+		other.Audience = jwtLib.ClaimStrings{"com.other.service"}
+		_, err := provider.FetchProfile(&oauth.Token{IdToken: standIn.sign(other)})
+		assert.Error(err)
+
+		other = newClaims()
+		other.Issuer = "https://accounts.google.com"
+		_, err = provider.FetchProfile(&oauth.Token{IdToken: standIn.sign(other)})
+		assert.Error(err)
+
+		otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		forged, _ := jwt.NewJwt().WithPrivateKey("apple-1", otherKey).Init().GenerateToken(newClaims())
+		_, err = provider.FetchProfile(&oauth.Token{IdToken: forged})
+		assert.Error(err)
+	})
+}
+
+func TestLoadPrivateKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	path := filepath.Join(t.TempDir(), "AuthKey.p8")
+	os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+
+	loaded, err := LoadPrivateKey(path)
+	assert.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	der, _ = x509.MarshalPKCS8PrivateKey(rsaKey)
+	os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	_, err = LoadPrivateKey(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package apple
+
+import (
+	jwtLib "github.com/golang-jwt/jwt/v4"
+)
+
+type GetTokenErrorBody struct {
+	Error string `json:"error"`
+}
+
+type GetTokenSuccessBody struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    uint32 `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IdToken      string `json:"id_token"`
+}
+
+type IdTokenClaims struct {
+	Email          string     `json:"email"`
+	EmailVerified  boolString `json:"email_verified"`
+	IsPrivateEmail boolString `json:"is_private_email"`
+	jwtLib.RegisteredClaims
+}
+
+// 애플은 boolean 값을 문자열("true")로 보내기도 한다.
+type boolString bool
+
+func (b *boolString) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `true`, `"true"`:
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
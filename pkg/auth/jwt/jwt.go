@@ -1,7 +1,10 @@
 package jwt
 
 import (
+	"crypto"
+	"sort"
 	"strings"
+	"time"
 
 	jwtLib "github.com/golang-jwt/jwt/v4"
 	"github.com/pkg/errors"
@@ -10,6 +13,9 @@ import (
 type JwtOption interface {
 	WithSigningMethod(method jwtLib.SigningMethod) JwtOption
 	WithSignKey(key string) JwtOption
+	WithHMACUntil(until time.Time) JwtOption
+	WithPrivateKey(kid string, key crypto.Signer) JwtOption
+	WithVerifyKey(kid string, key crypto.PublicKey) JwtOption
 
 	Init() Jwt
 }
@@ -17,6 +23,8 @@ type JwtOption interface {
 type Jwt interface {
 	GenerateToken(claim jwtLib.Claims) (string, error)
 	ParseToken(tokenString string, result jwtLib.Claims) error
+	// 검증용 공개키 목록. HMAC 키는 공개하지 않는다.
+	JWKS() JWKSet
 }
 
 const (
@@ -39,9 +47,14 @@ func NewJwt() JwtOption {
 }
 
 func (a *jwt) GenerateToken(claim jwtLib.Claims) (string, error) {
-	signKey := []byte(a.option.signKey)
+	var signKey interface{} = []byte(a.option.signKey)
 
 	token := jwtLib.NewWithClaims(a.option.signingMethod, claim)
+	if a.option.privateKey != nil {
+		token.Header["kid"] = a.option.keyId
+		signKey = a.option.privateKey
+	}
+
 	tokenString, err := token.SignedString(signKey)
 	if err != nil {
 		return "", err
@@ -51,12 +64,7 @@ func (a *jwt) GenerateToken(claim jwtLib.Claims) (string, error) {
 }
 
 func (a *jwt) ParseToken(tokenString string, result jwtLib.Claims) error {
-	token, err := jwtLib.ParseWithClaims(tokenString, result, func(token *jwtLib.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwtLib.SigningMethodHMAC); !ok {
-			return nil, errors.New(ErrInvalidToken)
-		}
-		return []byte(a.option.signKey), nil
-	})
+	token, err := jwtLib.ParseWithClaims(tokenString, result, a.verifyKey)
 
 	if err != nil || !token.Valid {
 		if strings.Contains(err.Error(), "expired") {
@@ -67,3 +75,48 @@ func (a *jwt) ParseToken(tokenString string, result jwtLib.Claims) error {
 
 	return nil
 }
+
+// kid 가 있으면 등록된 공개키로, 없으면 HMAC signKey 로 검증한다.
+func (a *jwt) verifyKey(token *jwtLib.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := token.Method.(*jwtLib.SigningMethodHMAC); !ok || !a.option.acceptHMAC {
+			return nil, errors.New(ErrInvalidToken)
+		}
+		if a.option.privateKey != nil && !a.option.hmacUntil.IsZero() && time.Now().After(a.option.hmacUntil) {
+			return nil, errors.New(ErrInvalidToken)
+		}
+		return []byte(a.option.signKey), nil
+	}
+
+	key, ok := a.option.verifyKeys[kid]
+	if !ok || !isMethodFor(token.Method, key) {
+		return nil, errors.New(ErrInvalidToken)
+	}
+	return key, nil
+}
+
+func (a *jwt) JWKS() JWKSet {
+	kids := make([]string, 0, len(a.option.verifyKeys))
+	for kid := range a.option.verifyKeys {
+		if kid != a.option.keyId {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	// 서명에 사용 중인 키를 맨 앞에 둔다.
+	if _, ok := a.option.verifyKeys[a.option.keyId]; ok {
+		kids = append([]string{a.option.keyId}, kids...)
+	}
+
+	set := JWKSet{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		key := a.option.verifyKeys[kid]
+		alg := signingMethodFor(key).Alg()
+		if kid == a.option.keyId {
+			alg = a.option.signingMethod.Alg()
+		}
+		set.Keys = append(set.Keys, newJWK(kid, alg, key))
+	}
+	return set
+}
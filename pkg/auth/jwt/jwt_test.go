@@ -1,6 +1,13 @@
 package jwt
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -130,3 +137,134 @@ func TestJwtCustomConfig(t *testing.T) {
 		}
 	})
 }
+
+func TestJwtAsymmetric(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	t.Run("RS256 생성 및 파싱", func(t *testing.T) {
+		module := NewJwt().WithPrivateKey("rsa-1", rsaKey).Init()
+		token, err := module.GenerateToken(customClm)
+		assert.NoError(err)
+
+		parsed, _, _ := new(jwtLib.Parser).ParseUnverified(token, &customClaim{})
+		assert.Equal("RS256", parsed.Method.Alg())
+		assert.Equal("rsa-1", parsed.Header["kid"])
+
+		var res customClaim
+		assert.NoError(module.ParseToken(token, &res))
+		assert.Equal("세훈", res.Name)
+	})
+
+	t.Run("EdDSA 생성 및 파싱", func(t *testing.T) {
+		module := NewJwt().WithPrivateKey("", edKey).Init()
+		token, err := module.GenerateToken(customClm)
+		assert.NoError(err)
+
+		parsed, _, _ := new(jwtLib.Parser).ParseUnverified(token, &customClaim{})
+		assert.Equal("EdDSA", parsed.Method.Alg())
+		thumbprint, _ := Thumbprint(edKey.Public())
+		assert.Equal(thumbprint, parsed.Header["kid"])
+
+		var res customClaim
+		assert.NoError(module.ParseToken(token, &res))
+	})
+
+	t.Run("키 교체 후에도 이전 키로 서명된 토큰 검증", func(t *testing.T) {
+		old := NewJwt().WithPrivateKey("old", rsaKey).Init()
+		token, _ := old.GenerateToken(customClm)
+
+		rotated := NewJwt().WithPrivateKey("new", edKey).WithVerifyKey("old", rsaKey.Public()).Init()
+		var res customClaim
+		assert.NoError(rotated.ParseToken(token, &res))
+
+		// 이전 키를 제거하면 검증 실패
+		removed := NewJwt().WithPrivateKey("new", edKey).Init()
+		assert.EqualError(removed.ParseToken(token, &res), ErrInvalidToken)
+	})
+
+	t.Run("kid 의 키 타입과 alg 가 다르면 실패", func(t *testing.T) {
+		module := NewJwt().WithPrivateKey("rsa-1", rsaKey).Init()
+		token := jwtLib.NewWithClaims(jwtLib.SigningMethodEdDSA, customClm)
+		token.Header["kid"] = "rsa-1"
+		tokenString, _ := token.SignedString(edKey)
+
+		var res customClaim
+		assert.EqualError(module.ParseToken(tokenString, &res), ErrInvalidToken)
+	})
+
+	t.Run("kid 없는 HMAC 토큰은 signKey 를 설정했을 때만 허용", func(t *testing.T) {
+		legacy, _ := NewJwt().WithSignKey("legacy").Init().GenerateToken(customClm)
+
+		var res customClaim
+		accepted := NewJwt().WithPrivateKey("rsa-1", rsaKey).WithSignKey("legacy").Init()
+		assert.NoError(accepted.ParseToken(legacy, &res))
+
+		rejected := NewJwt().WithPrivateKey("rsa-1", rsaKey).Init()
+		assert.EqualError(rejected.ParseToken(legacy, &res), ErrInvalidToken)
+
+		// 허용 기간이 지나면 signKey 가 있어도 받지 않는다.
+		inPeriod := NewJwt().WithPrivateKey("rsa-1", rsaKey).WithSignKey("legacy").WithHMACUntil(time.Now().Add(time.Hour)).Init()
+		assert.NoError(inPeriod.ParseToken(legacy, &res))
+		expired := NewJwt().WithPrivateKey("rsa-1", rsaKey).WithSignKey("legacy").WithHMACUntil(time.Now().Add(-time.Hour)).Init()
+		assert.EqualError(expired.ParseToken(legacy, &res), ErrInvalidToken)
+	})
+
+	t.Run("JWKS", func(t *testing.T) {
+		module := NewJwt().WithPrivateKey("new", edKey).WithVerifyKey("old", rsaKey.Public()).WithSignKey("secret").Init()
+		set := module.JWKS()
+
+		assert.Len(set.Keys, 2)
+		assert.Equal("new", set.Keys[0].Kid)
+		assert.Equal("OKP", set.Keys[0].Kty)
+		assert.Equal("EdDSA", set.Keys[0].Alg)
+		assert.Equal("old", set.Keys[1].Kid)
+		assert.Equal("RSA", set.Keys[1].Kty)
+		assert.Equal("RS256", set.Keys[1].Alg)
+		assert.Equal("AQAB", set.Keys[1].E)
+
+		assert.Empty(NewJwt().WithSignKey("secret").Init().JWKS().Keys)
+	})
+}
+
+func TestLoadKey(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	write := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600)
+		return path
+	}
+
+	pkcs1 := write("rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	edDer, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	pkcs8 := write("ed.pem", "PRIVATE KEY", edDer)
+	pubDer, _ := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	pub := write("rsa.pub", "PUBLIC KEY", pubDer)
+
+	signer, err := LoadPrivateKey(pkcs1)
+	assert.NoError(err)
+	assert.IsType(&rsa.PrivateKey{}, signer)
+
+	signer, err = LoadPrivateKey(pkcs8)
+	assert.NoError(err)
+	assert.IsType(ed25519.PrivateKey{}, signer)
+
+	publicKey, err := LoadPublicKey(pub)
+	assert.NoError(err)
+	assert.True(rsaKey.PublicKey.Equal(publicKey))
+
+	// 개인키 파일에서 공개키만 사용
+	publicKey, err = LoadPublicKey(pkcs8)
+	assert.NoError(err)
+	assert.True(edKey.Public().(ed25519.PublicKey).Equal(publicKey))
+
+	_, err = LoadPrivateKey(filepath.Join(dir, "none.pem"))
+	assert.Error(err)
+}
@@ -0,0 +1,207 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+
+	jwtLib "github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+)
+
+// RFC 7517 JSON Web Key. 공개키만 담는다.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PEM 파일에서 서명용 개인키를 읽는다. PKCS#8(RSA, Ed25519)과 PKCS#1(RSA)을 지원한다.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKeyPEM(data)
+}
+
+// PEM 파일에서 검증용 공개키를 읽는다. PKIX 공개키와 PKCS#1 RSA 공개키, 개인키(공개키만 사용)를 지원한다.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeyPEM(data)
+}
+
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: PEM 형식이 아닙니다")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt: 지원하지 않는 PEM 타입입니다 (%s)", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("jwt: 지원하지 않는 키 타입입니다 (%T)", key)
+}
+
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: PEM 형식이 아닙니다")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "RSA PRIVATE KEY", "PRIVATE KEY":
+		signer, errP := ParsePrivateKeyPEM(data)
+		if errP != nil {
+			return nil, errP
+		}
+		key = signer.Public()
+	default:
+		return nil, fmt.Errorf("jwt: 지원하지 않는 PEM 타입입니다 (%s)", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if signingMethodFor(key) == nil {
+		return nil, fmt.Errorf("jwt: 지원하지 않는 키 타입입니다 (%T)", key)
+	}
+	return key, nil
+}
+
+// RFC 7638 JWK Thumbprint. kid 를 따로 지정하지 않으면 이 값을 사용한다.
+func Thumbprint(key crypto.PublicKey) (string, error) {
+	var input string
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		input = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, rsaExponent(k), encode(k.N.Bytes()))
+	case ed25519.PublicKey:
+		input = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, encode(k))
+	default:
+		return "", fmt.Errorf("jwt: 지원하지 않는 키 타입입니다 (%T)", key)
+	}
+	sum := sha256.Sum256([]byte(input))
+	return encode(sum[:]), nil
+}
+
+// 키 타입에 맞는 기본 서명 방식
+func signingMethodFor(key interface{}) jwtLib.SigningMethod {
+	switch key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return jwtLib.SigningMethodRS256
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return jwtLib.SigningMethodEdDSA
+	}
+	return nil
+}
+
+// 토큰 헤더의 alg 가 키 타입과 맞는지 확인한다. (alg 를 바꿔치기하는 공격 방지)
+func isMethodFor(method jwtLib.SigningMethod, key crypto.PublicKey) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		_, ok := method.(*jwtLib.SigningMethodRSA)
+		return ok
+	case ed25519.PublicKey:
+		_, ok := method.(*jwtLib.SigningMethodEd25519)
+		return ok
+	}
+	return false
+}
+
+// JWK 를 검증용 공개키로 변환한다. RSA 와 Ed25519 만 지원한다.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > math.MaxInt32 {
+			return nil, errors.New("jwt: RSA JWK 형식이 올바르지 않습니다")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwt: Ed25519 JWK 형식이 올바르지 않습니다")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("jwt: 지원하지 않는 키 타입입니다 (%s)", k.Kty)
+}
+
+func newJWK(kid string, alg string, key crypto.PublicKey) JWK {
+	jwk := JWK{Kid: kid, Use: "sig", Alg: alg}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encode(k.N.Bytes())
+		jwk.E = rsaExponent(k)
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encode(k)
+	}
+	return jwk
+}
+
+func rsaExponent(k *rsa.PublicKey) string {
+	return encode(big.NewInt(int64(k.E)).Bytes())
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
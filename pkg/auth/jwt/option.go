@@ -1,12 +1,27 @@
 package jwt
 
 import (
+	"crypto"
+	"fmt"
+	"time"
+
 	jwtLib "github.com/golang-jwt/jwt/v4"
 )
 
 type option struct {
 	signKey       string
 	signingMethod jwtLib.SigningMethod
+
+	// 비대칭키 서명. 설정되면 signKey 대신 사용하고 헤더에 kid 를 넣는다.
+	privateKey crypto.Signer
+	keyId      string
+	// kid 별 검증용 공개키. 키 교체 중에는 이전 키도 함께 등록해둔다.
+	verifyKeys map[string]crypto.PublicKey
+	// 비대칭키를 사용할 때 kid 없는 HMAC 토큰(교체 전 발급된 토큰)을 signKey 로 검증할지 여부
+	isSignKeySet bool
+	acceptHMAC   bool
+	// 비대칭키를 사용할 때 HMAC 토큰을 이 시각까지만 허용한다. (zero 면 제한 없음)
+	hmacUntil time.Time
 }
 
 func (a *jwt) WithSigningMethod(method jwtLib.SigningMethod) JwtOption {
@@ -16,9 +31,58 @@ func (a *jwt) WithSigningMethod(method jwtLib.SigningMethod) JwtOption {
 
 func (a *jwt) WithSignKey(key string) JwtOption {
 	a.option.signKey = key
+	a.option.isSignKeySet = true
+	return a
+}
+
+// 비대칭키로 바꾼 뒤 kid 없는 HMAC 토큰을 until 까지만 허용한다.
+func (a *jwt) WithHMACUntil(until time.Time) JwtOption {
+	a.option.hmacUntil = until
+	return a
+}
+
+// RSA(RS256) 또는 Ed25519(EdDSA) 개인키로 서명한다. kid 가 비어있으면 공개키의 Thumbprint 를 사용한다.
+func (a *jwt) WithPrivateKey(kid string, key crypto.Signer) JwtOption {
+	kid = a.addVerifyKey(kid, key.Public())
+	a.option.privateKey = key
+	a.option.keyId = kid
+	return a
+}
+
+// 검증에만 사용하는 공개키를 추가한다. kid 가 비어있으면 공개키의 Thumbprint 를 사용한다.
+func (a *jwt) WithVerifyKey(kid string, key crypto.PublicKey) JwtOption {
+	a.addVerifyKey(kid, key)
 	return a
 }
 
 func (a *jwt) Init() Jwt {
+	if a.option.privateKey == nil {
+		a.option.acceptHMAC = true
+		return a
+	}
+
+	// 서명 방식이 키와 맞지 않으면(기본값 HS256 포함) 키 타입의 기본 방식을 사용한다.
+	if !isMethodFor(a.option.signingMethod, a.option.privateKey.Public()) {
+		a.option.signingMethod = signingMethodFor(a.option.privateKey)
+	}
+	if a.option.signingMethod == nil {
+		panic(fmt.Sprintf("jwt: 지원하지 않는 키 타입입니다 (%T)", a.option.privateKey))
+	}
+	a.option.acceptHMAC = a.option.isSignKeySet
 	return a
 }
+
+func (a *jwt) addVerifyKey(kid string, key crypto.PublicKey) string {
+	if kid == "" {
+		thumbprint, err := Thumbprint(key)
+		if err != nil {
+			panic(err)
+		}
+		kid = thumbprint
+	}
+	if a.option.verifyKeys == nil {
+		a.option.verifyKeys = make(map[string]crypto.PublicKey)
+	}
+	a.option.verifyKeys[kid] = key
+	return kid
+}
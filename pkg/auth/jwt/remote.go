@@ -0,0 +1,141 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwtLib "github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// 응답에 Cache-Control max-age 가 없을 때 캐시 기간
+	defaultKeySetTTL = time.Hour
+	// 모르는 kid 가 들어올 때마다 다시 가져오지 않도록 최소 간격을 둔다.
+	minKeySetRefreshInterval = 10 * time.Second
+)
+
+// 외부 인증 서버(구글, 애플 등)가 공개하는 JWKS 를 가져와 kid 별로 캐시한다.
+// 캐시가 만료됐거나 모르는 kid 가 들어오면 다시 가져온다. (키 교체 대응)
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	expiredAt time.Time
+}
+
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		now:    time.Now,
+	}
+}
+
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key, ok := s.keys[kid]
+	if ok && now.Before(s.expiredAt) {
+		return key, nil
+	}
+
+	if s.keys == nil || !now.Before(s.expiredAt) || now.Sub(s.fetchedAt) >= minKeySetRefreshInterval {
+		if err := s.refresh(ctx, now); err != nil {
+			// 가져오지 못해도 이전에 받아둔 키가 있으면 사용한다.
+			if ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		if key, ok = s.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("jwt: 알 수 없는 kid 입니다 (%s)", kid)
+}
+
+// jwtLib.ParseWithClaims 에 넘기는 Keyfunc. 헤더의 kid 로 키를 찾고 alg 가 키 타입과 맞는지 확인한다.
+func (s *RemoteKeySet) Keyfunc(token *jwtLib.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("jwt: kid 가 없습니다")
+	}
+
+	key, err := s.Key(context.Background(), kid)
+	if err != nil {
+		return nil, err
+	}
+	if !isMethodFor(token.Method, key) {
+		return nil, fmt.Errorf("jwt: 키와 맞지 않는 서명 방식입니다 (%s)", token.Method.Alg())
+	}
+	return key, nil
+}
+
+// mu 를 잡은 상태에서 호출한다.
+func (s *RemoteKeySet) refresh(ctx context.Context, now time.Time) error {
+	s.fetchedAt = now
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwt: JWKS 를 가져오지 못했습니다 (%d)", resp.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// 지원하지 않는 키는 건너뛴다.
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	s.keys = keys
+	s.expiredAt = now.Add(maxAge(resp.Header.Get("Cache-Control")))
+	return nil
+}
+
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if !found || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultKeySetTTL
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtLib "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteKeySet(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	var fetched int32
+	set := JWKSet{Keys: []JWK{newJWK("rsa-1", "RS256", rsaKey.Public())}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetched, 1)
+		w.Header().Set("Cache-Control", "public, max-age=600, must-revalidate")
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	keySet := NewRemoteKeySet(server.URL)
+	keySet.now = func() time.Time { return now }
+
+	t.Run("kid 로 키를 찾고 캐시한다", func(t *testing.T) {
+		key, err := keySet.Key(context.Background(), "rsa-1")
+		assert.NoError(err)
+		assert.True(rsaKey.PublicKey.Equal(key))
+
+		_, err = keySet.Key(context.Background(), "rsa-1")
+		assert.NoError(err)
+		assert.EqualValues(1, atomic.LoadInt32(&fetched))
+	})
+
+	t.Run("모르는 kid 는 최소 간격이 지난 뒤에만 다시 가져온다", func(t *testing.T) {
+		set.Keys = append(set.Keys, newJWK("ed-1", "EdDSA", edPub))
+
+		_, err := keySet.Key(context.Background(), "ed-1")
+		assert.Error(err)
+		assert.EqualValues(1, atomic.LoadInt32(&fetched))
+
+		now = now.Add(minKeySetRefreshInterval)
+		key, err := keySet.Key(context.Background(), "ed-1")
+		assert.NoError(err)
+		assert.True(edPub.Equal(key))
+		assert.EqualValues(2, atomic.LoadInt32(&fetched))
+	})
+
+	t.Run("max-age 가 지나면 다시 가져온다", func(t *testing.T) {
+		now = now.Add(601 * time.Second)
+		_, err := keySet.Key(context.Background(), "rsa-1")
+		assert.NoError(err)
+		assert.EqualValues(3, atomic.LoadInt32(&fetched))
+	})
+
+	t.Run("Keyfunc", func(t *testing.T) {
+		keySet.now = time.Now
+		claims := jwtLib.RegisteredClaims{ExpiresAt: jwtLib.NewNumericDate(time.Now().Add(time.Minute))}
+
+		token := jwtLib.NewWithClaims(jwtLib.SigningMethodRS256, claims)
+		token.Header["kid"] = "rsa-1"
+		signed, _ := token.SignedString(rsaKey)
+		_, err := jwtLib.ParseWithClaims(signed, &jwtLib.RegisteredClaims{}, keySet.Keyfunc)
+		assert.NoError(err)
+
+		// kid 가 없는 토큰
+		signed, _ = jwtLib.NewWithClaims(jwtLib.SigningMethodRS256, claims).SignedString(rsaKey)
+		_, err = jwtLib.ParseWithClaims(signed, &jwtLib.RegisteredClaims{}, keySet.Keyfunc)
+		assert.Error(err)
+
+		// RSA 공개키를 HMAC 키로 쓰는 alg 바꿔치기
+		token = jwtLib.NewWithClaims(jwtLib.SigningMethodHS256, claims)
+		token.Header["kid"] = "rsa-1"
+		signed, _ = token.SignedString([]byte("secret"))
+		_, err = jwtLib.ParseWithClaims(signed, &jwtLib.RegisteredClaims{}, keySet.Keyfunc)
+		assert.Error(err)
+	})
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	key, err := newJWK("rsa", "RS256", rsaKey.Public()).PublicKey()
+	assert.NoError(err)
+	assert.True(rsaKey.PublicKey.Equal(key))
+
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	key, err = newJWK("ed", "EdDSA", edPub).PublicKey()
+	assert.NoError(err)
+	assert.True(edPub.Equal(key))
+
+	_, err = JWK{Kty: "EC", Crv: "P-256"}.PublicKey()
+	assert.Error(err)
+	_, err = JWK{Kty: "RSA", N: "!!", E: "AQAB"}.PublicKey()
+	assert.Error(err)
+}
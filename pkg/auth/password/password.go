@@ -0,0 +1,140 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// 비밀번호를 argon2id 로 해시한다. 솔트와 파라미터는 해시 문자열(PHC 형식)에 함께 저장된다.
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+type Hasher interface {
+	Hash(password string) (string, error)
+	// needsRehash 는 비밀번호가 일치하고, 기존 SHA-256 해시이거나 현재 파라미터와 다른 해시일 때 true 이다.
+	Verify(hash string, password string) (ok bool, needsRehash bool)
+}
+
+type Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// RFC 9106 권장값 (64 MiB, t=3, p=4)
+var DefaultParams = Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+const prefix = "$argon2id$"
+
+// 기존 방식: sha256(secret + password) 의 hex 문자열
+var legacyHashRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type hasher struct {
+	params       Params
+	legacySecret string
+}
+
+// legacySecret 은 기존 SHA-256 해시를 검증할 때만 사용한다.
+func NewHasher(params Params, legacySecret string) Hasher {
+	return &hasher{
+		params:       params,
+		legacySecret: legacySecret,
+	}
+}
+
+func (h *hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		prefix,
+		argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *hasher) Verify(hash string, password string) (ok bool, needsRehash bool) {
+	if strings.HasPrefix(hash, prefix) {
+		params, salt, key, err := decode(hash)
+		if err != nil {
+			return false, false
+		}
+
+		other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(key, other) != 1 {
+			return false, false
+		}
+
+		needsRehash = params.Memory != h.params.Memory ||
+			params.Iterations != h.params.Iterations ||
+			params.Parallelism != h.params.Parallelism ||
+			uint32(len(salt)) != h.params.SaltLength ||
+			uint32(len(key)) != h.params.KeyLength
+		return true, needsRehash
+	}
+
+	if legacyHashRegex.MatchString(hash) {
+		sha := sha256.New()
+		sha.Write([]byte(h.legacySecret))
+		sha.Write([]byte(password))
+		other := hex.EncodeToString(sha.Sum(nil))
+		ok = subtle.ConstantTimeCompare([]byte(hash), []byte(other)) == 1
+		return ok, ok
+	}
+
+	return false, false
+}
+
+func decode(hash string) (params Params, salt []byte, key []byte, err error) {
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		err = fmt.Errorf("password: 해시 형식이 올바르지 않습니다")
+		return
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return
+	}
+	if version != argon2.Version {
+		err = fmt.Errorf("password: 지원하지 않는 argon2 버전입니다 (%d)", version)
+		return
+	}
+
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return
+	}
+	if len(key) == 0 {
+		err = fmt.Errorf("password: 해시 형식이 올바르지 않습니다")
+	}
+	return
+}
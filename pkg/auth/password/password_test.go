@@ -0,0 +1,76 @@
+package password
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 테스트 속도를 위해 작은 파라미터 사용
+var testParams = Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestHash(t *testing.T) {
+	h := NewHasher(testParams, "secret")
+
+	t.Run("같은 비밀번호라도 솔트가 달라 해시가 다르다", func(t *testing.T) {
+		a, err := h.Hash("password1234")
+		assert.NoError(t, err)
+		b, err := h.Hash("password1234")
+		assert.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(a, "$argon2id$v=19$m=1024,t=1,p=1$"))
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("검증", func(t *testing.T) {
+		hash, _ := h.Hash("password1234")
+
+		ok, needsRehash := h.Verify(hash, "password1234")
+		assert.True(t, ok)
+		assert.False(t, needsRehash)
+
+		ok, _ = h.Verify(hash, "password12345")
+		assert.False(t, ok)
+	})
+
+	t.Run("파라미터가 바뀌면 재해시 필요", func(t *testing.T) {
+		hash, _ := h.Hash("password1234")
+
+		stronger := testParams
+		stronger.Iterations = 2
+		ok, needsRehash := NewHasher(stronger, "secret").Verify(hash, "password1234")
+		assert.True(t, ok)
+		assert.True(t, needsRehash)
+	})
+
+	t.Run("잘못된 해시 문자열", func(t *testing.T) {
+		for _, hash := range []string{"", "plain", "$argon2id$v=19$m=1024,t=1,p=1$salt", "$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5"} {
+			ok, _ := h.Verify(hash, "password1234")
+			assert.False(t, ok, hash)
+		}
+	})
+}
+
+func TestLegacyHash(t *testing.T) {
+	h := NewHasher(testParams, "secret")
+	legacy := fmt.Sprintf("%x", sha256.Sum256([]byte("secret"+"password1234")))
+
+	ok, needsRehash := h.Verify(legacy, "password1234")
+	assert.True(t, ok)
+	assert.True(t, needsRehash)
+
+	ok, _ = h.Verify(legacy, "wrong")
+	assert.False(t, ok)
+
+	ok, _ = NewHasher(testParams, "other").Verify(legacy, "password1234")
+	assert.False(t, ok)
+}
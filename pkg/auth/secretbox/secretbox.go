@@ -0,0 +1,82 @@
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// DB 에 저장하는 비밀값(TOTP 비밀키 등)을 AES-256-GCM 으로 암호화한다.
+// 암호문은 nonce 를 앞에 붙여 base64 로 인코딩하고, 키를 교체할 수 있도록 버전을 붙인다.
+//
+//	v1.<base64(nonce + ciphertext)>
+type Box interface {
+	Seal(plaintext string) (string, error)
+	Open(sealed string) (string, error)
+}
+
+const (
+	KeySize = 32 // byte, AES-256
+
+	version = "v1."
+)
+
+var (
+	ErrKeyInvalid    = errors.New("secretbox: key must be base64 encoded 32 bytes")
+	ErrSealedInvalid = errors.New("secretbox: invalid sealed value")
+)
+
+var encoding = base64.RawStdEncoding
+
+type box struct {
+	aead cipher.AEAD
+}
+
+// key 는 base64 로 인코딩된 32byte 키 (openssl rand -base64 32)
+func NewBox(key string) (Box, error) {
+	k, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(k) != KeySize {
+		return nil, ErrKeyInvalid
+	}
+
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &box{aead: aead}, nil
+}
+
+func (b *box) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return version + encoding.EncodeToString(sealed), nil
+}
+
+func (b *box) Open(sealed string) (string, error) {
+	if !strings.HasPrefix(sealed, version) {
+		return "", ErrSealedInvalid
+	}
+
+	v, err := encoding.DecodeString(strings.TrimPrefix(sealed, version))
+	if err != nil || len(v) < b.aead.NonceSize() {
+		return "", ErrSealedInvalid
+	}
+
+	nonce, ciphertext := v[:b.aead.NonceSize()], v[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrSealedInvalid
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,60 @@
+package secretbox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=" // "0123456789abcdef0123456789abcdef"
+
+func TestSealOpen(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewBox(testKey)
+	assert.NoError(err)
+
+	sealed, err := b.Seal("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	assert.NoError(err)
+	assert.True(strings.HasPrefix(sealed, version))
+	assert.NotContains(sealed, "GEZDGNBVGY3TQOJQ")
+
+	plaintext, err := b.Open(sealed)
+	assert.NoError(err)
+	assert.Equal("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", plaintext)
+
+	t.Run("같은 값도 매번 다른 암호문", func(t *testing.T) {
+		again, _ := b.Seal("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+		assert.NotEqual(sealed, again)
+	})
+
+	t.Run("변조된 암호문", func(t *testing.T) {
+		i := len(sealed) / 2
+		c := byte('A')
+		if sealed[i] == c {
+			c = 'B'
+		}
+		tampered := sealed[:i] + string(c) + sealed[i+1:]
+		_, err := b.Open(tampered)
+		assert.ErrorIs(err, ErrSealedInvalid)
+	})
+
+	t.Run("암호화되지 않은 값", func(t *testing.T) {
+		_, err := b.Open("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+		assert.ErrorIs(err, ErrSealedInvalid)
+	})
+
+	t.Run("다른 키", func(t *testing.T) {
+		other, _ := NewBox("ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=")
+		_, err := other.Open(sealed)
+		assert.ErrorIs(err, ErrSealedInvalid)
+	})
+}
+
+func TestNewBox(t *testing.T) {
+	_, err := NewBox("")
+	assert.ErrorIs(t, err, ErrKeyInvalid)
+
+	_, err = NewBox("c2hvcnQ=")
+	assert.ErrorIs(t, err, ErrKeyInvalid)
+}
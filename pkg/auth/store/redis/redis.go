@@ -5,8 +5,53 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v9"
+	"github.com/google/uuid"
 )
 
+// 값이 같을 때만 지워야 다른 인스턴스가 잡은 락을 풀지 않는다.
+var delIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// 읽고 쓰는 사이에 다른 요청이 값을 바꾸지 못하도록 한 번에 비교하고 교체한다.
+var hSetIfEqualScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], ARGV[4])
+	return 1
+end
+return 0
+`)
+
+// 처음 만들 때만 만료 시간을 설정해야 횟수를 셀 때마다 기간이 늘어나지 않는다.
+var incrScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// 요청 시각(ms)을 score 로 sorted set 에 쌓고 window 밖의 기록은 지운다.
+// 여러 서버에서 같은 시계를 쓰도록 Redis 의 TIME 을 기준으로 한다.
+var slidingWindowScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+if redis.call("ZCARD", KEYS[1]) < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], window)
+	return {1, 0}
+end
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
 type store struct {
 	cli *redis.Client
 }
@@ -31,6 +76,14 @@ func (s *store) HSet(ctx context.Context, key string, field string, value string
 	return connect.Expire(ctx, key, expiration).Err()
 }
 
+func (s *store) HSetIfEqual(ctx context.Context, key string, field string, expected string, value string, expiration time.Duration) (bool, error) {
+	swapped, err := hSetIfEqualScript.Run(ctx, s.cli, []string{key}, field, expected, value, expiration.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return swapped > 0, nil
+}
+
 func (s *store) HDel(ctx context.Context, key, field string) (err error) {
 	connect := s.cli.Conn()
 
@@ -45,6 +98,14 @@ func (s *store) HGet(ctx context.Context, key string, field string) (string, err
 	return s.cli.HGet(ctx, key, field).Result()
 }
 
+func (s *store) HExists(ctx context.Context, key string, field string) (bool, error) {
+	return s.cli.HExists(ctx, key, field).Result()
+}
+
+func (s *store) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.cli.HGetAll(ctx, key).Result()
+}
+
 func (s *store) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
 	return s.cli.SetEx(ctx, key, value, expiration).Err()
 }
@@ -53,6 +114,14 @@ func (s *store) Get(ctx context.Context, key string) string {
 	return s.cli.Get(ctx, key).Val()
 }
 
+func (s *store) GetDel(ctx context.Context, key string) (string, error) {
+	val, err := s.cli.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
 func (s *store) Check(ctx context.Context, key string) (bool, error) {
 	val, err := s.cli.Exists(ctx, key).Result()
 	if err != nil {
@@ -65,3 +134,28 @@ func (s *store) Check(ctx context.Context, key string) (bool, error) {
 
 	return false, err
 }
+
+func (s *store) SetNX(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	return s.cli.SetNX(ctx, key, value, expiration).Result()
+}
+
+func (s *store) DelIfEqual(ctx context.Context, key string, value string) (bool, error) {
+	deleted, err := delIfEqualScript.Run(ctx, s.cli, []string{key}, value).Int()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+func (s *store) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return incrScript.Run(ctx, s.cli, []string{key}, expiration.Milliseconds()).Int64()
+}
+
+func (s *store) SlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	// 같은 ms 에 들어온 요청도 따로 세도록 member 는 매번 새로 만든다.
+	result, err := slidingWindowScript.Run(ctx, s.cli, []string{key}, window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -10,7 +10,23 @@ type Store interface {
 	Set(ctx context.Context, key string, value string, expiration time.Duration) error
 	HSet(ctx context.Context, key string, field string, value string, expiration time.Duration) error
 	HGet(ctx context.Context, key string, field string) (string, error)
+	// 필드의 값이 expected 와 같을 때만 value 로 바꾼다. (compare-and-swap)
+	HSetIfEqual(ctx context.Context, key string, field string, expected string, value string, expiration time.Duration) (bool, error)
 	HDel(ctx context.Context, key, field string) (err error)
+	HExists(ctx context.Context, key string, field string) (bool, error)
+	// 해시의 모든 필드와 값을 가져온다. key 가 없으면 빈 map 을 반환한다.
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
 	Get(ctx context.Context, key string) string
+	// 값을 가져오면서 지운다. (일회용 토큰) key 가 없으면 빈 문자열을 반환한다.
+	GetDel(ctx context.Context, key string) (string, error)
 	Check(ctx context.Context, key string) (bool, error)
+	// key 가 없을 때만 저장한다. (분산 락 획득)
+	SetNX(ctx context.Context, key string, value string, expiration time.Duration) (bool, error)
+	// 저장된 값이 value 와 같을 때만 삭제한다. (분산 락 해제)
+	DelIfEqual(ctx context.Context, key string, value string) (bool, error)
+	// 1 증가시킨 값을 반환한다. key 를 처음 만들 때만 expiration 을 설정한다. (횟수 제한)
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	// 최근 window 동안 limit 번까지 허용한다. (sliding window 요청 제한)
+	// 허용되면 이번 요청을 기록하고, 아니면 다시 허용될 때까지 남은 시간을 반환한다.
+	SlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, retryAfter time.Duration, err error)
 }
@@ -56,3 +56,80 @@ func TestHGet(t *testing.T) {
 	val, _ := store.HGet(context.Background(), "1", "asd")
 	assert.Equal(t, val, "asd")
 }
+
+func TestHGetAll(t *testing.T) {
+	c := config.NewConfig()
+	err := c.Load("../../../configs")
+	assert.NoError(t, err)
+	redisClient := infrastructure.NewRedis(c)
+	store := redis.NewStore(redisClient)
+	if err := store.HSet(context.Background(), "1", "asd", "asd", time.Duration(time.Second*24)); err != nil {
+		t.Error(err)
+	}
+	val, err := store.HGetAll(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "asd", val["asd"])
+}
+
+func TestHSetIfEqual(t *testing.T) {
+	c := config.NewConfig()
+	err := c.Load("../../../configs")
+	assert.NoError(t, err)
+	redisClient := infrastructure.NewRedis(c)
+	store := redis.NewStore(redisClient)
+	store.Del(context.Background(), "cas")
+	store.HSet(context.Background(), "cas", "field", "old", time.Minute)
+
+	swapped, err := store.HSetIfEqual(context.Background(), "cas", "field", "old", "new", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+
+	// 이미 바뀐 값이면 교체하지 않는다.
+	swapped, err = store.HSetIfEqual(context.Background(), "cas", "field", "old", "other", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+	val, _ := store.HGet(context.Background(), "cas", "field")
+	assert.Equal(t, "new", val)
+
+	swapped, err = store.HSetIfEqual(context.Background(), "cas", "missing", "old", "new", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+}
+
+func TestIncr(t *testing.T) {
+	c := config.NewConfig()
+	err := c.Load("../../../configs")
+	assert.NoError(t, err)
+	redisClient := infrastructure.NewRedis(c)
+	store := redis.NewStore(redisClient)
+	store.Del(context.Background(), "incr")
+
+	count, err := store.Incr(context.Background(), "incr", time.Minute)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	count, err = store.Incr(context.Background(), "incr", time.Hour)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.LessOrEqual(t, redisClient.TTL(context.Background(), "incr").Val(), time.Minute)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	c := config.NewConfig()
+	err := c.Load("../../../configs")
+	assert.NoError(t, err)
+	redisClient := infrastructure.NewRedis(c)
+	store := redis.NewStore(redisClient)
+	store.Del(context.Background(), "sliding")
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := store.SlidingWindow(context.Background(), "sliding", 3, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, retryAfter, err := store.SlidingWindow(context.Background(), "sliding", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Greater(t, retryAfter, time.Duration(0))
+	assert.LessOrEqual(t, retryAfter, time.Minute)
+	assert.EqualValues(t, 3, redisClient.ZCard(context.Background(), "sliding").Val())
+}
@@ -0,0 +1,88 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 부록 B 의 SHA1 테스트 벡터 (8자리 중 뒤 6자리)
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+
+func TestCode(t *testing.T) {
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	} {
+		code, err := Code(rfcSecret, time.Unix(tc.unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.code, code, tc.unix)
+	}
+
+	_, err := Code("not base32!", time.Now())
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1111111111, 0)
+	code, _ := Code(rfcSecret, now)
+
+	counter, ok := Validate(rfcSecret, code, now, 1)
+	assert.True(ok)
+	assert.EqualValues(1111111111/30, counter)
+
+	t.Run("앞뒤 한 구간까지 허용", func(t *testing.T) {
+		counter, ok := Validate(rfcSecret, code, now.Add(Period), 1)
+		assert.True(ok)
+		assert.EqualValues(1111111111/30, counter)
+
+		_, ok = Validate(rfcSecret, code, now.Add(2*Period), 1)
+		assert.False(ok)
+	})
+
+	t.Run("소문자, 공백이 섞인 비밀키", func(t *testing.T) {
+		_, ok := Validate("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", code, now, 0)
+		assert.True(ok)
+	})
+
+	t.Run("틀린 코드", func(t *testing.T) {
+		_, ok := Validate(rfcSecret, "000000", now, 1)
+		assert.False(ok)
+		_, ok = Validate(rfcSecret, code[:5], now, 1)
+		assert.False(ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, _ := GenerateSecret()
+	assert.NotEqual(t, secret, other)
+
+	_, err = Code(secret, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("onthemat", "user@onthemat.com", rfcSecret))
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/onthemat:user@onthemat.com", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "onthemat", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
@@ -1,26 +1,41 @@
 package email
 
 import (
-	"net/smtp"
+	"fmt"
 
 	"onthemat/internal/app/config"
 )
 
-type Email struct {
-	auth     smtp.Auth
-	username string
-	host     string
+// 메일을 보내는 방법. 운영은 SMTP, 개발은 파일, 테스트는 Recorder 를 사용한다.
+type Sender interface {
+	Send(msg *Message) error
 }
 
-func NewEmail(config *config.Config) *Email {
-	auth := smtp.PlainAuth("", config.Email.UserName, config.Email.Password, config.Email.Host)
-	return &Email{
-		auth:     auth,
-		username: config.Email.UserName,
-		host:     config.Email.Host,
+// 설정(EMAIL_TRANSPORT)에 맞는 Sender 를 만든다.
+func NewSender(config *config.Config) (Sender, error) {
+	c := config.Email
+	from := c.From
+	if from == "" {
+		from = c.UserName
 	}
-}
 
-func (e *Email) Send(to []string, msg []byte) error {
-	return smtp.SendMail(e.host+":587", e.auth, e.username, to, msg)
+	switch c.Transport {
+	case "", "smtp":
+		security, err := ParseSecurity(c.Security)
+		if err != nil {
+			return nil, err
+		}
+		return NewSMTP(SMTPConfig{
+			Host:     c.Host,
+			Port:     c.Port,
+			Security: security,
+			UserName: c.UserName,
+			Password: c.Password,
+			From:     from,
+		}), nil
+	case "file":
+		return NewFileSender(c.FileDir, from), nil
+	default:
+		return nil, fmt.Errorf("email: 알 수 없는 transport 입니다 (%s)", c.Transport)
+	}
 }
@@ -1,9 +1,15 @@
 package email
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"onthemat/internal/app/config"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSendEmail(t *testing.T) {
@@ -13,14 +19,78 @@ func TestSendEmail(t *testing.T) {
 		return
 	}
 
-	mailModule := NewEmail(c)
-	to := []string{"beardfriend21@gmail.com"}
-	subject := "본문 제목\r\n"
-	blank := "\r\n"
-	body := "본문 냉용\r\n"
-	msg := []byte(subject + blank + body)
-	if err := mailModule.Send(to, msg); err != nil {
+	sender, err := NewSender(c)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := sender.Send(&Message{
+		To:      []string{"beardfriend21@gmail.com"},
+		Subject: "본문 제목",
+		Text:    "본문 냉용",
+	}); err != nil {
 		t.Error(err)
 		return
 	}
 }
+
+func TestNewSender(t *testing.T) {
+	assert := assert.New(t)
+	c := config.NewConfig()
+
+	c.Email.Transport = "smtp"
+	c.Email.Security = "tls"
+	sender, err := NewSender(c)
+	assert.NoError(err)
+	assert.Equal(SecurityTLS, sender.(*smtpSender).config.Security)
+
+	c.Email.Security = "ssl"
+	_, err = NewSender(c)
+	assert.Error(err)
+
+	c.Email.Transport = "file"
+	sender, err = NewSender(c)
+	assert.NoError(err)
+	assert.IsType(&fileSender{}, sender)
+
+	c.Email.Transport = "sendmail"
+	_, err = NewSender(c)
+	assert.Error(err)
+}
+
+func TestFileSender(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	sender := NewFileSender(dir, "no-reply@onthemat.com")
+
+	assert.NoError(sender.Send(&Message{To: []string{"user@onthemat.com"}, Subject: "제목", Text: "본문"}))
+	assert.NoError(sender.Send(&Message{To: []string{"user@onthemat.com"}, Subject: "제목", HTML: "<p>본문</p>"}))
+
+	files, _ := os.ReadDir(filepath.Join(dir, "new"))
+	assert.Len(files, 2)
+	tmpFiles, _ := os.ReadDir(filepath.Join(dir, "tmp"))
+	assert.Len(tmpFiles, 0)
+
+	raw, _ := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+	assert.True(strings.HasPrefix(string(raw), "From: <no-reply@onthemat.com>\r\n"))
+}
+
+func TestRecorder(t *testing.T) {
+	assert := assert.New(t)
+	recorder := NewRecorder()
+
+	msg := &Message{To: []string{"user@onthemat.com"}, Subject: "제목", Text: "본문"}
+	assert.NoError(recorder.Send(msg))
+	msg.To[0] = "changed@onthemat.com"
+	assert.Equal("user@onthemat.com", recorder.Messages()[0].To[0])
+
+	// 보낼 수 없는 메일
+	assert.Error(recorder.Send(&Message{To: []string{"user@onthemat.com"}}))
+
+	recorder.Err = errors.New("421")
+	assert.Error(recorder.Send(msg))
+	assert.Len(recorder.Messages(), 1)
+
+	recorder.Reset()
+	assert.Empty(recorder.Messages())
+}
@@ -0,0 +1,54 @@
+package email
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileSender struct {
+	dir  string
+	from string
+	now  func() time.Time
+}
+
+// 메일을 보내지 않고 dir 에 .eml 파일로 저장한다. (개발용)
+// maildir 처럼 tmp 에 쓴 뒤 new 로 옮기므로, 읽는 쪽에서 쓰다 만 파일을 보지 않는다.
+func NewFileSender(dir string, from string) Sender {
+	return &fileSender{
+		dir:  dir,
+		from: from,
+		now:  time.Now,
+	}
+}
+
+func (s *fileSender) Send(msg *Message) error {
+	if msg.From == "" {
+		m := *msg
+		m.From = s.from
+		msg = &m
+	}
+	now := s.now()
+	raw, err := msg.Bytes(now)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range []string{"tmp", "new"} {
+		if err := os.MkdirAll(filepath.Join(s.dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	name := now.Format("20060102T150405.000000000") + "_" + hex.EncodeToString(b) + ".eml"
+
+	tmp := filepath.Join(s.dir, "tmp", name)
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, "new", name))
+}
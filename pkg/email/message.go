@@ -0,0 +1,134 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	// 비어있으면 Sender 의 기본 발신자를 사용한다.
+	From    string
+	To      []string
+	Subject string
+	// 둘 중 하나는 있어야 한다. 둘 다 있으면 multipart/alternative 로 보낸다.
+	Text string
+	HTML string
+}
+
+// RFC 5322 형식의 메일 원문을 만든다.
+// 제목은 RFC 2047 로 인코딩하고, 본문은 quoted-printable 로 인코딩한다.
+func (m *Message) Bytes(now time.Time) ([]byte, error) {
+	if m.From == "" {
+		return nil, errors.New("email: 보내는 사람이 없습니다")
+	}
+	if len(m.To) == 0 {
+		return nil, errors.New("email: 받는 사람이 없습니다")
+	}
+	if m.Text == "" && m.HTML == "" {
+		return nil, errors.New("email: 본문이 없습니다")
+	}
+
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, err
+	}
+	to := make([]string, 0, len(m.To))
+	for _, t := range m.To {
+		addr, err := mail.ParseAddress(t)
+		if err != nil {
+			return nil, err
+		}
+		to = append(to, addr.String())
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", encodeHeader(m.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageId(from.Address))
+	header("MIME-Version", "1.0")
+
+	if m.Text == "" || m.HTML == "" {
+		contentType, body := "text/plain", m.Text
+		if m.HTML != "" {
+			contentType, body = "text/html", m.HTML
+		}
+		header("Content-Type", contentType+`; charset="UTF-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	// 메일 클라이언트는 마지막 파트를 우선하므로 HTML 을 뒤에 둔다.
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain", m.Text},
+		{"text/html", m.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + `; charset="UTF-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	header("Content-Type", "multipart/alternative;\r\n boundary=\""+writer.Boundary()+"\"")
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// 헤더 인젝션을 막기 위해 줄바꿈을 지우고 RFC 2047 로 인코딩한다.
+// 길면 여러 encoded-word 로 나뉘므로 한 줄이 너무 길지 않도록 접는다.
+func encodeHeader(value string) string {
+	value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+	return strings.ReplaceAll(mime.BEncoding.Encode("UTF-8", value), "?= =?", "?=\r\n =?")
+}
+
+func messageId(from string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = from[i+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
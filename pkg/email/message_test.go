@@ -0,0 +1,123 @@
+package email
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageBytes(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+
+	t.Run("multipart/alternative", func(t *testing.T) {
+		html := "<p>" + strings.Repeat("비밀번호 재설정 ", 20) + "</p>"
+		raw, err := (&Message{
+			From:    "온더맷 <no-reply@onthemat.com>",
+			To:      []string{"user@onthemat.com", "관리자 <admin@onthemat.com>"},
+			Subject: "[온더맷] 비밀번호 재설정 안내 " + strings.Repeat("긴 제목 ", 10),
+			Text:    "비밀번호 재설정",
+			HTML:    html,
+		}).Bytes(now)
+		assert.NoError(err)
+
+		// 모든 줄은 ASCII 이고 접힌 제목과 본문은 78자를 넘지 않는다.
+		for _, line := range strings.Split(string(raw), "\r\n") {
+			assert.LessOrEqual(len(line), 78+len("Subject: "), line)
+			for _, r := range line {
+				assert.Less(r, rune(128), line)
+			}
+		}
+
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		assert.NoError(err)
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		assert.NoError(err)
+		assert.Equal("[온더맷] 비밀번호 재설정 안내 "+strings.Repeat("긴 제목 ", 10), subject)
+
+		from, err := msg.Header.AddressList("From")
+		assert.NoError(err)
+		assert.Equal("온더맷", from[0].Name)
+		to, err := msg.Header.AddressList("To")
+		assert.NoError(err)
+		assert.Len(to, 2)
+		assert.Equal("admin@onthemat.com", to[1].Address)
+
+		date, err := msg.Header.Date()
+		assert.NoError(err)
+		assert.True(now.Equal(date))
+		assert.True(strings.HasSuffix(msg.Header.Get("Message-ID"), "@onthemat.com>"))
+
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		assert.NoError(err)
+		assert.Equal("multipart/alternative", mediaType)
+
+		reader := multipart.NewReader(msg.Body, params["boundary"])
+		var contentTypes, bodies []string
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(err)
+			assert.Equal("quoted-printable", part.Header.Get("Content-Transfer-Encoding"))
+			body, err := io.ReadAll(quotedprintable.NewReader(part))
+			assert.NoError(err)
+			contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+			bodies = append(bodies, string(body))
+		}
+		assert.Equal([]string{`text/plain; charset="UTF-8"`, `text/html; charset="UTF-8"`}, contentTypes)
+		assert.Equal([]string{"비밀번호 재설정", html}, bodies)
+	})
+
+	t.Run("본문이 하나면 multipart 가 아님", func(t *testing.T) {
+		raw, err := (&Message{
+			From:    "no-reply@onthemat.com",
+			To:      []string{"user@onthemat.com"},
+			Subject: "plain",
+			HTML:    "<p>안녕하세요</p>",
+		}).Bytes(now)
+		assert.NoError(err)
+
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		assert.NoError(err)
+		assert.Equal(`text/html; charset="UTF-8"`, msg.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+		assert.Equal("<p>안녕하세요</p>", string(body))
+	})
+
+	t.Run("제목의 줄바꿈으로 헤더를 추가할 수 없음", func(t *testing.T) {
+		raw, err := (&Message{
+			From:    "no-reply@onthemat.com",
+			To:      []string{"user@onthemat.com"},
+			Subject: "제목\r\nBcc: attacker@example.com",
+			Text:    "본문",
+		}).Bytes(now)
+		assert.NoError(err)
+
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		assert.NoError(err)
+		assert.Empty(msg.Header.Get("Bcc"))
+	})
+
+	t.Run("잘못된 메일", func(t *testing.T) {
+		for _, m := range []*Message{
+			{To: []string{"user@onthemat.com"}, Text: "본문"},
+			{From: "no-reply@onthemat.com", Text: "본문"},
+			{From: "no-reply@onthemat.com", To: []string{"user@onthemat.com"}},
+			{From: "no-reply@onthemat.com", To: []string{"not-an-address"}, Text: "본문"},
+		} {
+			_, err := m.Bytes(now)
+			assert.Error(err)
+		}
+	})
+}
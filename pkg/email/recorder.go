@@ -0,0 +1,56 @@
+package email
+
+import (
+	"sync"
+	"time"
+)
+
+// 보낸 메일을 메모리에 쌓아두는 Sender. (테스트용)
+type Recorder struct {
+	mu       sync.Mutex
+	messages []Message
+	// 설정하면 Send 가 이 에러를 반환하고 메일을 쌓지 않는다.
+	Err error
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Send(msg *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Err != nil {
+		return r.Err
+	}
+
+	// 실제로 보낼 수 있는 메일인지 확인한다.
+	m := *msg
+	if m.From == "" {
+		m.From = "recorder@localhost"
+	}
+	if _, err := m.Bytes(time.Now()); err != nil {
+		return err
+	}
+
+	m.To = append([]string(nil), msg.To...)
+	r.messages = append(r.messages, m)
+	return nil
+}
+
+// 지금까지 보낸 메일
+func (r *Recorder) Messages() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Message(nil), r.messages...)
+}
+
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = nil
+	r.Err = nil
+}
@@ -0,0 +1,153 @@
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type Security int
+
+const (
+	// 평문으로 연결한 뒤 STARTTLS 로 암호화한다. (보통 587)
+	SecurityStartTLS Security = iota
+	// 연결부터 TLS 를 사용한다. (보통 465)
+	SecurityTLS
+	// 암호화하지 않는다. 로컬 개발용 SMTP 서버에만 사용한다.
+	SecurityNone
+)
+
+func ParseSecurity(s string) (Security, error) {
+	switch s {
+	case "", "starttls":
+		return SecurityStartTLS, nil
+	case "tls":
+		return SecurityTLS, nil
+	case "none":
+		return SecurityNone, nil
+	}
+	return 0, fmt.Errorf("email: 알 수 없는 security 입니다 (%s)", s)
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Security Security
+	// 비어있으면 인증하지 않는다.
+	UserName string
+	Password string
+	From     string
+}
+
+const smtpTimeout = 30 * time.Second
+
+type smtpSender struct {
+	config    SMTPConfig
+	tlsConfig *tls.Config
+	now       func() time.Time
+}
+
+func NewSMTP(config SMTPConfig) Sender {
+	return &smtpSender{
+		config:    config,
+		tlsConfig: &tls.Config{ServerName: config.Host},
+		now:       time.Now,
+	}
+}
+
+func (s *smtpSender) Send(msg *Message) error {
+	if msg.From == "" {
+		m := *msg
+		m.From = s.config.From
+		msg = &m
+	}
+	raw, err := msg.Bytes(s.now())
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.config.UserName != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("email: 서버가 AUTH 를 지원하지 않습니다")
+		}
+		if err := client.Auth(smtp.PlainAuth("", s.config.UserName, s.config.Password, s.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return err
+		}
+		if err := client.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *smtpSender) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if s.config.Security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, s.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(s.now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if s.config.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("email: 서버가 STARTTLS 를 지원하지 않습니다")
+		}
+		if err := client.StartTLS(s.tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
@@ -0,0 +1,217 @@
+package email
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 테스트용 로컬 SMTP 서버. 받은 메일을 기록만 한다.
+type smtpStandIn struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	// EHLO 응답에 STARTTLS 를 광고한다.
+	startTLS bool
+
+	mu   sync.Mutex
+	tls  bool
+	auth string
+	from string
+	rcpt []string
+	data []byte
+}
+
+func newSMTPStandIn(t *testing.T, tlsConfig *tls.Config, implicitTLS bool, startTLS bool) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if implicitTLS {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	s := &smtpStandIn{listener: listener, tlsConfig: tlsConfig, startTLS: startTLS}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, implicitTLS)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve(conn net.Conn, secured bool) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+
+		s.mu.Lock()
+		switch strings.ToUpper(cmd) {
+		case "EHLO":
+			extensions := []string{"localhost", "AUTH PLAIN"}
+			if s.startTLS && !secured {
+				extensions = append(extensions, "STARTTLS")
+			}
+			for i, ext := range extensions {
+				sep := "-"
+				if i == len(extensions)-1 {
+					sep = " "
+				}
+				tp.PrintfLine("250%s%s", sep, ext)
+			}
+		case "STARTTLS":
+			tp.PrintfLine("220 ready")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				s.mu.Unlock()
+				return
+			}
+			conn, secured = tlsConn, true
+			tp = textproto.NewConn(conn)
+		case "AUTH":
+			s.auth = arg
+			tp.PrintfLine("235 ok")
+		case "MAIL":
+			s.from = arg
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, arg)
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			s.data, _ = tp.ReadDotBytes()
+			s.tls = secured
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			s.mu.Unlock()
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+		s.mu.Unlock()
+	}
+}
+
+// 127.0.0.1 용 자체 서명 인증서
+func newTestTLSConfig(t *testing.T) (server *tls.Config, client *tls.Config) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	server = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	client = &tls.Config{ServerName: "127.0.0.1", RootCAs: pool}
+	return
+}
+
+func TestSMTPSender(t *testing.T) {
+	serverTLS, clientTLS := newTestTLSConfig(t)
+	msg := &Message{
+		To:      []string{"사용자 <user@onthemat.com>", "admin@onthemat.com"},
+		Subject: "[온더맷] 이메일 인증 안내",
+		Text:    "인증 링크",
+		HTML:    "<a>인증 링크</a>",
+	}
+
+	for _, tc := range []struct {
+		name        string
+		security    Security
+		implicitTLS bool
+		startTLS    bool
+		wantTLS     bool
+	}{
+		{"STARTTLS", SecurityStartTLS, false, true, true},
+		{"TLS", SecurityTLS, true, false, true},
+		{"암호화 없음", SecurityNone, false, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := newSMTPStandIn(t, serverTLS, tc.implicitTLS, tc.startTLS)
+
+			sender := NewSMTP(SMTPConfig{
+				Host:     "127.0.0.1",
+				Port:     server.port(),
+				Security: tc.security,
+				UserName: "no-reply@onthemat.com",
+				Password: "password",
+				From:     "온더맷 <no-reply@onthemat.com>",
+			}).(*smtpSender)
+			sender.tlsConfig = clientTLS
+
+			assert.NoError(sender.Send(msg))
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			assert.Equal(tc.wantTLS, server.tls)
+			assert.Equal("PLAIN "+base64.StdEncoding.EncodeToString([]byte("\x00no-reply@onthemat.com\x00password")), server.auth)
+			assert.Equal("FROM:<no-reply@onthemat.com>", server.from)
+			assert.Equal([]string{"TO:<user@onthemat.com>", "TO:<admin@onthemat.com>"}, server.rcpt)
+
+			received, err := mail.ReadMessage(bytes.NewReader(server.data))
+			assert.NoError(err)
+			subject, _ := new(mime.WordDecoder).DecodeHeader(received.Header.Get("Subject"))
+			assert.Equal(msg.Subject, subject)
+		})
+	}
+
+	t.Run("STARTTLS 를 지원하지 않는 서버", func(t *testing.T) {
+		server := newSMTPStandIn(t, serverTLS, false, false)
+		sender := NewSMTP(SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     server.port(),
+			Security: SecurityStartTLS,
+			From:     "no-reply@onthemat.com",
+		}).(*smtpSender)
+		sender.tlsConfig = clientTLS
+
+		assert.Error(t, sender.Send(msg))
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		assert.Nil(t, server.data)
+	})
+}
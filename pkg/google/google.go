@@ -1,11 +1,15 @@
 package google
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"net/url"
 	"strings"
 
 	"onthemat/internal/app/config"
+	"onthemat/pkg/auth/jwt"
+	"onthemat/pkg/oauth"
 
 	"github.com/valyala/fasthttp"
 )
@@ -15,6 +19,8 @@ type Google struct {
 	tokenUrl string
 	client   *fasthttp.Client
 	config   *config.Config
+	// id_token 서명 검증용 공개키
+	keySet *jwt.RemoteKeySet
 }
 
 func NewGoogle(config *config.Config) *Google {
@@ -23,27 +29,41 @@ func NewGoogle(config *config.Config) *Google {
 		tokenUrl: "https://oauth2.googleapis.com",
 		client:   &fasthttp.Client{},
 		config:   config,
+		keySet:   jwt.NewRemoteKeySet("https://www.googleapis.com/oauth2/v3/certs"),
 	}
 }
 
-func (g *Google) Authorize() string {
+func (g *Google) Name() string {
+	return "google"
+}
+
+func (g *Google) SupportsPKCE() bool {
+	return true
+}
+
+// codeChallenge 는 PKCE(S256) code_verifier 의 해시이다.
+func (g *Google) AuthorizeURL(state string, codeChallenge string) string {
 	var scope []string
 
+	scope = append(scope, "openid")
 	scope = append(scope, "email")
 	scope = append(scope, "profile")
 
-	scopeString := strings.Join(scope, " ")
-	redirectUrl := fmt.Sprintf("%s/o/oauth2/v2/auth?scope=%s&response_type=code&redirect_uri=%s&client_id=%s",
-		g.authUrl,
-		scopeString,
-		g.config.Oauth.GoogleRedirect,
-		g.config.Oauth.GoogleClientId,
-	)
-	return redirectUrl
+	query := url.Values{}
+	query.Set("scope", strings.Join(scope, " "))
+	query.Set("response_type", "code")
+	query.Set("redirect_uri", g.config.Oauth.GoogleRedirect)
+	query.Set("client_id", g.config.Oauth.GoogleClientId)
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	return g.authUrl + "/o/oauth2/v2/auth?" + query.Encode()
 }
 
-func (g *Google) GetToken(code string) *fasthttp.Response {
+func (g *Google) ExchangeCode(code string, state string, codeVerifier string) (*oauth.Token, error) {
 	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
 	req.SetRequestURI(g.tokenUrl + "/token")
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
@@ -52,17 +72,46 @@ func (g *Google) GetToken(code string) *fasthttp.Response {
 	req.URI().QueryArgs().Add("client_secret", g.config.Oauth.GoogleClientSecret)
 	req.URI().QueryArgs().Add("redirect_uri", g.config.Oauth.GoogleRedirect)
 	req.URI().QueryArgs().Add("grant_type", "authorization_code")
+	req.URI().QueryArgs().Add("code_verifier", codeVerifier)
 
 	req.Header.SetMethod(fasthttp.MethodPost)
 
 	resp := fasthttp.AcquireResponse()
-	err := g.client.Do(req, resp)
+	defer fasthttp.ReleaseResponse(resp)
+	if err := g.client.Do(req, resp); err != nil {
+		return nil, err
+	}
 
-	fasthttp.ReleaseRequest(req)
+	if resp.StatusCode() != fasthttp.StatusOK {
+		body := new(GetTokenErrorBody)
+		json.Unmarshal(resp.Body(), body)
+		if body.Error == "" {
+			return nil, fmt.Errorf("google: %d", resp.StatusCode())
+		}
+		return nil, errors.New(body.Error + body.ErrorDescription)
+	}
+
+	body := new(GetTokenSuccessBody)
+	if err := json.Unmarshal(resp.Body(), body); err != nil {
+		return nil, err
+	}
+	return &oauth.Token{AccessToken: body.AccessToken, IdToken: body.IdToken}, nil
+}
 
+// 유저 정보는 별도로 조회하지 않고 검증된 id_token 에서 꺼낸다.
+func (g *Google) FetchProfile(token *oauth.Token) (*oauth.Profile, error) {
+	claims, err := g.VerifyIdToken(token.IdToken)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERR Connection error: %v\n", err)
+		return nil, err
 	}
 
-	return resp
+	profile := &oauth.Profile{
+		Key:      claims.Subject,
+		Nickname: claims.Name,
+	}
+	// 인증되지 않은 이메일로 기존 계정과 연결되지 않도록 한다.
+	if claims.EmailVerified {
+		profile.Email = claims.Email
+	}
+	return profile, nil
 }
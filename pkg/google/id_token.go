@@ -0,0 +1,35 @@
+package google
+
+import (
+	"fmt"
+
+	jwtLib "github.com/golang-jwt/jwt/v4"
+)
+
+var issuers = []string{"https://accounts.google.com", "accounts.google.com"}
+
+type IdTokenClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	jwtLib.RegisteredClaims
+}
+
+// 토큰 응답의 id_token 을 구글 공개키(JWKS)로 검증한다. 서명, 만료, 발급자(iss), 대상(aud = 클라이언트 아이디)을 확인한다.
+func (g *Google) VerifyIdToken(idToken string) (*IdTokenClaims, error) {
+	claims := new(IdTokenClaims)
+	if _, err := jwtLib.ParseWithClaims(idToken, claims, g.keySet.Keyfunc); err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyAudience(g.config.Oauth.GoogleClientId, true) {
+		return nil, fmt.Errorf("google: id_token 의 aud 가 올바르지 않습니다")
+	}
+
+	for _, issuer := range issuers {
+		if claims.Issuer == issuer {
+			return claims, nil
+		}
+	}
+	return nil, fmt.Errorf("google: id_token 의 iss 가 올바르지 않습니다 (%s)", claims.Issuer)
+}
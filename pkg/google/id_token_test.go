@@ -0,0 +1,114 @@
+package google
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/config"
+	"onthemat/pkg/auth/jwt"
+	"onthemat/pkg/oauth"
+
+	jwtLib "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyIdToken(t *testing.T) {
+	assert := assert.New(t)
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	signer := jwt.NewJwt().WithPrivateKey("google-1", key).Init()
+
+	// 구글 JWKS 대신 로컬 서버
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(signer.JWKS())
+	}))
+	defer server.Close()
+
+	c := config.NewConfig()
+	c.Oauth.GoogleClientId = "client-id"
+	g := NewGoogle(c)
+	g.keySet = jwt.NewRemoteKeySet(server.URL)
+
+	newClaims := func() *IdTokenClaims {
+		return &IdTokenClaims{
+			Email:         "google@google.com",
+			EmailVerified: true,
+			Name:          "구글",
+			RegisteredClaims: jwtLib.RegisteredClaims{
+				Issuer:    "https://accounts.google.com",
+				Subject:   "googleSub",
+				Audience:  jwtLib.ClaimStrings{"client-id"},
+				ExpiresAt: jwtLib.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}
+	}
+
+	t.Run("성공", func(t *testing.T) {
+		idToken, _ := signer.GenerateToken(newClaims())
+
+		claims, err := g.VerifyIdToken(idToken)
+		assert.NoError(err)
+		assert.Equal("googleSub", claims.Subject)
+		assert.Equal("google@google.com", claims.Email)
+		assert.True(claims.EmailVerified)
+	})
+
+	t.Run("FetchProfile 은 인증된 이메일만 사용한다", func(t *testing.T) {
+		claims := newClaims()
+		claims.EmailVerified = false
+		idToken, _ := signer.GenerateToken(claims)
+
+		profile, err := g.FetchProfile(&oauth.Token{IdToken: idToken})
+		assert.NoError(err)
+		assert.Equal("googleSub", profile.Key)
+		assert.Equal("구글", profile.Nickname)
+		assert.Empty(profile.Email)
+	})
+
+	t.Run("다른 키로 서명한 토큰", func(t *testing.T) {
+		other, _ := rsa.GenerateKey(rand.Reader, 2048)
+		idToken, _ := jwt.NewJwt().WithPrivateKey("google-1", other).Init().GenerateToken(newClaims())
+
+		_, err := g.VerifyIdToken(idToken)
+		assert.Error(err)
+	})
+
+	t.Run("서명 없는 토큰", func(t *testing.T) {
+		idToken, _ := jwtLib.NewWithClaims(jwtLib.SigningMethodNone, newClaims()).SignedString(jwtLib.UnsafeAllowNoneSignatureType)
+
+		_, err := g.VerifyIdToken(idToken)
+		assert.Error(err)
+	})
+
+	t.Run("다른 클라이언트에게 발급된 토큰", func(t *testing.T) {
+		claims := newClaims()
+		claims.Audience = jwtLib.ClaimStrings{"other-client"}
+		idToken, _ := signer.GenerateToken(claims)
+
+		_, err := g.VerifyIdToken(idToken)
+		assert.Error(err)
+	})
+
+	t.Run("다른 발급자", func(t *testing.T) {
+		claims := newClaims()
+		claims.Issuer = "https://evil.example.com"
+		idToken, _ := signer.GenerateToken(claims)
+
+		_, err := g.VerifyIdToken(idToken)
+		assert.Error(err)
+	})
+
+	t.Run("만료된 토큰", func(t *testing.T) {
+		claims := newClaims()
+		claims.ExpiresAt = jwtLib.NewNumericDate(time.Now().Add(-time.Minute))
+		idToken, _ := signer.GenerateToken(claims)
+
+		_, err := g.VerifyIdToken(idToken)
+		assert.Error(err)
+	})
+}
@@ -13,9 +13,3 @@ type GetTokenSuccessBody struct {
 	TokenType    string `json:"token_type"`
 	IdToken      string `json:"id_token"`
 }
-
-type GetUserInfo struct {
-	Email    string `json:"email"`
-	Sub      string `json:"sub"`
-	Nickname string `json:"nickName"`
-}
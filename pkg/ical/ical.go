@@ -0,0 +1,136 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// RFC 5545 iCalendar 피드 작성
+// 일시는 모두 UTC(Z)로 쓰기 때문에 VTIMEZONE 은 포함하지 않는다.
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+
+	dateTimeFormat = "20060102T150405Z"
+
+	// 한 줄의 최대 길이 (octet)
+	maxLineLength = 75
+)
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []*Event
+}
+
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Status       string
+	Start        time.Time
+	End          time.Time
+	Stamp        time.Time
+	LastModified time.Time
+}
+
+func NewCalendar(prodId, name string) *Calendar {
+	return &Calendar{
+		ProdID: prodId,
+		Name:   name,
+		Events: make([]*Event, 0),
+	}
+}
+
+func (c *Calendar) AddEvent(e *Event) {
+	c.Events = append(c.Events, e)
+}
+
+func (c *Calendar) Bytes() []byte {
+	w := &writer{}
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", e.UID)
+		w.line("DTSTAMP", formatTime(e.Stamp))
+		w.line("DTSTART", formatTime(e.Start))
+		w.line("DTEND", formatTime(e.End))
+		w.line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			w.line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			w.line("LOCATION", escape(e.Location))
+		}
+		if e.Status != "" {
+			w.line("STATUS", e.Status)
+		}
+		if !e.LastModified.IsZero() {
+			w.line("LAST-MODIFIED", formatTime(e.LastModified))
+		}
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// TEXT 값의 특수문자 이스케이프 (RFC 5545 3.3.11)
+func escape(v string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(v)
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// 75 octet 을 넘는 줄은 접어서 쓴다. (RFC 5545 3.1) UTF-8 문자 중간에서는 자르지 않는다.
+func (w *writer) line(name, value string) {
+	l := name + ":" + value
+
+	limit := maxLineLength
+	for len(l) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(l[cut]) {
+			cut--
+		}
+		w.buf.WriteString(l[:cut])
+		w.buf.WriteString("\r\n ")
+		l = l[cut:]
+		// 이어지는 줄은 앞의 공백 1 octet 을 포함한다.
+		limit = maxLineLength - 1
+	}
+	w.buf.WriteString(l)
+	w.buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,42 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"onthemat/pkg/ical"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarBytes(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Seoul")
+
+	c := ical.NewCalendar("-//onthemat//calendar//KO", "대강")
+	c.AddEvent(&ical.Event{
+		UID:      "instead-1-schedule-2@onthemat",
+		Summary:  "대강, 하타 요가; 저녁반",
+		Location: strings.Repeat("서울특별시 강남구 ", 10),
+		Status:   ical.StatusConfirmed,
+		Start:    time.Date(2022, 12, 6, 19, 0, 0, 0, loc),
+		End:      time.Date(2022, 12, 6, 20, 0, 0, 0, loc),
+		Stamp:    time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	result := string(c.Bytes())
+
+	assert.True(t, strings.HasPrefix(result, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(result, "END:VCALENDAR\r\n"))
+	assert.Contains(t, result, "DTSTART:20221206T100000Z\r\n")
+	assert.Contains(t, result, "DTEND:20221206T110000Z\r\n")
+	assert.Contains(t, result, `SUMMARY:대강\, 하타 요가\; 저녁반`)
+
+	for _, l := range strings.Split(strings.TrimSuffix(result, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(l), 75)
+	}
+
+	// 접힌 줄을 펼치면 원래 값
+	unfolded := strings.ReplaceAll(result, "\r\n ", "")
+	assert.Contains(t, unfolded, "LOCATION:"+strings.Repeat("서울특별시 강남구 ", 10)+"\r\n")
+}
@@ -1,10 +1,14 @@
 package kakao
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"net/url"
+	"strconv"
 
 	"onthemat/internal/app/config"
+	"onthemat/pkg/oauth"
 
 	"github.com/valyala/fasthttp"
 )
@@ -25,67 +29,102 @@ func NewKakao(config *config.Config) *Kakao {
 	}
 }
 
-func (k *Kakao) Authorize() *fasthttp.Response {
-	req := fasthttp.AcquireRequest()
-	req.SetRequestURI(k.AuthUrl + "/oauth/authorize")
-	req.Header.Add("Content-Type", "text/html")
-	req.URI().QueryArgs().Add("client_id", k.config.Oauth.KaKaoClientId)
-	req.URI().QueryArgs().Add("redirect_uri", k.config.Oauth.KaKaoRedirect)
-	req.URI().QueryArgs().Add("response_type", "code")
-
-	req.Header.SetMethod(fasthttp.MethodGet)
-
-	resp := fasthttp.AcquireResponse()
-	err := k.client.Do(req, resp)
+func (k *Kakao) Name() string {
+	return "kakao"
+}
 
-	fasthttp.ReleaseRequest(req)
+// 카카오는 PKCE 를 지원하지 않아 state 만 사용한다.
+func (k *Kakao) SupportsPKCE() bool {
+	return false
+}
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERR Connection error: %v\n", err)
-	}
+// 카카오 로그인 페이지 주소
+func (k *Kakao) AuthorizeURL(state string, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("client_id", k.config.Oauth.KaKaoClientId)
+	query.Set("redirect_uri", k.config.Oauth.KaKaoRedirect)
+	query.Set("response_type", "code")
+	query.Set("state", state)
 
-	return resp
+	return k.AuthUrl + "/oauth/authorize?" + query.Encode()
 }
 
-func (k *Kakao) GetToken(code string) *fasthttp.Response {
+func (k *Kakao) ExchangeCode(code string, state string, codeVerifier string) (*oauth.Token, error) {
 	cnf := k.config.Oauth
 
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("client_id", cnf.KaKaoClientId)
+	form.Set("redirect_uri", cnf.KaKaoRedirect)
+	form.Set("code", code)
+
 	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
 	req.Header.SetMethod(fasthttp.MethodPost)
 	req.SetRequestURI(k.AuthUrl + "/oauth/token")
-
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
-
-	body := fmt.Sprintf("grant_type=authorization_code&client_id=%s&redirect_uri=%s&code=%s", cnf.KaKaoClientId, cnf.KaKaoRedirect, code)
-	req.SetBody([]byte(body))
+	req.SetBodyString(form.Encode())
 
 	resp := fasthttp.AcquireResponse()
-	err := k.client.Do(req, resp)
-	fasthttp.ReleaseRequest(req)
-
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERR Connection error: %v\n", err)
+	defer fasthttp.ReleaseResponse(resp)
+	if err := k.client.Do(req, resp); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp); err != nil {
+		return nil, err
 	}
 
-	return resp
+	body := new(GetTokenSuccessBody)
+	if err := json.Unmarshal(resp.Body(), body); err != nil {
+		return nil, err
+	}
+	return &oauth.Token{AccessToken: body.AccessToken}, nil
 }
 
-func (k *Kakao) GetUserInfo(accessToken string) *fasthttp.Response {
+func (k *Kakao) FetchProfile(token *oauth.Token) (*oauth.Profile, error) {
 	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
 	req.Header.SetMethod(fasthttp.MethodGet)
 	req.SetRequestURI(k.ApiUrl + "/v2/user/me")
 
-	authorizationValue := fmt.Sprintf("Bearer %s", accessToken)
+	authorizationValue := fmt.Sprintf("Bearer %s", token.AccessToken)
 	req.Header.Add("Authorization", authorizationValue)
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
 
 	resp := fasthttp.AcquireResponse()
-	err := k.client.Do(req, resp)
-	fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+	if err := k.client.Do(req, resp); err != nil {
+		return nil, err
+	}
+	if err := responseError(resp); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERR Connection error: %v\n", err)
+	body := new(GetUserInfoSuccessBody)
+	if err := json.Unmarshal(resp.Body(), body); err != nil {
+		return nil, err
 	}
 
-	return resp
+	profile := &oauth.Profile{
+		Key:      strconv.FormatUint(uint64(body.Id), 10),
+		Nickname: body.KakaoAccount.Profile.NickName,
+	}
+	// 인증되지 않은 이메일로 기존 계정과 연결되지 않도록 한다.
+	if body.KakaoAccount.Email != nil && body.KakaoAccount.IsEmailValid && body.KakaoAccount.IsEmailVerified {
+		profile.Email = *body.KakaoAccount.Email
+	}
+	return profile, nil
+}
+
+func responseError(resp *fasthttp.Response) error {
+	if resp.StatusCode() == fasthttp.StatusOK {
+		return nil
+	}
+
+	body := new(GetTokenErrorBody)
+	json.Unmarshal(resp.Body(), body)
+	if body.Error == "" && body.ErrorCode == "" {
+		return fmt.Errorf("kakao: %d", resp.StatusCode())
+	}
+	return errors.New(body.Error + body.ErrorCode)
 }
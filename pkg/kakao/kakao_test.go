@@ -10,7 +10,7 @@ import (
 func TestKakao(t *testing.T) {
 	c := config.NewConfig()
 	kaka := NewKakao(c)
-	d := kaka.Authorize()
+	d := kaka.AuthorizeURL("state", "")
 	fmt.Println(d)
 }
 
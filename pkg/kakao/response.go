@@ -17,7 +17,9 @@ type GetTokenSuccessBody struct {
 type GetUserInfoSuccessBody struct {
 	Id           uint `json:"id"`
 	KakaoAccount struct {
-		Email *string `json:"email"`
+		Email           *string `json:"email"`
+		IsEmailValid    bool    `json:"is_email_valid"`
+		IsEmailVerified bool    `json:"is_email_verified"`
 
 		Profile struct {
 			NickName string `json:"nickname"`
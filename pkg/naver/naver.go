@@ -1,10 +1,13 @@
 package naver
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"net/url"
 
 	"onthemat/internal/app/config"
+	"onthemat/pkg/oauth"
 
 	"github.com/valyala/fasthttp"
 )
@@ -25,56 +28,94 @@ func NewNaver(config *config.Config) *Naver {
 	}
 }
 
-func (n *Naver) Authorize() string {
-	redirectUrl := fmt.Sprintf("%s/authorize?response_type=code&redirect_uri=%s&client_id=%s&state=%s",
-		n.authUrl,
-		n.config.Oauth.NaverRedirect,
-		n.config.Oauth.NaverClientId,
-		"randomText",
-	)
-	return redirectUrl
+func (n *Naver) Name() string {
+	return "naver"
 }
 
-func (n *Naver) GetToken(code string) *fasthttp.Response {
+// 네이버는 PKCE 를 지원하지 않아 state 만 사용한다.
+func (n *Naver) SupportsPKCE() bool {
+	return false
+}
+
+func (n *Naver) AuthorizeURL(state string, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("redirect_uri", n.config.Oauth.NaverRedirect)
+	query.Set("client_id", n.config.Oauth.NaverClientId)
+	query.Set("state", state)
+
+	return n.authUrl + "/authorize?" + query.Encode()
+}
+
+func (n *Naver) ExchangeCode(code string, state string, codeVerifier string) (*oauth.Token, error) {
 	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
 	req.SetRequestURI(n.authUrl + "/token")
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	req.URI().QueryArgs().Add("grant_type", "authorization_code")
 	req.URI().QueryArgs().Add("client_id", n.config.Oauth.NaverClientId)
 	req.URI().QueryArgs().Add("client_secret", n.config.Oauth.NaverClientSecret)
 	req.URI().QueryArgs().Add("code", code)
-	req.URI().QueryArgs().Add("state", "randomText")
+	req.URI().QueryArgs().Add("state", state)
 
 	req.Header.SetMethod(fasthttp.MethodGet)
 
 	resp := fasthttp.AcquireResponse()
-	err := n.client.Do(req, resp)
-
-	fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+	if err := n.client.Do(req, resp); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERR Connection error: %v\n", err)
+	// 네이버는 실패해도 200 으로 응답하는 경우가 있다.
+	errBody := new(GetTokenErrorBody)
+	json.Unmarshal(resp.Body(), errBody)
+	if resp.StatusCode() != fasthttp.StatusOK || errBody.Error != "" {
+		return nil, responseError(resp.StatusCode(), errBody)
 	}
 
-	return resp
+	body := new(GetTokenSuccessBody)
+	if err := json.Unmarshal(resp.Body(), body); err != nil {
+		return nil, err
+	}
+	return &oauth.Token{AccessToken: body.AccessToken}, nil
 }
 
-func (k *Naver) GetUserInfo(accessToken string) *fasthttp.Response {
+func (n *Naver) FetchProfile(token *oauth.Token) (*oauth.Profile, error) {
 	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
 	req.Header.SetMethod(fasthttp.MethodGet)
-	req.SetRequestURI(k.openAPIUrl + "/v1/nid/me")
+	req.SetRequestURI(n.openAPIUrl + "/v1/nid/me")
 
-	authorizationValue := fmt.Sprintf("Bearer %s", accessToken)
+	authorizationValue := fmt.Sprintf("Bearer %s", token.AccessToken)
 	req.Header.Add("Authorization", authorizationValue)
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
 
 	resp := fasthttp.AcquireResponse()
-	err := k.client.Do(req, resp)
-	fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+	if err := n.client.Do(req, resp); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != fasthttp.StatusOK {
+		errBody := new(GetTokenErrorBody)
+		json.Unmarshal(resp.Body(), errBody)
+		return nil, responseError(resp.StatusCode(), errBody)
+	}
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERR Connection error: %v\n", err)
+	body := new(GetUserInfoSuccessBody)
+	if err := json.Unmarshal(resp.Body(), body); err != nil {
+		return nil, err
 	}
 
-	return resp
+	return &oauth.Profile{
+		Key:      body.Response.Id,
+		Email:    body.Response.Email,
+		Nickname: body.Response.NickName,
+	}, nil
+}
+
+func responseError(statusCode int, body *GetTokenErrorBody) error {
+	if body.Error == "" {
+		return fmt.Errorf("naver: %d", statusCode)
+	}
+	return errors.New(body.Error + body.ErrorDescription)
 }
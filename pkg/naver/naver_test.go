@@ -15,7 +15,7 @@ func TestNaver(t *testing.T) {
 	}
 
 	nM := NewNaver(c)
-	url := nM.Authorize()
+	url := nM.AuthorizeURL("state", "")
 	fmt.Println(url)
 	// nM.GetToken()
 }
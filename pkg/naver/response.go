@@ -18,3 +18,7 @@ type GetUserInfo struct {
 	NickName string `json:"nickname"`
 	Name     string `json:"name"`
 }
+
+type GetUserInfoSuccessBody struct {
+	Response GetUserInfo `json:"response"`
+}
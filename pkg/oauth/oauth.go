@@ -0,0 +1,78 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"sort"
+)
+
+// 인가 코드로 받은 토큰. 업체에 따라 둘 중 하나만 있을 수 있다.
+type Token struct {
+	AccessToken string
+	IdToken     string
+}
+
+// 소셜 로그인 업체에서 받은 유저 정보
+type Profile struct {
+	// 업체에서 발급한 유저 고유 아이디
+	Key string
+	// 업체가 확인한 이메일. 없거나 인증되지 않았으면 빈 문자열이다.
+	Email    string
+	Nickname string
+}
+
+// 소셜 로그인 업체. 업체를 추가할 때는 이 인터페이스를 구현해 Registry 에 등록한다.
+type SocialProvider interface {
+	// Registry 의 키. (kakao, google, naver, apple)
+	Name() string
+	// true 면 AuthorizeURL 에 code_challenge 를, ExchangeCode 에 code_verifier 를 넘긴다.
+	SupportsPKCE() bool
+	// 로그인 페이지 주소. PKCE 를 지원하지 않으면 codeChallenge 는 무시한다.
+	AuthorizeURL(state string, codeChallenge string) string
+	ExchangeCode(code string, state string, codeVerifier string) (*Token, error)
+	FetchProfile(token *Token) (*Profile, error)
+}
+
+type Registry struct {
+	providers map[string]SocialProvider
+}
+
+func NewRegistry(providers ...SocialProvider) *Registry {
+	r := &Registry{providers: make(map[string]SocialProvider, len(providers))}
+	for _, provider := range providers {
+		r.providers[provider.Name()] = provider
+	}
+	return r
+}
+
+func (r *Registry) Get(name string) (SocialProvider, bool) {
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// 등록된 업체 이름 (정렬)
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RFC 7636 code_verifier(43자)와 S256 code_challenge
+func NewCodeVerifier() (verifier string, challenge string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	verifier = base64.RawURLEncoding.EncodeToString(b)
+	challenge = CodeChallenge(verifier)
+	return
+}
+
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
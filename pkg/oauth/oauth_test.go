@@ -0,0 +1,37 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider struct {
+	SocialProvider
+	name string
+}
+
+func (f *fakeProvider) Name() string { return f.name }
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(&fakeProvider{name: "naver"}, &fakeProvider{name: "apple"}, &fakeProvider{name: "kakao"})
+
+	provider, ok := r.Get("apple")
+	assert.True(t, ok)
+	assert.Equal(t, "apple", provider.Name())
+
+	_, ok = r.Get("facebook")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"apple", "kakao", "naver"}, r.Names())
+}
+
+func TestCodeVerifier(t *testing.T) {
+	verifier, challenge, err := NewCodeVerifier()
+	assert.NoError(t, err)
+	assert.Len(t, verifier, 43)
+	assert.Equal(t, CodeChallenge(verifier), challenge)
+
+	// RFC 7636 Appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
@@ -0,0 +1,53 @@
+package sms
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+type Message struct {
+	To   string
+	Text string
+}
+
+// 문자를 보내지 않고 로그로 남기고 메모리에 쌓아둔다. (개발, 테스트용)
+type Fake struct {
+	mu       sync.Mutex
+	messages []Message
+	// 설정하면 Send 가 이 에러를 반환하고 문자를 쌓지 않는다.
+	Err error
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Send(ctx context.Context, to string, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	log.Printf("[sms] to %s: %s", to, text)
+	f.messages = append(f.messages, Message{To: to, Text: text})
+	return nil
+}
+
+// 지금까지 보낸 문자
+func (f *Fake) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Message(nil), f.messages...)
+}
+
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = nil
+	f.Err = nil
+}
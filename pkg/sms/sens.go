@@ -0,0 +1,103 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"onthemat/internal/app/config"
+
+	"github.com/valyala/fasthttp"
+)
+
+// 네이버 클라우드 플랫폼 SENS 문자 발송
+// https://api.ncloud-docs.com/docs/ko/ai-application-service-sens-smsv2
+type Sens struct {
+	apiUrl string
+	client *fasthttp.Client
+	config *config.Config
+	now    func() time.Time
+}
+
+func NewSens(config *config.Config) *Sens {
+	return &Sens{
+		apiUrl: "https://sens.apigw.ntruss.com",
+		client: &fasthttp.Client{ReadTimeout: 10 * time.Second, WriteTimeout: 10 * time.Second},
+		config: config,
+		now:    time.Now,
+	}
+}
+
+type sensMessage struct {
+	To string `json:"to"`
+}
+
+type sensRequestBody struct {
+	Type     string        `json:"type"`
+	From     string        `json:"from"`
+	Content  string        `json:"content"`
+	Messages []sensMessage `json:"messages"`
+}
+
+type sensResponseBody struct {
+	RequestId    string `json:"requestId"`
+	StatusCode   string `json:"statusCode"`
+	StatusName   string `json:"statusName"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+func (s *Sens) Send(ctx context.Context, to string, text string) error {
+	uri := "/sms/v2/services/" + s.config.Sms.SensServiceId + "/messages"
+	body, err := json.Marshal(sensRequestBody{
+		Type:     "SMS",
+		From:     s.config.Sms.From,
+		Content:  text,
+		Messages: []sensMessage{{To: to}},
+	})
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(s.now().UnixMilli(), 10)
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI(s.apiUrl + uri)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.Header.SetContentType("application/json; charset=utf-8")
+	req.Header.Set("x-ncp-apigw-timestamp", timestamp)
+	req.Header.Set("x-ncp-iam-access-key", s.config.Sms.SensAccessKey)
+	req.Header.Set("x-ncp-apigw-signature-v2", s.signature(fasthttp.MethodPost, uri, timestamp))
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	if deadline, ok := ctx.Deadline(); ok {
+		err = s.client.DoDeadline(req, resp, deadline)
+	} else {
+		err = s.client.Do(req, resp)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 접수되면 202 를 반환한다.
+	result := new(sensResponseBody)
+	json.Unmarshal(resp.Body(), result)
+	if resp.StatusCode() != fasthttp.StatusAccepted || result.StatusName != "success" {
+		return fmt.Errorf("sms: SENS 발송 실패 (%d) %s %s", resp.StatusCode(), result.StatusName, result.ErrorMessage)
+	}
+	return nil
+}
+
+// "{method} {uri}\n{timestamp}\n{accessKey}" 를 secret key 로 HMAC-SHA256 서명한다.
+func (s *Sens) signature(method string, uri string, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Sms.SensSecretKey))
+	mac.Write([]byte(method + " " + uri + "\n" + timestamp + "\n" + s.config.Sms.SensAccessKey))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
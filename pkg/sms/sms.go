@@ -0,0 +1,26 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+
+	"onthemat/internal/app/config"
+)
+
+// 문자를 보내는 방법. 운영은 업체(SENS), 개발과 테스트는 Fake 를 사용한다.
+type Sender interface {
+	// to 는 하이픈 없는 휴대폰 번호다. (01012345678)
+	Send(ctx context.Context, to string, text string) error
+}
+
+// 설정(SMS_PROVIDER)에 맞는 Sender 를 만든다.
+func NewSender(config *config.Config) (Sender, error) {
+	switch config.Sms.Provider {
+	case "", "fake":
+		return NewFake(), nil
+	case "sens":
+		return NewSens(config), nil
+	default:
+		return nil, fmt.Errorf("sms: 알 수 없는 provider 입니다 (%s)", config.Sms.Provider)
+	}
+}
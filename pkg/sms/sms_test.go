@@ -0,0 +1,112 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"onthemat/internal/app/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSens(t *testing.T) {
+	assert := assert.New(t)
+
+	c := config.NewConfig()
+	c.Sms.From = "0212345678"
+	c.Sms.SensServiceId = "ncp:sms:kr:1234:onthemat"
+	c.Sms.SensAccessKey = "accessKey"
+	c.Sms.SensSecretKey = "secretKey"
+
+	var status int
+	var received sensRequestBody
+	var header http.Header
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&received)
+
+		w.WriteHeader(status)
+		if status == http.StatusAccepted {
+			w.Write([]byte(`{"requestId":"RSSA-1","requestTime":"2026-10-18T12:00:00.000","statusCode":"202","statusName":"success"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"401","errorMessage":"Authentication Failed"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	sens := NewSens(c)
+	sens.apiUrl = server.URL
+	sens.now = func() time.Time { return now }
+
+	t.Run("발송 접수", func(t *testing.T) {
+		status = http.StatusAccepted
+		err := sens.Send(context.Background(), "01012345678", "[온더맷] 인증번호 [123456]")
+		assert.NoError(err)
+
+		uri := "/sms/v2/services/ncp:sms:kr:1234:onthemat/messages"
+		assert.Equal(uri, path)
+		assert.Equal(sensRequestBody{
+			Type:     "SMS",
+			From:     "0212345678",
+			Content:  "[온더맷] 인증번호 [123456]",
+			Messages: []sensMessage{{To: "01012345678"}},
+		}, received)
+
+		timestamp := "1792324800000"
+		assert.Equal(timestamp, header.Get("x-ncp-apigw-timestamp"))
+		assert.Equal("accessKey", header.Get("x-ncp-iam-access-key"))
+		mac := hmac.New(sha256.New, []byte("secretKey"))
+		mac.Write([]byte("POST " + uri + "\n" + timestamp + "\naccessKey"))
+		assert.Equal(base64.StdEncoding.EncodeToString(mac.Sum(nil)), header.Get("x-ncp-apigw-signature-v2"))
+	})
+
+	t.Run("인증 실패", func(t *testing.T) {
+		status = http.StatusUnauthorized
+		err := sens.Send(context.Background(), "01012345678", "인증번호")
+		assert.Error(err)
+	})
+}
+
+func TestFake(t *testing.T) {
+	assert := assert.New(t)
+	fake := NewFake()
+
+	assert.NoError(fake.Send(context.Background(), "01012345678", "인증번호 [123456]"))
+	assert.Equal([]Message{{To: "01012345678", Text: "인증번호 [123456]"}}, fake.Messages())
+
+	fake.Err = errors.New("발송 실패")
+	assert.Error(fake.Send(context.Background(), "01012345678", "인증번호"))
+	assert.Len(fake.Messages(), 1)
+
+	fake.Reset()
+	assert.Empty(fake.Messages())
+}
+
+func TestNewSender(t *testing.T) {
+	assert := assert.New(t)
+	c := config.NewConfig()
+
+	sender, err := NewSender(c)
+	assert.NoError(err)
+	assert.IsType(&Fake{}, sender)
+
+	c.Sms.Provider = "sens"
+	sender, err = NewSender(c)
+	assert.NoError(err)
+	assert.IsType(&Sens{}, sender)
+
+	c.Sms.Provider = "unknown"
+	_, err = NewSender(c)
+	assert.Error(err)
+}